@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/icco/etu/client"
+)
+
+// attachmentExts are the extensions picked up when a directory is given as
+// an attachment path.
+var attachmentExts = map[string][]string{
+	"image": {".png", ".jpg", ".jpeg", ".gif", ".webp", ".heic", ".heif", ".bmp", ".tif", ".tiff"},
+	"audio": {".mp3", ".m4a", ".aac", ".wav", ".ogg", ".oga", ".opus", ".flac", ".webm"},
+}
+
+// expandPaths replaces globs with the files they match and directories with
+// the kind of files they contain, keeping order and dropping duplicates.
+// Patterns that match nothing are kept so validation can report them.
+func expandPaths(paths []string, kind string) []string {
+	var out []string
+	seen := map[string]bool{}
+	add := func(p string) {
+		if !seen[p] {
+			seen[p] = true
+			out = append(out, p)
+		}
+	}
+	for _, p := range paths {
+		if strings.ContainsAny(p, "*?[") {
+			if matches, err := filepath.Glob(p); err == nil && len(matches) > 0 {
+				for _, m := range matches {
+					if info, err := os.Stat(m); err == nil && !info.IsDir() {
+						add(m)
+					}
+				}
+				continue
+			}
+		}
+		if info, err := os.Stat(p); err == nil && info.IsDir() {
+			entries, err := os.ReadDir(p)
+			if err == nil {
+				for _, e := range entries {
+					ext := strings.ToLower(filepath.Ext(e.Name()))
+					if !e.IsDir() && slices.Contains(attachmentExts[kind], ext) {
+						add(filepath.Join(p, e.Name()))
+					}
+				}
+				continue
+			}
+		}
+		add(p)
+	}
+	return out
+}
+
+// attachmentStatus is the outcome of checking one attachment path.
+type attachmentStatus struct {
+	path string
+	info *client.AttachmentInfo
+	err  error
+}
+
+// inspectAttachments expands and checks every path in a newline-separated
+// list, with the image options set on ctx.
+func inspectAttachments(ctx context.Context, input, kind string) []attachmentStatus {
+	c := cfg
+	if c == nil {
+		c = &client.Config{}
+	}
+	paths := expandPaths(parsePaths(input), kind)
+	statuses := make([]attachmentStatus, 0, len(paths))
+	for _, p := range paths {
+		info, err := c.InspectAttachment(ctx, p, kind)
+		statuses = append(statuses, attachmentStatus{path: p, info: info, err: err})
+	}
+	return statuses
+}
+
+// describeAttachment renders metadata such as "1920x1080 · image/png · 1.2 MB".
+func describeAttachment(info *client.AttachmentInfo) string {
+	var parts []string
+	if info.Width > 0 {
+		parts = append(parts, fmt.Sprintf("%dx%d", info.Width, info.Height))
+	}
+	if info.Duration > 0 {
+		parts = append(parts, formatClock(info.Duration.Round(time.Second)))
+	}
+	parts = append(parts, info.MIMEType, client.FormatBytes(info.Size))
+	if info.Note != "" {
+		parts = append(parts, info.Note)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// attachmentsDescription is the help text for an attachment field followed by
+// a line per attachment showing its metadata or what is wrong with it.
+func attachmentsDescription(ctx context.Context, help, input, kind string) string {
+	statuses := inspectAttachments(ctx, input, kind)
+	if len(statuses) == 0 {
+		return help
+	}
+	okStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	lines := []string{help}
+	for _, s := range statuses {
+		name := truncate(filepath.Base(s.path), 32)
+		if s.err != nil {
+			lines = append(lines, errStyle.Render("✗ "+name+": "+s.err.Error()))
+			continue
+		}
+		lines = append(lines, okStyle.Render("✓ ")+name+"  "+describeAttachment(s.info))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// validateAttachments returns a huh validator that rejects the field while
+// any of its attachments fails inspection.
+func validateAttachments(ctx context.Context, kind string) func(string) error {
+	return func(input string) error {
+		for _, s := range inspectAttachments(ctx, input, kind) {
+			if s.err != nil {
+				return fmt.Errorf("%s: %w", filepath.Base(s.path), s.err)
+			}
+		}
+		return nil
+	}
+}
+
+// keepSelected filters paths down to those in keep.
+func keepSelected(paths, keep []string) []string {
+	var out []string
+	for _, p := range paths {
+		if slices.Contains(keep, p) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/icco/etu/client"
+)
+
+func TestExpandPaths(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.png", "b.JPG", "notes.txt", "memo.mp3"} {
+		if err := os.WriteFile(filepath.Join(dir, name), pngHeader, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "sub.png"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	at := func(name string) string { return filepath.Join(dir, name) }
+
+	tests := []struct {
+		name  string
+		paths []string
+		kind  string
+		want  []string
+	}{
+		{"plain", []string{at("a.png")}, "image", []string{at("a.png")}},
+		{"directory", []string{dir}, "image", []string{at("a.png"), at("b.JPG")}},
+		{"directory audio", []string{dir}, "audio", []string{at("memo.mp3")}},
+		{"glob skips dirs", []string{at("*.png")}, "image", []string{at("a.png")}},
+		{"duplicates", []string{at("a.png"), at("*.png")}, "image", []string{at("a.png")}},
+		{"unmatched glob kept", []string{at("*.gif")}, "image", []string{at("*.gif")}},
+		{"missing kept", []string{at("gone.png")}, "image", []string{at("gone.png")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expandPaths(tt.paths, tt.kind); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expandPaths() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttachmentsDescription(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "shot.png")
+	if err := os.WriteFile(good, pngHeader, 0600); err != nil {
+		t.Fatal(err)
+	}
+	input := good + "\n" + filepath.Join(dir, "missing.png")
+
+	got := attachmentsDescription(context.Background(), "help", input, "image")
+	lines := strings.Split(got, "\n")
+	if len(lines) != 3 || lines[0] != "help" {
+		t.Fatalf("description = %q", got)
+	}
+	if !strings.Contains(lines[1], "shot.png") || !strings.Contains(lines[1], "image/png") {
+		t.Errorf("line for good file = %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "missing.png: no such file") {
+		t.Errorf("line for missing file = %q", lines[2])
+	}
+	if got := attachmentsDescription(context.Background(), "help", "", "image"); got != "help" {
+		t.Errorf("empty description = %q, want just the help", got)
+	}
+
+	validate := validateAttachments(context.Background(), "image")
+	if err := validate(good); err != nil {
+		t.Errorf("validate(good) = %v", err)
+	}
+	if err := validate(input); err == nil || !strings.Contains(err.Error(), "missing.png") {
+		t.Errorf("validate(input) = %v, want missing.png error", err)
+	}
+}
+
+func TestValidateAttachmentsImageOptions(t *testing.T) {
+	orig := cfg
+	t.Cleanup(func() { cfg = orig })
+	cfg = &client.Config{MaxMessageSize: 1024} // leaves no room for the file
+	big := filepath.Join(t.TempDir(), "big.png")
+	if err := os.WriteFile(big, append(pngHeader, make([]byte, 4096)...), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := validateAttachments(context.Background(), "image")(big); err == nil || !strings.Contains(err.Error(), "over the backend's") {
+		t.Errorf("validate without image options = %v, want size error", err)
+	}
+	ctx := client.WithImageOptions(context.Background(), 1024, 0)
+	if err := validateAttachments(ctx, "image")(big); err != nil {
+		t.Errorf("validate with --image-max-dimension = %v, want it to pass", err)
+	}
+	if got := attachmentsDescription(ctx, "help", big, "image"); !strings.Contains(got, "will be downscaled") {
+		t.Errorf("description = %q, want downscale note", got)
+	}
+}
+
+func TestKeepSelected(t *testing.T) {
+	got := keepSelected([]string{"/a", "/b", "/c"}, []string{"/c", "/a", "/x"})
+	if want := []string{"/a", "/c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("keepSelected() = %v, want %v", got, want)
+	}
+}
+
+func TestCommandCreateAttachmentDirectory(t *testing.T) {
+	srv := startFakeBackend(t)
+	dir := t.TempDir()
+	for _, name := range []string{"one.png", "two.png", "readme.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), pngHeader, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := runCLI(t, "screenshots", "create", "-i", dir); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	notes := srv.Notes()
+	if len(notes) != 1 || len(notes[0].GetImages()) != 2 {
+		t.Fatalf("notes = %v, want one note with two images", notes)
+	}
+}
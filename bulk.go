@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/icco/etu/client"
+)
+
+// bulkWorkers is how many entries a bulk action works on at once.
+const bulkWorkers = 4
+
+// bulkAction is something done to the marked entries in the list.
+type bulkAction int
+
+const (
+	bulkNone bulkAction = iota
+	bulkDelete
+	bulkExport
+	bulkAddTag
+	bulkRemoveTag
+	bulkCopy
+)
+
+// bulkVerbs describe a finished action, as in "Deleted 3 entries".
+var bulkVerbs = map[bulkAction]string{
+	bulkDelete:    "Deleted",
+	bulkExport:    "Exported",
+	bulkAddTag:    "Tagged",
+	bulkRemoveTag: "Untagged",
+	bulkCopy:      "Copied",
+}
+
+// bulkResult is what a bulk action did to one entry.
+type bulkResult struct {
+	post    *client.Post
+	updated *client.Post // the entry after the action, if it returns one
+	err     error
+}
+
+// runBulk calls fn on each post, at most workers at a time, and returns the
+// results in the order of posts.
+func runBulk(ctx context.Context, posts []*client.Post, workers int, fn func(context.Context, *client.Post) (*client.Post, error)) []bulkResult {
+	results := make([]bulkResult, len(posts))
+	sem := make(chan struct{}, max(workers, 1))
+	var wg sync.WaitGroup
+	for i, p := range posts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			updated, err := fn(ctx, p)
+			results[i] = bulkResult{post: p, updated: updated, err: err}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// bulkFunc is what action does to a single entry.
+func bulkFunc(j client.Journal, action bulkAction, tag string) func(context.Context, *client.Post) (*client.Post, error) {
+	switch action {
+	case bulkDelete:
+		return func(ctx context.Context, p *client.Post) (*client.Post, error) {
+			return nil, j.DeletePost(ctx, p.PageID)
+		}
+	case bulkAddTag, bulkRemoveTag:
+		return func(ctx context.Context, p *client.Post) (*client.Post, error) {
+			tags := slices.Clone(p.Tags)
+			if action == bulkAddTag && !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			} else if action == bulkRemoveTag {
+				tags = slices.DeleteFunc(tags, func(t string) bool { return t == tag })
+			}
+			if slices.Equal(tags, p.Tags) {
+				return p, nil
+			}
+			return j.SetPostTags(ctx, p.PageID, tags)
+		}
+	default:
+		// Export and copy need the full text of each entry.
+		return func(ctx context.Context, p *client.Post) (*client.Post, error) {
+			text, err := j.GetPostFullContent(ctx, p.PageID)
+			if err != nil {
+				return nil, err
+			}
+			full := *p
+			full.Text = text
+			return &full, nil
+		}
+	}
+}
+
+// bulkDoneMsg reports a finished bulk action. err is a failure after the
+// entries were fetched, such as writing the export.
+type bulkDoneMsg struct {
+	action  bulkAction
+	arg     string
+	results []bulkResult
+	err     error
+}
+
+// runBulkAction does action to posts in the background. arg is the tag to
+// add or remove, or the file to export to.
+func runBulkAction(ctx context.Context, j client.Journal, action bulkAction, arg string, posts []*client.Post) tea.Cmd {
+	return func() tea.Msg {
+		msg := bulkDoneMsg{action: action, arg: arg}
+		msg.results = runBulk(ctx, posts, bulkWorkers, bulkFunc(j, action, arg))
+		var fetched []*client.Post
+		for _, r := range msg.results {
+			if r.err == nil && r.updated != nil {
+				fetched = append(fetched, r.updated)
+			}
+		}
+		switch {
+		case len(fetched) == 0:
+		case action == bulkExport:
+			msg.err = exportPosts(arg, fetched, time.Now())
+		case action == bulkCopy:
+			texts := make([]string, len(fetched))
+			for i, p := range fetched {
+				texts[i] = strings.TrimSpace(p.Text)
+			}
+			msg.err = copyToClipboard(ctx, strings.Join(texts, "\n\n---\n\n"))
+		}
+		return msg
+	}
+}
+
+// exportPosts writes posts to path with the digest template, newest first,
+// as HTML if path ends in .html and as Markdown otherwise.
+func exportPosts(path string, posts []*client.Post, now time.Time) error {
+	// Search results come in order of relevance.
+	posts = slices.Clone(posts)
+	slices.SortStableFunc(posts, func(a, b *client.Post) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	format := "markdown"
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".html" || ext == ".htm" {
+		format = "html"
+	}
+	src, err := digestTemplate(format, "")
+	if err != nil {
+		return err
+	}
+	loc := displayTime.loc
+	from := startOfDay(posts[len(posts)-1].CreatedAt, loc)
+	to := startOfDay(posts[0].CreatedAt, loc).AddDate(0, 0, 1)
+	var buf bytes.Buffer
+	if err := renderDigest(&buf, format, src, newDigestData("Journal export", from, to, posts, now)); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("write export: %w", err)
+	}
+	return nil
+}
+
+// defaultExportPath is the file export suggests.
+func defaultExportPath(now time.Time) string {
+	return "etu-export-" + now.Format("2006-01-02") + ".md"
+}
+
+// bulkSummary reports how a bulk action went, listing each entry it failed on.
+func bulkSummary(msg bulkDoneMsg) string {
+	var failed []bulkResult
+	for _, r := range msg.results {
+		if r.err != nil {
+			failed = append(failed, r)
+		}
+	}
+	done := len(msg.results) - len(failed)
+	var b strings.Builder
+	b.WriteString(bulkVerbs[msg.action])
+	if len(failed) > 0 {
+		fmt.Fprintf(&b, " %d of", done)
+	}
+	fmt.Fprintf(&b, " %d %s", len(msg.results), plural(len(msg.results), "entry", "entries"))
+	switch msg.action {
+	case bulkExport:
+		fmt.Fprintf(&b, " to %s", msg.arg)
+	case bulkAddTag:
+		fmt.Fprintf(&b, " with %s", msg.arg)
+	case bulkRemoveTag:
+		fmt.Fprintf(&b, " removing %s", msg.arg)
+	case bulkCopy:
+		b.WriteString(" to the clipboard")
+	}
+	if msg.err != nil {
+		// Nothing was exported or copied after all.
+		what := "Export"
+		if msg.action == bulkCopy {
+			what = "Copy"
+		}
+		b.Reset()
+		fmt.Fprintf(&b, "%s failed: %v", what, msg.err)
+	}
+	if len(failed) == 0 {
+		b.WriteString(".")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "; %d failed:", len(failed))
+	for _, r := range failed {
+		fmt.Fprintf(&b, "\n  %s  %s: %v", formatTime(r.post.CreatedAt), truncate(firstLine(r.post.Text), 40), r.err)
+	}
+	return b.String()
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/icco/etu/client"
+	"github.com/icco/etu/client/fake"
+)
+
+func TestRunBulk(t *testing.T) {
+	var posts []*client.Post
+	for i := range 10 {
+		posts = append(posts, &client.Post{PageID: string(rune('a' + i))})
+	}
+	var running, peak atomic.Int32
+	results := runBulk(context.Background(), posts, 3, func(_ context.Context, p *client.Post) (*client.Post, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		if p.PageID == "c" {
+			return nil, errors.New("boom")
+		}
+		return p, nil
+	})
+	if peak.Load() > 3 {
+		t.Errorf("%d calls ran at once, want at most 3", peak.Load())
+	}
+	for i, r := range results {
+		if r.post != posts[i] {
+			t.Fatalf("result %d is for %s, want results in order", i, r.post.PageID)
+		}
+		if (r.err != nil) != (r.post.PageID == "c") {
+			t.Errorf("result %s err = %v", r.post.PageID, r.err)
+		}
+	}
+
+	summary := bulkSummary(bulkDoneMsg{action: bulkDelete, results: results})
+	if !strings.HasPrefix(summary, "Deleted 9 of 10 entries; 1 failed:\n  ") || !strings.HasSuffix(summary, ": boom") {
+		t.Errorf("summary = %q", summary)
+	}
+	if got := bulkSummary(bulkDoneMsg{action: bulkAddTag, arg: "work", results: results[:2]}); got != "Tagged 2 entries with work." {
+		t.Errorf("summary = %q", got)
+	}
+}
+
+// loadedList is a postListModel with bulk actions over j's posts.
+func loadedList(j client.Journal) postListModel {
+	m := newPostListModel(context.Background(), j, 25, "test", true)
+	m.enableBulk()
+	updated, _ := m.Update(loadPosts(context.Background(), j, m.count, m.query)())
+	return updated.(postListModel)
+}
+
+// finish runs the bulk action cmd started and feeds its result back.
+func finish(t *testing.T, m postListModel, cmd tea.Cmd) postListModel {
+	t.Helper()
+	if !m.running || cmd == nil {
+		t.Fatal("no bulk action running")
+	}
+	for _, msg := range cmd().(tea.BatchMsg) {
+		if done, ok := msg().(bulkDoneMsg); ok {
+			updated, _ := m.Update(done)
+			return updated.(postListModel)
+		}
+	}
+	t.Fatal("bulk action didn't finish")
+	return m
+}
+
+func TestPostListModelBulkDelete(t *testing.T) {
+	now := time.Now()
+	j := fake.NewJournal(
+		&client.Post{Text: "one", CreatedAt: now.Add(-3 * time.Hour)},
+		&client.Post{Text: "two", CreatedAt: now.Add(-2 * time.Hour)},
+		&client.Post{Text: "three", CreatedAt: now.Add(-time.Hour)},
+	)
+	m := loadedList(j)
+
+	// Mark three and one; space moves down after marking.
+	m = pressKey(t, m, tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	m = pressKey(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	m = pressKey(t, m, tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	if len(m.marked) != 2 || !strings.HasSuffix(m.list.Title, "· 2 marked") {
+		t.Fatalf("marked = %v, title %q", m.marked, m.list.Title)
+	}
+	m = pressKey(t, m, runes("d"))
+	if !strings.Contains(m.View(), "Delete 2 entries?") {
+		t.Errorf("view = %q", m.View())
+	}
+	updated, cmd := m.Update(runes("y"))
+	m = finish(t, updated.(postListModel), cmd)
+
+	if posts := j.Posts(); len(posts) != 1 || posts[0].Text != "two" {
+		t.Errorf("posts left = %v, want only two", posts)
+	}
+	if len(m.posts) != 1 || len(m.marked) != 0 || m.status != "Deleted 2 entries." {
+		t.Errorf("after delete: posts %v, marked %v, status %q", m.posts, m.marked, m.status)
+	}
+}
+
+func TestPostListModelBulkTagAndExport(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	now := time.Now()
+	j := fake.NewJournal(
+		&client.Post{Text: "one", CreatedAt: now.Add(-2 * time.Hour), Tags: []string{"work"}},
+		&client.Post{Text: "two", CreatedAt: now.Add(-time.Hour)},
+	)
+	m := loadedList(j)
+
+	// * marks everything, and again unmarks it.
+	if m = pressKey(t, m, runes("*")); len(m.marked) != 2 {
+		t.Fatalf("marked = %v after *", m.marked)
+	}
+	if m = pressKey(t, m, runes("*")); len(m.marked) != 0 {
+		t.Fatalf("marked = %v after * twice", m.marked)
+	}
+	m = pressKey(t, m, runes("*"))
+
+	m = pressKey(t, m, runes("+"))
+	m.input.SetValue("#idea")
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = finish(t, updated.(postListModel), cmd)
+	for _, p := range j.Posts() {
+		if !reflect.DeepEqual(p.Tags, []string{"idea"}) && !reflect.DeepEqual(p.Tags, []string{"work", "idea"}) {
+			t.Errorf("%s tags = %v", p.Text, p.Tags)
+		}
+	}
+	if m.posts[1].Tags[1] != "idea" {
+		t.Errorf("list entry tags = %v, want the update shown", m.posts[1].Tags)
+	}
+
+	path := filepath.Join(t.TempDir(), "out.md")
+	m = pressKey(t, m, runes("e"))
+	m.input.SetValue(path)
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = finish(t, updated.(postListModel), cmd)
+	out, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(out), "# Journal export") || !strings.Contains(string(out), "one") || !strings.Contains(string(out), "two") {
+		t.Errorf("export =\n%s", out)
+	}
+	if m.status != "Exported 2 entries to "+path+"." {
+		t.Errorf("status = %q", m.status)
+	}
+}
+
+func TestPostListModelBulkCopyFailure(t *testing.T) {
+	fakeTools(t, nil, nil)
+	j := fake.NewJournal(&client.Post{Text: "one", CreatedAt: time.Now()})
+	m := loadedList(j)
+
+	updated, cmd := m.Update(runes("y"))
+	m = finish(t, updated.(postListModel), cmd)
+	if !strings.HasPrefix(m.status, "Copy failed: no clipboard tool found") {
+		t.Errorf("status = %q", m.status)
+	}
+}
+
+func pressKey(t *testing.T, m postListModel, msg tea.KeyMsg) postListModel {
+	t.Helper()
+	updated, _ := m.Update(msg)
+	return updated.(postListModel)
+}
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestExportPostsSortsByDate(t *testing.T) {
+	useDisplayTime(t, timeFormat{loc: time.UTC, layout: "2006-01-02 15:04", clock: "15:04", now: time.Now})
+	at := func(d int) time.Time { return time.Date(2026, 3, d, 9, 0, 0, 0, time.UTC) }
+	// In order of relevance, as search returns them.
+	posts := []*client.Post{
+		{Text: "middle", CreatedAt: at(12)},
+		{Text: "newest", CreatedAt: at(14)},
+		{Text: "oldest", CreatedAt: at(10)},
+	}
+	path := filepath.Join(t.TempDir(), "out.md")
+	if err := exportPosts(path, posts, at(15)); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out := string(data); !strings.Contains(out, "Tue Mar 10 2026 to Sat Mar 14 2026") || !strings.Contains(out, "newest") {
+		t.Errorf("export =\n%s", out)
+	}
+	if posts[0].Text != "middle" {
+		t.Error("exportPosts reordered its argument")
+	}
+}
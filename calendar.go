@@ -0,0 +1,373 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/icco/etu/client"
+	"github.com/spf13/cobra"
+)
+
+var calendarCmd = &cobra.Command{
+	Use:     "calendar",
+	Aliases: []string{"cal"},
+	Short:   "Browse journal entries by date on a month calendar.",
+	Args:    cobra.NoArgs,
+	RunE:    showCalendar,
+}
+
+// calendarShades are the cell styles from no entries to many.
+var calendarShades = []lipgloss.Style{
+	lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
+	lipgloss.NewStyle().Background(lipgloss.Color("22")).Foreground(lipgloss.Color("255")),
+	lipgloss.NewStyle().Background(lipgloss.Color("28")).Foreground(lipgloss.Color("255")),
+	lipgloss.NewStyle().Background(lipgloss.Color("34")).Foreground(lipgloss.Color("0")),
+	lipgloss.NewStyle().Background(lipgloss.Color("40")).Foreground(lipgloss.Color("0")),
+}
+
+var calendarCursorStyle = lipgloss.NewStyle().Bold(true).Reverse(true)
+
+// shade picks the calendarShades index for a day with n entries.
+func shade(n int) int {
+	switch {
+	case n <= 0:
+		return 0
+	case n == 1:
+		return 1
+	case n <= 3:
+		return 2
+	case n <= 5:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// startOfDay is midnight of t's day in loc.
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// startOfMonth is midnight on the first of t's month, in t's timezone.
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// addMonths moves day by n months, keeping the day of the month where it
+// exists and otherwise using the month's last day.
+func addMonths(day time.Time, n int) time.Time {
+	first := startOfMonth(day).AddDate(0, n, 0)
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(day.Day(), last)-1)
+}
+
+// weekdayColumn is the grid column of t, with weeks starting on Monday.
+func weekdayColumn(t time.Time) int {
+	return (int(t.Weekday()) + 6) % 7
+}
+
+type calendarLoadedMsg struct {
+	posts    []*client.Post
+	complete time.Time
+	err      error
+}
+
+func loadCalendar(ctx context.Context, j client.Journal, from time.Time) tea.Cmd {
+	return func() tea.Msg {
+		posts, complete, err := client.ListPostsBetween(ctx, j, from, time.Time{})
+		return calendarLoadedMsg{posts: posts, complete: complete, err: err}
+	}
+}
+
+// calendarModel is a month grid shaded by how many entries each day has.
+// Entries are loaded back to the earliest month visited, so flipping to a
+// month already seen is instant; cached counts fill in until then.
+type calendarModel struct {
+	ctx     context.Context
+	journal client.Journal
+	loc     *time.Location
+	now     func() time.Time
+
+	cursor   time.Time      // the selected day, at midnight
+	counts   map[string]int // entries per "2006-01-02"
+	posts    []*client.Post // loaded entries, newest first
+	complete time.Time      // posts hold every entry since this time
+	loaded   bool
+	fetching bool
+	opening  bool // open the cursor's day once its entries are loaded
+	err      error
+
+	selected []*client.Post
+	quitting bool
+}
+
+func newCalendarModel(ctx context.Context, j client.Journal, loc *time.Location, month time.Time, cached *client.DayCounts) calendarModel {
+	m := calendarModel{
+		ctx:     ctx,
+		journal: j,
+		loc:     loc,
+		now:     time.Now,
+		counts:  map[string]int{},
+		// Init loads the first month.
+		fetching: true,
+	}
+	if cached != nil && cached.Zone == loc.String() {
+		// Days before From weren't fully counted.
+		from := cached.From.In(loc).Format("2006-01-02")
+		for day, n := range cached.Counts {
+			if day >= from {
+				m.counts[day] = n
+			}
+		}
+	}
+	today := startOfDay(m.now(), loc)
+	m.cursor = today
+	if !month.IsZero() && !startOfMonth(month.In(loc)).Equal(startOfMonth(today)) {
+		m.cursor = startOfMonth(month.In(loc))
+	}
+	return m
+}
+
+func (m calendarModel) Init() tea.Cmd {
+	return loadCalendar(m.ctx, m.journal, startOfMonth(m.cursor))
+}
+
+// covered reports whether the loaded entries include all of day.
+func (m calendarModel) covered(day time.Time) bool {
+	return m.loaded && !day.Before(m.complete)
+}
+
+// ensureLoaded starts loading entries back to the cursor's month if needed.
+func (m *calendarModel) ensureLoaded() tea.Cmd {
+	if m.fetching || m.covered(startOfMonth(m.cursor)) {
+		return nil
+	}
+	m.fetching = true
+	return loadCalendar(m.ctx, m.journal, startOfMonth(m.cursor))
+}
+
+// dayPosts returns the loaded entries of day.
+func (m calendarModel) dayPosts(day time.Time) []*client.Post {
+	return client.PostsBetween(m.posts, day, day.AddDate(0, 0, 1))
+}
+
+// open selects the cursor's entries and quits, or waits for them to load.
+func (m calendarModel) open() (tea.Model, tea.Cmd) {
+	if !m.covered(m.cursor) {
+		m.opening = true
+		if !m.fetching {
+			m.fetching = true
+			return m, loadCalendar(m.ctx, m.journal, startOfMonth(m.cursor))
+		}
+		return m, nil
+	}
+	posts := m.dayPosts(m.cursor)
+	if len(posts) == 0 {
+		return m, nil
+	}
+	m.selected = posts
+	m.quitting = true
+	return m, tea.Quit
+}
+
+func (m calendarModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case calendarLoadedMsg:
+		m.fetching = false
+		if msg.err != nil {
+			m.err = msg.err
+			m.opening = false
+			return m, nil
+		}
+		m.err = nil
+		m.posts, m.complete, m.loaded = msg.posts, msg.complete, true
+		fresh := client.CountByDay(m.posts, m.loc)
+		// Keep cached counts for days before the loaded range.
+		cutoff := m.complete.In(m.loc).Format("2006-01-02")
+		for day, n := range m.counts {
+			if day < cutoff {
+				fresh[day] = n
+			}
+		}
+		m.counts = fresh
+		if m.opening {
+			m.opening = false
+			return m.open()
+		}
+		return m, m.ensureLoaded()
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			m.quitting = true
+			return m, tea.Quit
+		case "left", "h":
+			m.cursor = m.cursor.AddDate(0, 0, -1)
+		case "right", "l":
+			m.cursor = m.cursor.AddDate(0, 0, 1)
+		case "up", "k":
+			m.cursor = m.cursor.AddDate(0, 0, -7)
+		case "down", "j":
+			m.cursor = m.cursor.AddDate(0, 0, 7)
+		case "[", "pgup", "p":
+			m.cursor = addMonths(m.cursor, -1)
+		case "]", "pgdown", "n":
+			m.cursor = addMonths(m.cursor, 1)
+		case "t":
+			m.cursor = startOfDay(m.now(), m.loc)
+		case "enter":
+			return m.open()
+		}
+		return m, m.ensureLoaded()
+	}
+	return m, nil
+}
+
+// monthTotal sums the counts of month's days.
+func monthTotal(counts map[string]int, month time.Time) int {
+	total := 0
+	prefix := month.Format("2006-01-")
+	for day, n := range counts {
+		if strings.HasPrefix(day, prefix) {
+			total += n
+		}
+	}
+	return total
+}
+
+func (m calendarModel) View() string {
+	if m.quitting {
+		return ""
+	}
+	month := startOfMonth(m.cursor)
+	today := startOfDay(m.now(), m.loc)
+
+	var s strings.Builder
+	total := monthTotal(m.counts, month)
+	s.WriteString(timelineHeaderStyle.Render(month.Format("January 2006")))
+	s.WriteString(timelineGapStyle.Render(fmt.Sprintf("  %d %s", total, plural(total, "entry", "entries"))))
+	s.WriteString("\n\n Mo  Tu  We  Th  Fr  Sa  Su\n")
+	s.WriteString(strings.Repeat("    ", weekdayColumn(month)))
+	for day := month; day.Month() == month.Month(); day = day.AddDate(0, 0, 1) {
+		n := m.counts[day.Format("2006-01-02")]
+		cell := fmt.Sprintf(" %2d ", day.Day())
+		style := calendarShades[shade(n)]
+		if day.Equal(today) {
+			style = style.Underline(true)
+		}
+		if day.Equal(m.cursor) {
+			style = calendarCursorStyle.Inherit(style)
+		}
+		s.WriteString(style.Render(cell))
+		if weekdayColumn(day) == 6 {
+			s.WriteString("\n")
+		}
+	}
+	s.WriteString("\n\n")
+
+	n := m.counts[m.cursor.Format("2006-01-02")]
+	s.WriteString(fmt.Sprintf("%s: %d %s", m.cursor.Format("Mon Jan 2"), n, plural(n, "entry", "entries")))
+	switch {
+	case m.err != nil:
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("  Error: " + m.err.Error()))
+	case m.fetching:
+		s.WriteString(timelineGapStyle.Render("  updating..."))
+	}
+	s.WriteString("\n\n")
+	legend := make([]string, len(calendarShades))
+	for i, style := range calendarShades {
+		legend[i] = style.Render("  ")
+	}
+	s.WriteString(timelineHelpStyle.Render("less ") + strings.Join(legend, "") + timelineHelpStyle.Render(" more"))
+	s.WriteString("\n")
+	s.WriteString(timelineHelpStyle.Render("arrows move · [/] month · t today · enter open · q quit"))
+	return docStyle.Render(s.String())
+}
+
+// printCalendar writes month as a plain grid followed by each day's count.
+func printCalendar(w io.Writer, month time.Time, counts map[string]int) {
+	fmt.Fprintf(w, "%s\n Mo Tu We Th Fr Sa Su\n", month.Format("January 2006"))
+	fmt.Fprint(w, strings.Repeat("   ", weekdayColumn(month)))
+	var days []string
+	for day := month; day.Month() == month.Month(); day = day.AddDate(0, 0, 1) {
+		fmt.Fprintf(w, " %2d", day.Day())
+		if weekdayColumn(day) == 6 {
+			fmt.Fprintln(w)
+		}
+		if key := day.Format("2006-01-02"); counts[key] > 0 {
+			days = append(days, key)
+		}
+	}
+	fmt.Fprintln(w)
+	sort.Strings(days)
+	if len(days) > 0 {
+		fmt.Fprintln(w)
+	}
+	for _, day := range days {
+		fmt.Fprintf(w, "%s  %d\n", day, counts[day])
+	}
+}
+
+func showCalendar(cmd *cobra.Command, _ []string) error {
+	loc := displayTime.loc
+	var month time.Time
+	if s, _ := cmd.Flags().GetString("month"); s != "" {
+		t, err := time.ParseInLocation("2006-01", s, loc)
+		if err != nil {
+			return fmt.Errorf("invalid --month %q: use YYYY-MM", s)
+		}
+		month = t
+	}
+
+	if !isInteractive(cmd.OutOrStdout()) {
+		if month.IsZero() {
+			month = startOfMonth(time.Now().In(loc))
+		}
+		posts, _, err := client.ListPostsBetween(cmd.Context(), journal, month, month.AddDate(0, 1, 0))
+		if err != nil {
+			return err
+		}
+		printCalendar(cmd.OutOrStdout(), month, client.CountByDay(posts, loc))
+		return nil
+	}
+
+	cached, err := client.LoadDayCounts()
+	if err != nil {
+		log.Printf("etu: reading calendar cache: %v", err)
+	}
+	final, err := tea.NewProgram(newCalendarModel(cmd.Context(), journal, loc, month, cached), tea.WithAltScreen()).Run()
+	if err != nil {
+		return err
+	}
+	m := final.(calendarModel)
+	if m.loaded {
+		// Save only what was loaded now, so days kept from the old cache
+		// don't get a fresh Saved time and outlive its expiry.
+		counts := &client.DayCounts{Saved: time.Now(), Zone: loc.String(), From: m.complete, Counts: client.CountByDay(m.posts, loc)}
+		if err := client.SaveDayCounts(counts); err != nil {
+			log.Printf("etu: writing calendar cache: %v", err)
+		}
+	}
+	if len(m.selected) == 0 {
+		return nil
+	}
+
+	list := newPostListModel(m.ctx, journal, len(m.selected), m.cursor.Format("Monday, Jan 2 2006"), false)
+	updated, _ := list.Update(postsLoadedMsg{posts: m.selected})
+	final, err = tea.NewProgram(updated, tea.WithAltScreen()).Run()
+	if err != nil {
+		return err
+	}
+	if selected := final.(postListModel).selected; selected != nil {
+		return displayPost(cmd, selected)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/icco/etu/client"
+	"github.com/icco/etu/client/fake"
+)
+
+func TestAddMonths(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	for _, tc := range []struct {
+		from time.Time
+		n    int
+		want time.Time
+	}{
+		{day(2026, 1, 31), 1, day(2026, 2, 28)},
+		{day(2026, 3, 15), -1, day(2026, 2, 15)},
+		{day(2026, 1, 10), -1, day(2025, 12, 10)},
+		{day(2024, 3, 31), -1, day(2024, 2, 29)},
+	} {
+		if got := addMonths(tc.from, tc.n); !got.Equal(tc.want) {
+			t.Errorf("addMonths(%v, %d) = %v, want %v", tc.from, tc.n, got, tc.want)
+		}
+	}
+}
+
+func TestShade(t *testing.T) {
+	for n, want := range map[int]int{0: 0, 1: 1, 3: 2, 5: 3, 12: 4} {
+		if got := shade(n); got != want {
+			t.Errorf("shade(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
+
+// calendarKey sends one key to m, completing any load it starts.
+func calendarKey(t *testing.T, m calendarModel, key tea.KeyMsg) (calendarModel, tea.Cmd) {
+	t.Helper()
+	updated, cmd := m.Update(key)
+	m = updated.(calendarModel)
+	for m.fetching && cmd != nil {
+		updated, cmd = m.Update(cmd())
+		m = updated.(calendarModel)
+	}
+	return m, cmd
+}
+
+func TestCalendarModel(t *testing.T) {
+	now := time.Now()
+	thisMonth := startOfMonth(startOfDay(now, time.Local))
+	lastMonth := thisMonth.AddDate(0, -1, 0)
+	j := fake.NewJournal(
+		&client.Post{Text: "old", CreatedAt: lastMonth.Add(10 * time.Hour)},
+		&client.Post{Text: "old too", CreatedAt: lastMonth.Add(11 * time.Hour)},
+		&client.Post{Text: "new", CreatedAt: thisMonth.Add(9 * time.Hour)},
+	)
+	cached := &client.DayCounts{Zone: time.Local.String(), Counts: map[string]int{lastMonth.Format("2006-01-02"): 7}}
+
+	m := newCalendarModel(context.Background(), j, time.Local, time.Time{}, cached)
+	if got := m.counts[lastMonth.Format("2006-01-02")]; got != 7 {
+		t.Errorf("cached count = %d, want 7 before loading", got)
+	}
+	// Days the cache didn't fully count are left out.
+	partial := &client.DayCounts{Zone: cached.Zone, From: thisMonth, Counts: cached.Counts}
+	if got := newCalendarModel(context.Background(), j, time.Local, time.Time{}, partial).counts; len(got) != 0 {
+		t.Errorf("counts from before From = %v, want none", got)
+	}
+	updated, _ := m.Update(m.Init()())
+	m = updated.(calendarModel)
+	if m.fetching || !m.loaded || m.counts[thisMonth.Format("2006-01-02")] != 1 {
+		t.Fatalf("after load: fetching=%v loaded=%v counts=%v", m.fetching, m.loaded, m.counts)
+	}
+	if got := m.counts[lastMonth.Format("2006-01-02")]; got != 7 {
+		t.Errorf("last month's count = %d, want the cached 7 until it is loaded", got)
+	}
+
+	m, _ = calendarKey(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("[")})
+	if got := m.counts[lastMonth.Format("2006-01-02")]; got != 2 {
+		t.Errorf("last month's count = %d, want 2 once loaded", got)
+	}
+	m.cursor = lastMonth
+	m, cmd := calendarKey(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.selected) != 2 || cmd == nil {
+		t.Fatalf("selected %d entries, want last month's 2", len(m.selected))
+	}
+	if !strings.Contains(m.selected[0].Text, "old") {
+		t.Errorf("selected %q", m.selected[0].Text)
+	}
+}
+
+func TestCalendarModelEmptyDay(t *testing.T) {
+	j := fake.NewJournal()
+	m := newCalendarModel(context.Background(), j, time.UTC, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), nil)
+	if got := m.cursor.Format("2006-01-02"); got != "2026-02-01" {
+		t.Errorf("cursor = %s, want the first of the given month", got)
+	}
+	updated, _ := m.Update(m.Init()())
+	m = updated.(calendarModel)
+	m, cmd := calendarKey(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.selected != nil || cmd != nil {
+		t.Error("enter on a day without entries should do nothing")
+	}
+	m, _ = calendarKey(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	if got := m.cursor.Format("2006-01-02"); got != "2026-02-08" {
+		t.Errorf("after down, cursor = %s, want a week later", got)
+	}
+}
+
+func TestCommandCalendarPlain(t *testing.T) {
+	srv := startFakeBackend(t)
+	useDisplayTime(t, timeFormat{loc: time.UTC, layout: "2006-01-02 15:04", clock: "15:04", now: time.Now})
+	srv.AddNote("a", time.Date(2026, 2, 3, 9, 0, 0, 0, time.UTC))
+	srv.AddNote("b", time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC))
+	srv.AddNote("c", time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC))
+
+	out, err := runCLI(t, "", "calendar", "--month", "2026-02", "--timezone", "UTC")
+	if err != nil {
+		t.Fatalf("calendar: %v", err)
+	}
+	for _, want := range []string{"February 2026", strings.Repeat(" ", 18) + "  1\n  2  3", "2026-02-03  2"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "2026-03-01") {
+		t.Errorf("output includes March:\n%s", out)
+	}
+}
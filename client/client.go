@@ -13,6 +13,8 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -33,17 +35,66 @@ type Post struct {
 type Config struct {
 	APIKey     string
 	GRPCTarget string
-	grpc       *grpcClients
+
+	// CAFile is a PEM bundle used instead of the system roots to verify the server.
+	CAFile string
+	// CertFile and KeyFile are a PEM client certificate and key for mTLS.
+	CertFile string
+	KeyFile  string
+	// ServerName overrides the name checked against the server certificate.
+	ServerName string
+	// Insecure dials without TLS. Only allowed for loopback and unix socket targets.
+	Insecure bool
+
+	// Timeout bounds each gRPC attempt that has no earlier deadline; zero means 15s.
+	Timeout time.Duration
+	// MaxRetries is how often idempotent calls are retried; zero means 3, negative disables.
+	MaxRetries int
+
+	// MaxMessageSize is the largest request the backend accepts, in bytes; zero means 4 MiB.
+	MaxMessageSize int
+	// ImageMaxDimension downscales attached images so their longer side fits; zero keeps size.
+	ImageMaxDimension int
+	// ImageQuality is the JPEG quality (1-100) for re-encoded images; zero means 85.
+	ImageQuality int
+
+	// ScreenshotCommand is a shell command that captures a screenshot as PNG on
+	// stdout, or into the file named by a {file} placeholder.
+	ScreenshotCommand string
+
+	// Context names the providers that add context to new entries; see etu create --context.
+	Context []string
+	// ContextFormat is "footer" or "tags"; empty means footer.
+	ContextFormat string
+
+	// Timezone is the IANA zone entry times are displayed in; empty means local.
+	Timezone string
+	// DateFormat picks how entry dates are displayed; empty means "iso".
+	DateFormat string
+	// Clock is "12h", "24h" or "auto"; empty means auto.
+	Clock string
+
+	// Dialer replaces the network dialer, e.g. with an in-process bufconn listener.
+	// Insecure is allowed with a custom Dialer regardless of GRPCTarget.
+	Dialer func(ctx context.Context, addr string) (net.Conn, error)
+
+	grpc *grpcClients
 }
 
 // LoadConfig loads configuration from ~/.config/etu/config.json and environment variables.
-// Env ETU_API_KEY and ETU_GRPC_TARGET override file values. If no config file exists and
-// no API key is set, a config file is created with the correct structure and an empty key.
+// Env ETU_API_KEY, ETU_GRPC_TARGET, ETU_SCREENSHOT_COMMAND, ETU_CONTEXT (comma-separated),
+// ETU_TIMEZONE, ETU_DATE_FORMAT and ETU_CLOCK fill in values missing from the file.
+// ETU_TLS_CA_FILE, ETU_TLS_CERT_FILE, ETU_TLS_KEY_FILE, ETU_TLS_SERVER_NAME, ETU_INSECURE
+// and ETU_TIMEOUT override the file for this run only. If no config file exists and no
+// API key is set, a config file is created with the correct structure and an empty key.
 func LoadConfig() *Config {
 	cf, err := loadConfigFromFile()
 	if err != nil {
 		log.Printf("etu: reading config: %v", err)
 	}
+	if cf == nil {
+		cf = &ConfigFile{}
+	}
 
 	if cf.APIKey == "" {
 		cf.APIKey = os.Getenv("ETU_API_KEY")
@@ -51,18 +102,100 @@ func LoadConfig() *Config {
 	if cf.GRPCTarget == "" {
 		cf.GRPCTarget = os.Getenv("ETU_GRPC_TARGET")
 	}
+	// Transport and security settings from the environment win over the
+	// file, and since only the API key and target are saved, they last
+	// for this run only.
+	envOverride(&cf.CAFile, "ETU_TLS_CA_FILE")
+	envOverride(&cf.CertFile, "ETU_TLS_CERT_FILE")
+	envOverride(&cf.KeyFile, "ETU_TLS_KEY_FILE")
+	envOverride(&cf.ServerName, "ETU_TLS_SERVER_NAME")
+	envOverride(&cf.Timeout, "ETU_TIMEOUT")
+	if v := strings.TrimSpace(os.Getenv("ETU_INSECURE")); v != "" {
+		if insecure, err := strconv.ParseBool(v); err != nil {
+			log.Printf("etu: ignoring invalid ETU_INSECURE %q: %v", v, err)
+		} else {
+			cf.Insecure = insecure
+		}
+	}
+	if cf.ScreenshotCommand == "" {
+		cf.ScreenshotCommand = os.Getenv("ETU_SCREENSHOT_COMMAND")
+	}
+	if len(cf.Context) == 0 {
+		cf.Context = splitList(os.Getenv("ETU_CONTEXT"))
+	}
+	if cf.Timezone == "" {
+		cf.Timezone = os.Getenv("ETU_TIMEZONE")
+	}
+	if cf.DateFormat == "" {
+		cf.DateFormat = os.Getenv("ETU_DATE_FORMAT")
+	}
+	if cf.Clock == "" {
+		cf.Clock = os.Getenv("ETU_CLOCK")
+	}
+	var timeout time.Duration
+	if t := strings.TrimSpace(cf.Timeout); t != "" {
+		if timeout, err = time.ParseDuration(t); err != nil {
+			log.Printf("etu: ignoring invalid timeout %q: %v", t, err)
+			timeout = 0
+		}
+	}
+	maxMessageSize := cf.MaxMessageSize
+	if maxMessageSize != 0 && maxMessageSize <= messageOverhead {
+		log.Printf("etu: ignoring max_message_size %d: it must be over %s", maxMessageSize, FormatBytes(messageOverhead))
+		maxMessageSize = 0
+	}
 	// Trim whitespace so pasted keys or env vars with trailing newlines don't break validation.
 	return &Config{
 		APIKey:     strings.TrimSpace(cf.APIKey),
 		GRPCTarget: strings.TrimSpace(cf.GRPCTarget),
+		CAFile:     strings.TrimSpace(cf.CAFile),
+		CertFile:   strings.TrimSpace(cf.CertFile),
+		KeyFile:    strings.TrimSpace(cf.KeyFile),
+		ServerName: strings.TrimSpace(cf.ServerName),
+		Insecure:   cf.Insecure,
+		Timeout:    timeout,
+		MaxRetries: cf.MaxRetries,
+
+		MaxMessageSize:    maxMessageSize,
+		ImageMaxDimension: cf.ImageMaxDimension,
+		ImageQuality:      cf.ImageQuality,
+
+		ScreenshotCommand: strings.TrimSpace(cf.ScreenshotCommand),
+		Context:           cf.Context,
+		ContextFormat:     strings.TrimSpace(cf.ContextFormat),
+
+		Timezone:   strings.TrimSpace(cf.Timezone),
+		DateFormat: strings.TrimSpace(cf.DateFormat),
+		Clock:      strings.TrimSpace(cf.Clock),
+	}
+}
+
+// envOverride sets *field to the environment variable name if it is set.
+func envOverride(field *string, name string) {
+	if v := os.Getenv(name); v != "" {
+		*field = v
+	}
+}
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
 	}
+	return out
 }
 
-// Validate checks that the API key is present.
+// Validate checks that the API key is present and the transport settings are coherent.
 func (c *Config) Validate() error {
 	if c.APIKey == "" {
 		return fmt.Errorf("API key required: set ETU_API_KEY or add api_key to config file")
 	}
+	if err := c.validateTransport(); err != nil {
+		return err
+	}
 	c.warnIfTargetUnresolvable()
 	return nil
 }
@@ -70,7 +203,7 @@ func (c *Config) Validate() error {
 // warnIfTargetUnresolvable prints a stderr warning when GRPCTarget's host doesn't resolve.
 // Catches stale grpc_target values after a default change (e.g. PR #97 moved natwelch.com → timeclimbers.com).
 func (c *Config) warnIfTargetUnresolvable() {
-	if c.GRPCTarget == "" {
+	if c.GRPCTarget == "" || c.Dialer != nil || isUnixTarget(c.GRPCTarget) {
 		return
 	}
 	host, _, err := net.SplitHostPort(c.GRPCTarget)
@@ -199,22 +332,39 @@ func detectMIME(data []byte, path string) string {
 	return mimeType
 }
 
+// DetectMIME returns the MIME type of data as SaveEntry would send it,
+// sniffing the content and falling back to path's extension.
+func DetectMIME(data []byte, path string) string {
+	return detectMIME(data, path)
+}
+
 // LoadImageUploads reads image files from paths and returns proto ImageUpload messages.
 // MIME type is detected from content (or file extension as fallback).
 func LoadImageUploads(paths []string) ([]*proto.ImageUpload, error) {
+	return loadImageUploads(paths, mediaOptions{})
+}
+
+func loadImageUploads(paths []string, opts mediaOptions) ([]*proto.ImageUpload, error) {
 	if len(paths) == 0 {
 		return nil, nil
 	}
 	out := make([]*proto.ImageUpload, 0, len(paths))
 	for _, p := range paths {
-		// Paths come from CLI flags supplied by the user; reading them is the intent.
-		data, err := os.ReadFile(p) //nolint:gosec // G304: user-supplied CLI input
+		data, err := readAttachment(p, "image", opts)
 		if err != nil {
-			return nil, fmt.Errorf("read image %s: %w", p, err)
+			return nil, err
+		}
+		data, mimeType, note := prepareImage(data, detectMIME(data, p), opts)
+		if note != "" {
+			opts.report(UploadProgress{Path: p, Done: int64(len(data)), Total: int64(len(data)), Note: note})
+		}
+		if opts.maxBytes > 0 && int64(len(data)) > opts.maxBytes {
+			return nil, fmt.Errorf("image %s is %s even after downscaling, over the backend's %s limit per entry: lower image_max_dimension",
+				filepath.Base(p), FormatBytes(int64(len(data))), FormatBytes(opts.maxBytes))
 		}
 		out = append(out, &proto.ImageUpload{
 			Data:     data,
-			MimeType: detectMIME(data, p),
+			MimeType: mimeType,
 		})
 	}
 	return out, nil
@@ -223,51 +373,99 @@ func LoadImageUploads(paths []string) ([]*proto.ImageUpload, error) {
 // LoadAudioUploads reads audio files from paths and returns proto AudioUpload messages.
 // MIME type is detected from content (or file extension as fallback).
 func LoadAudioUploads(paths []string) ([]*proto.AudioUpload, error) {
+	return loadAudioUploads(paths, mediaOptions{})
+}
+
+func loadAudioUploads(paths []string, opts mediaOptions) ([]*proto.AudioUpload, error) {
 	if len(paths) == 0 {
 		return nil, nil
 	}
 	out := make([]*proto.AudioUpload, 0, len(paths))
 	for _, p := range paths {
-		// Paths come from CLI flags supplied by the user; reading them is the intent.
-		data, err := os.ReadFile(p) //nolint:gosec // G304: user-supplied CLI input
+		data, err := readAttachment(p, "audio", opts)
 		if err != nil {
-			return nil, fmt.Errorf("read audio %s: %w", p, err)
+			return nil, err
+		}
+		mimeType := detectMIME(data, p)
+		if mimeType == "application/ogg" {
+			// Sniffing can't tell audio from video Ogg; attachments here are audio.
+			mimeType = "audio/ogg"
 		}
 		out = append(out, &proto.AudioUpload{
 			Data:     data,
-			MimeType: detectMIME(data, p),
+			MimeType: mimeType,
 		})
 	}
 	return out, nil
 }
 
+type entryTagsKey struct{}
+
+// WithTags returns a context that makes SaveEntry add tags to the new entry,
+// alongside any the backend generates.
+func WithTags(ctx context.Context, tags ...string) context.Context {
+	return context.WithValue(ctx, entryTagsKey{}, append(slices.Clone(EntryTags(ctx)), tags...))
+}
+
+// EntryTags returns the tags added to ctx by WithTags.
+func EntryTags(ctx context.Context) []string {
+	tags, _ := ctx.Value(entryTagsKey{}).([]string)
+	return tags
+}
+
+// mergeTags appends the tags in extra missing from tags.
+func mergeTags(tags, extra []string) []string {
+	out := append([]string(nil), tags...)
+	for _, t := range extra {
+		if !slices.Contains(out, t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
 // SaveEntry saves a new journal entry via the backend (tags are generated on the backend).
 // imagePaths and audioPaths are optional paths to image and audio files to attach to the note.
-func (c *Config) SaveEntry(ctx context.Context, text string, imagePaths, audioPaths []string) error {
-	userID, err := c.ensureUserID(ctx)
-	if err != nil {
-		return err
-	}
+// It returns the created entry.
+func (c *Config) SaveEntry(ctx context.Context, text string, imagePaths, audioPaths []string) (*Post, error) {
 	g, err := c.getGRPCClients()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	images, err := LoadImageUploads(imagePaths)
+	opts := c.mediaOptions(ctx)
+	images, err := loadImageUploads(imagePaths, opts)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	audios, err := LoadAudioUploads(audioPaths)
+	audios, err := loadAudioUploads(audioPaths, opts)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	sizes := map[string]int64{"entry text": int64(len(text))}
+	for i, img := range images {
+		sizes[imagePaths[i]] = int64(len(img.GetData()))
+	}
+	for i, aud := range audios {
+		sizes[audioPaths[i]] = int64(len(aud.GetData()))
+	}
+	if err := checkTotalSize(sizes, opts.maxBytes); err != nil {
+		return nil, err
 	}
-	resp, err := g.notesClient.CreateNote(ctx, &proto.CreateNoteRequest{
-		UserId:  userID,
-		Content: text,
-		Images:  images,
-		Audios:  audios,
+	// One key per entry, for a backend that drops duplicate creates.
+	createCtx := withIdempotencyKey(ctx)
+	var resp *proto.CreateNoteResponse
+	err = c.withUserID(createCtx, func(userID string) error {
+		var err error
+		resp, err = g.notesClient.CreateNote(createCtx, &proto.CreateNoteRequest{
+			UserId:  userID,
+			Content: text,
+			Images:  images,
+			Audios:  audios,
+		})
+		return err
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 	created := resp.GetNote()
 	if created != nil && created.GetCreatedAt() != nil {
@@ -276,55 +474,92 @@ func (c *Config) SaveEntry(ctx context.Context, text string, imagePaths, audioPa
 			log.Printf("etu: updating timesince cache: %v", err)
 		}
 	}
-	return nil
+	if tags := EntryTags(ctx); len(tags) > 0 && created != nil {
+		// CreateNote has no tags field, so tags are added in a follow-up update.
+		err = c.withUserID(ctx, func(userID string) error {
+			updated, err := g.notesClient.UpdateNote(ctx, &proto.UpdateNoteRequest{
+				UserId:     userID,
+				Id:         created.GetId(),
+				Tags:       mergeTags(created.GetTags(), tags),
+				UpdateTags: true,
+			})
+			if err == nil && updated.GetNote() != nil {
+				created = updated.GetNote()
+			}
+			return err
+		})
+		if err != nil {
+			return noteToPost(created), fmt.Errorf("entry saved, but adding tags failed: %w", err)
+		}
+	}
+	return noteToPost(created), nil
 }
 
 // UpdatePost updates the content of an existing journal entry by ID.
 // Tags are left untouched (the backend keeps the existing tag list).
 func (c *Config) UpdatePost(ctx context.Context, pageID, content string) (*Post, error) {
-	userID, err := c.ensureUserID(ctx)
+	g, err := c.getGRPCClients()
 	if err != nil {
 		return nil, err
 	}
+	var resp *proto.UpdateNoteResponse
+	err = c.withUserID(ctx, func(userID string) error {
+		var err error
+		resp, err = g.notesClient.UpdateNote(ctx, &proto.UpdateNoteRequest{
+			UserId:     userID,
+			Id:         pageID,
+			Content:    &content,
+			UpdateTags: false,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update note: %w", err)
+	}
+	return noteToPost(resp.GetNote()), nil
+}
+
+// SetPostTags replaces the tags of an existing journal entry, leaving its
+// content untouched.
+func (c *Config) SetPostTags(ctx context.Context, pageID string, tags []string) (*Post, error) {
 	g, err := c.getGRPCClients()
 	if err != nil {
 		return nil, err
 	}
-	resp, err := g.notesClient.UpdateNote(ctx, &proto.UpdateNoteRequest{
-		UserId:     userID,
-		Id:         pageID,
-		Content:    &content,
-		UpdateTags: false,
+	var resp *proto.UpdateNoteResponse
+	err = c.withUserID(ctx, func(userID string) error {
+		var err error
+		resp, err = g.notesClient.UpdateNote(ctx, &proto.UpdateNoteRequest{
+			UserId:     userID,
+			Id:         pageID,
+			Tags:       tags,
+			UpdateTags: true,
+		})
+		return err
 	})
 	if err != nil {
-		return nil, fmt.Errorf("update note: %w", err)
+		return nil, fmt.Errorf("update tags: %w", err)
 	}
 	return noteToPost(resp.GetNote()), nil
 }
 
 // DeletePost deletes a journal entry by ID.
 func (c *Config) DeletePost(ctx context.Context, pageID string) error {
-	userID, err := c.ensureUserID(ctx)
-	if err != nil {
-		return err
-	}
 	g, err := c.getGRPCClients()
 	if err != nil {
 		return err
 	}
-	_, err = g.notesClient.DeleteNote(ctx, &proto.DeleteNoteRequest{
-		UserId: userID,
-		Id:     pageID,
+	return c.withUserID(ctx, func(userID string) error {
+		_, err := g.notesClient.DeleteNote(ctx, &proto.DeleteNoteRequest{
+			UserId: userID,
+			Id:     pageID,
+		})
+		return err
 	})
-	return err
 }
 
 // ListPosts lists the most recent journal entries.
 func (c *Config) ListPosts(ctx context.Context, count int) ([]*Post, error) {
-	userID, err := c.ensureUserID(ctx)
-	if err != nil {
-		return nil, err
-	}
 	g, err := c.getGRPCClients()
 	if err != nil {
 		return nil, err
@@ -333,9 +568,14 @@ func (c *Config) ListPosts(ctx context.Context, count int) ([]*Post, error) {
 	if err != nil {
 		return nil, fmt.Errorf("count: %w", err)
 	}
-	resp, err := g.notesClient.ListNotes(ctx, &proto.ListNotesRequest{
-		UserId: userID,
-		Limit:  limit,
+	var resp *proto.ListNotesResponse
+	err = c.withUserID(ctx, func(userID string) error {
+		var err error
+		resp, err = g.notesClient.ListNotes(ctx, &proto.ListNotesRequest{
+			UserId: userID,
+			Limit:  limit,
+		})
+		return err
 	})
 	if err != nil {
 		return nil, err
@@ -345,10 +585,6 @@ func (c *Config) ListPosts(ctx context.Context, count int) ([]*Post, error) {
 
 // SearchPosts searches journal entries via the backend.
 func (c *Config) SearchPosts(ctx context.Context, query string, maxResults int) ([]*Post, error) {
-	userID, err := c.ensureUserID(ctx)
-	if err != nil {
-		return nil, err
-	}
 	g, err := c.getGRPCClients()
 	if err != nil {
 		return nil, err
@@ -357,10 +593,15 @@ func (c *Config) SearchPosts(ctx context.Context, query string, maxResults int)
 	if err != nil {
 		return nil, fmt.Errorf("maxResults: %w", err)
 	}
-	resp, err := g.notesClient.ListNotes(ctx, &proto.ListNotesRequest{
-		UserId: userID,
-		Search: query,
-		Limit:  limit,
+	var resp *proto.ListNotesResponse
+	err = c.withUserID(ctx, func(userID string) error {
+		var err error
+		resp, err = g.notesClient.ListNotes(ctx, &proto.ListNotesRequest{
+			UserId: userID,
+			Search: query,
+			Limit:  limit,
+		})
+		return err
 	})
 	if err != nil {
 		return nil, err
@@ -370,10 +611,6 @@ func (c *Config) SearchPosts(ctx context.Context, query string, maxResults int)
 
 // GetRandomPosts fetches random journal entries from the backend.
 func (c *Config) GetRandomPosts(ctx context.Context, count int) ([]*Post, error) {
-	userID, err := c.ensureUserID(ctx)
-	if err != nil {
-		return nil, err
-	}
 	g, err := c.getGRPCClients()
 	if err != nil {
 		return nil, err
@@ -382,9 +619,14 @@ func (c *Config) GetRandomPosts(ctx context.Context, count int) ([]*Post, error)
 	if err != nil {
 		return nil, fmt.Errorf("count: %w", err)
 	}
-	resp, err := g.notesClient.GetRandomNotes(ctx, &proto.GetRandomNotesRequest{
-		UserId: userID,
-		Count:  c32,
+	var resp *proto.GetRandomNotesResponse
+	err = c.withUserID(ctx, func(userID string) error {
+		var err error
+		resp, err = g.notesClient.GetRandomNotes(ctx, &proto.GetRandomNotesRequest{
+			UserId: userID,
+			Count:  c32,
+		})
+		return err
 	})
 	if err != nil {
 		return nil, err
@@ -400,16 +642,17 @@ type Tag struct {
 
 // ListTags lists all tags for the current user.
 func (c *Config) ListTags(ctx context.Context) ([]Tag, error) {
-	userID, err := c.ensureUserID(ctx)
-	if err != nil {
-		return nil, err
-	}
 	g, err := c.getGRPCClients()
 	if err != nil {
 		return nil, err
 	}
-	resp, err := g.tagsClient.ListTags(ctx, &proto.ListTagsRequest{
-		UserId: userID,
+	var resp *proto.ListTagsResponse
+	err = c.withUserID(ctx, func(userID string) error {
+		var err error
+		resp, err = g.tagsClient.ListTags(ctx, &proto.ListTagsRequest{
+			UserId: userID,
+		})
+		return err
 	})
 	if err != nil {
 		return nil, fmt.Errorf("list tags: %w", err)
@@ -427,21 +670,23 @@ type Stats struct {
 // GetStats fetches aggregate stats. When global is true, community-wide stats
 // are returned; otherwise stats are scoped to the current user.
 func (c *Config) GetStats(ctx context.Context, global bool) (Stats, error) {
-	var userID string
-	if !global {
-		var err error
-		userID, err = c.ensureUserID(ctx)
-		if err != nil {
-			return Stats{}, err
-		}
-	}
 	g, err := c.getGRPCClients()
 	if err != nil {
 		return Stats{}, err
 	}
-	resp, err := g.statsClient.GetStats(ctx, &proto.GetStatsRequest{
-		UserId: userID,
-	})
+	var resp *proto.GetStatsResponse
+	getStats := func(userID string) error {
+		var err error
+		resp, err = g.statsClient.GetStats(ctx, &proto.GetStatsRequest{
+			UserId: userID,
+		})
+		return err
+	}
+	if global {
+		err = getStats("")
+	} else {
+		err = c.withUserID(ctx, getStats)
+	}
 	if err != nil {
 		return Stats{}, fmt.Errorf("get stats: %w", err)
 	}
@@ -452,24 +697,47 @@ func (c *Config) GetStats(ctx context.Context, global bool) (Stats, error) {
 	}, nil
 }
 
-// GetPostFullContent fetches the full content of a post by ID.
-func (c *Config) GetPostFullContent(ctx context.Context, pageID string) (string, error) {
-	userID, err := c.ensureUserID(ctx)
-	if err != nil {
-		return "", err
-	}
+// getNote fetches the note with ID pageID as the backend returns it.
+func (c *Config) getNote(ctx context.Context, pageID string) (*proto.Note, error) {
 	g, err := c.getGRPCClients()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	resp, err := g.notesClient.GetNote(ctx, &proto.GetNoteRequest{
-		UserId: userID,
-		Id:     pageID,
+	var resp *proto.GetNoteResponse
+	err = c.withUserID(ctx, func(userID string) error {
+		var err error
+		resp, err = g.notesClient.GetNote(ctx, &proto.GetNoteRequest{
+			UserId: userID,
+			Id:     pageID,
+		})
+		return err
 	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetNote(), nil
+}
+
+// GetPost fetches a single journal entry by ID, including attachment
+// processing results.
+func (c *Config) GetPost(ctx context.Context, pageID string) (*Post, error) {
+	n, err := c.getNote(ctx, pageID)
+	if err != nil {
+		return nil, fmt.Errorf("get note: %w", err)
+	}
+	if n == nil {
+		return nil, fmt.Errorf("note %s not found", pageID)
+	}
+	return noteToPost(n), nil
+}
+
+// GetPostFullContent fetches the full content of a post by ID.
+func (c *Config) GetPostFullContent(ctx context.Context, pageID string) (string, error) {
+	n, err := c.getNote(ctx, pageID)
 	if err != nil {
 		return "", err
 	}
-	if n := resp.GetNote(); n != nil {
+	if n != nil {
 		return strings.TrimSpace(n.GetContent()), nil
 	}
 	return "", nil
@@ -1,8 +1,10 @@
 package client
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 )
@@ -28,6 +30,21 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestWithTagsDoesNotShareTags(t *testing.T) {
+	base := WithTags(context.Background(), "a", "b", "c")
+	// Trim capacity off so both appends would land in the same spare slot.
+	base = context.WithValue(base, entryTagsKey{}, EntryTags(base)[:2:3])
+
+	x := WithTags(base, "x")
+	y := WithTags(base, "y")
+	if got := EntryTags(x); !slices.Equal(got, []string{"a", "b", "x"}) {
+		t.Errorf("x tags = %q, want [a b x]", got)
+	}
+	if got := EntryTags(y); !slices.Equal(got, []string{"a", "b", "y"}) {
+		t.Errorf("y tags = %q, want [a b y]", got)
+	}
+}
+
 func TestDetectMIME(t *testing.T) {
 	tests := []struct {
 		name string
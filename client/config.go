@@ -13,6 +13,42 @@ const defaultGRPCTarget = "grpc.etu.timeclimbers.com:443"
 type ConfigFile struct {
 	APIKey     string `json:"api_key"`
 	GRPCTarget string `json:"grpc_target"`
+
+	// TLS settings for self-hosted backends. All optional; the zero value
+	// dials the target over TLS verified against the system roots.
+	CAFile     string `json:"tls_ca_file,omitempty"`
+	CertFile   string `json:"tls_cert_file,omitempty"`
+	KeyFile    string `json:"tls_key_file,omitempty"`
+	ServerName string `json:"tls_server_name,omitempty"`
+	Insecure   bool   `json:"insecure,omitempty"`
+
+	// Timeout is the per-call deadline as a Go duration (e.g. "15s").
+	Timeout string `json:"timeout,omitempty"`
+	// MaxRetries caps retries of idempotent calls; negative disables them.
+	MaxRetries int `json:"max_retries,omitempty"`
+
+	// MaxMessageSize is the backend's request size limit in bytes.
+	MaxMessageSize int `json:"max_message_size,omitempty"`
+	// ImageMaxDimension downscales attached images to fit this many pixels.
+	ImageMaxDimension int `json:"image_max_dimension,omitempty"`
+	// ImageQuality is the JPEG quality used when re-encoding images.
+	ImageQuality int `json:"image_quality,omitempty"`
+
+	// ScreenshotCommand captures a screenshot for attaching, e.g. `grim -g "$(slurp)" -`.
+	ScreenshotCommand string `json:"screenshot_command,omitempty"`
+
+	// Context names the providers whose output is added to new entries,
+	// e.g. ["git", "cwd"]; empty disables context capture.
+	Context []string `json:"context,omitempty"`
+	// ContextFormat is how context is added: "footer" (default) or "tags".
+	ContextFormat string `json:"context_format,omitempty"`
+
+	// Timezone is the IANA zone entry times are shown in, e.g. "Europe/Berlin".
+	Timezone string `json:"timezone,omitempty"`
+	// DateFormat is "iso", "us", "eu", "long", "relative" or a Go time layout.
+	DateFormat string `json:"date_format,omitempty"`
+	// Clock is "12h", "24h" or "auto" (from the locale).
+	Clock string `json:"clock,omitempty"`
 }
 
 // ConfigDir returns the etu config directory (e.g. ~/.config/etu on Unix).
@@ -48,9 +84,32 @@ func CachePath(filename string) (string, error) {
 	return filepath.Join(dir, filename), nil
 }
 
-// loadConfigFromFile reads api_key and grpc_target from ~/.config/etu/config.json.
-// Missing file or invalid JSON returns nil error and zero values; caller can use env or defaults.
+// TemplatesDir returns the directory holding entry templates
+// (~/.config/etu/templates), creating it if needed.
+func TemplatesDir() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	full := filepath.Join(dir, "templates")
+	if err := os.MkdirAll(full, 0700); err != nil {
+		return "", fmt.Errorf("create templates dir: %w", err)
+	}
+	return full, nil
+}
+
+// loadConfigFromFile reads ~/.config/etu/config.json, creating it with an
+// empty api_key and the default grpc_target if it doesn't exist.
 func loadConfigFromFile() (*ConfigFile, error) {
+	cf, err := readConfigFile()
+	if os.IsNotExist(err) {
+		return SaveConfig("", "")
+	}
+	return cf, err
+}
+
+// readConfigFile reads ~/.config/etu/config.json as it is on disk.
+func readConfigFile() (*ConfigFile, error) {
 	path, err := ConfigPath()
 	if err != nil {
 		return nil, err
@@ -58,9 +117,6 @@ func loadConfigFromFile() (*ConfigFile, error) {
 	// path is from ConfigPath() (fixed config dir under user home), not external input.
 	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from fixed config dir, not user-controlled
 	if err != nil {
-		if os.IsNotExist(err) {
-			return SaveConfig("", "")
-		}
 		return nil, err
 	}
 	var cf ConfigFile
@@ -70,17 +126,31 @@ func loadConfigFromFile() (*ConfigFile, error) {
 	return &cf, nil
 }
 
-// SaveConfig writes api_key and grpc_target to ~/.config/etu/config.json.
-// Creates the config directory if it does not exist.
+// SaveConfig writes api_key and grpc_target to ~/.config/etu/config.json,
+// keeping the file's other settings as they are. Creates the config
+// directory if it does not exist.
 func SaveConfig(apiKey, grpcTarget string) (*ConfigFile, error) {
-	if grpcTarget == "" {
-		grpcTarget = defaultGRPCTarget
+	cf, err := readConfigFile()
+	switch {
+	case os.IsNotExist(err):
+		cf = &ConfigFile{}
+	case err != nil:
+		return nil, err
+	}
+	cf.APIKey, cf.GRPCTarget = apiKey, grpcTarget
+	return SaveConfigFile(cf)
+}
+
+// SaveConfigFile writes cf to ~/.config/etu/config.json, filling in the default
+// grpc_target when empty. Creates the config directory if it does not exist.
+func SaveConfigFile(cf *ConfigFile) (*ConfigFile, error) {
+	if cf.GRPCTarget == "" {
+		cf.GRPCTarget = defaultGRPCTarget
 	}
 	path, err := ConfigPath()
 	if err != nil {
 		return nil, err
 	}
-	cf := &ConfigFile{APIKey: apiKey, GRPCTarget: grpcTarget}
 	// Persisting the API key to the user's local config is intentional.
 	data, err := json.Marshal(cf) //nolint:gosec // G117: api_key persistence is the purpose of this file
 	if err != nil {
@@ -3,6 +3,7 @@ package client
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -169,3 +170,77 @@ func TestLoadConfigFileOverridesDefault(t *testing.T) {
 		t.Errorf("GRPCTarget = %q, want %q", cfg.GRPCTarget, "file-target:443")
 	}
 }
+
+func TestSaveConfigFilePreservesTLS(t *testing.T) {
+	setTestHome(t)
+
+	in := &ConfigFile{
+		APIKey:     "key",
+		GRPCTarget: "localhost:50051",
+		CAFile:     "/etc/etu/ca.pem",
+		CertFile:   "/etc/etu/client.pem",
+		KeyFile:    "/etc/etu/client-key.pem",
+		ServerName: "etu.internal",
+		Insecure:   true,
+
+		Context:       []string{"git", "cwd"},
+		ContextFormat: "tags",
+		Timezone:      "Europe/Berlin",
+		DateFormat:    "relative",
+		Clock:         "12h",
+	}
+	if _, err := SaveConfigFile(in); err != nil {
+		t.Fatalf("SaveConfigFile: %v", err)
+	}
+
+	loaded, err := loadConfigFromFile()
+	if err != nil {
+		t.Fatalf("loadConfigFromFile: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, in) {
+		t.Errorf("loaded = %+v, want %+v", *loaded, *in)
+	}
+}
+
+func TestLoadConfigTLSFromEnv(t *testing.T) {
+	setTestHome(t)
+	t.Setenv("ETU_TLS_CA_FILE", "/tmp/ca.pem")
+	t.Setenv("ETU_TLS_SERVER_NAME", "etu.internal")
+	t.Setenv("ETU_INSECURE", "true")
+
+	cfg := LoadConfig()
+	if cfg.CAFile != "/tmp/ca.pem" {
+		t.Errorf("CAFile = %q, want %q", cfg.CAFile, "/tmp/ca.pem")
+	}
+	if cfg.ServerName != "etu.internal" {
+		t.Errorf("ServerName = %q, want %q", cfg.ServerName, "etu.internal")
+	}
+	if !cfg.Insecure {
+		t.Error("Insecure = false, want true")
+	}
+}
+
+func TestLoadConfigEnvIsNotSaved(t *testing.T) {
+	setTestHome(t)
+	if _, err := SaveConfigFile(&ConfigFile{APIKey: "key", GRPCTarget: "localhost:50051", Insecure: true, Timezone: "Europe/Berlin"}); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ETU_INSECURE", "0")
+	t.Setenv("ETU_TLS_CA_FILE", "/tmp/ca.pem")
+
+	cfg := LoadConfig()
+	if cfg.Insecure || cfg.CAFile != "/tmp/ca.pem" {
+		t.Errorf("Insecure = %v, CAFile = %q; want the env to override the file", cfg.Insecure, cfg.CAFile)
+	}
+	if _, err := SaveConfig("new-key", cfg.GRPCTarget); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := readConfigFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &ConfigFile{APIKey: "new-key", GRPCTarget: "localhost:50051", Insecure: true, Timezone: "Europe/Berlin"}
+	if !reflect.DeepEqual(saved, want) {
+		t.Errorf("saved = %+v, want %+v", *saved, *want)
+	}
+}
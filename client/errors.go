@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RPCError is a backend call failure with an actionable message.
+// The underlying gRPC error stays reachable via errors.Unwrap and status.Code.
+type RPCError struct {
+	Method string
+	Code   codes.Code
+	msg    string
+	err    error
+}
+
+func (e *RPCError) Error() string { return e.msg }
+func (e *RPCError) Unwrap() error { return e.err }
+
+// newRPCError maps a gRPC failure from method to an RPCError. nil stays nil.
+func newRPCError(method string, c *Config, err error) error {
+	if err == nil {
+		return nil
+	}
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return err
+	}
+	code := status.Code(err)
+	if errors.Is(err, context.DeadlineExceeded) {
+		code = codes.DeadlineExceeded
+	} else if errors.Is(err, context.Canceled) {
+		code = codes.Canceled
+	}
+	return &RPCError{
+		Method: path.Base(method),
+		Code:   code,
+		msg:    friendlyMessage(code, c, err),
+		err:    err,
+	}
+}
+
+// friendlyMessage explains a gRPC status code in terms of what the user can do.
+func friendlyMessage(code codes.Code, c *Config, err error) string {
+	desc := status.Convert(err).Message()
+	switch code {
+	case codes.Unauthenticated:
+		return "the backend rejected the API key: check api_key in the config file or ETU_API_KEY"
+	case codes.PermissionDenied:
+		return "the API key is not allowed to do that: check it belongs to this account"
+	case codes.Unavailable:
+		return fmt.Sprintf("cannot reach the backend at %s: check your network or grpc_target (%s)", c.GRPCTarget, desc)
+	case codes.DeadlineExceeded:
+		return fmt.Sprintf("the backend did not answer within %s: try again or raise timeout in the config file", c.callTimeout())
+	case codes.Canceled:
+		return "request canceled"
+	case codes.NotFound:
+		return "entry not found: it may have been deleted"
+	case codes.InvalidArgument:
+		return "the backend rejected the request: " + desc
+	case codes.ResourceExhausted:
+		return "the backend is rate limiting or the request is too large: " + desc
+	case codes.Unimplemented:
+		return "the backend does not support this operation: it may need upgrading"
+	default:
+		return fmt.Sprintf("backend error (%s): %s", code, desc)
+	}
+}
+
+// IsNotFound reports whether err means the entry doesn't exist.
+func IsNotFound(err error) bool {
+	return status.Code(err) == codes.NotFound
+}
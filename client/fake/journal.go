@@ -0,0 +1,241 @@
+// Package fake provides test doubles for the etu client: an in-memory
+// client.Journal and an in-process gRPC server implementing the etu-backend
+// services.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/icco/etu-backend/proto"
+	"github.com/icco/etu/client"
+)
+
+// Journal is an in-memory client.Journal. The zero value is empty and ready to use.
+type Journal struct {
+	// Now returns the current time; nil means time.Now.
+	Now func() time.Time
+
+	mu     sync.Mutex
+	posts  []*client.Post // newest first
+	nextID int
+}
+
+var _ client.Journal = (*Journal)(nil)
+
+// NewJournal returns a Journal seeded with posts, in any order.
+func NewJournal(posts ...*client.Post) *Journal {
+	j := &Journal{}
+	for _, p := range posts {
+		j.add(p)
+	}
+	return j
+}
+
+func (j *Journal) now() time.Time {
+	if j.Now != nil {
+		return j.Now()
+	}
+	return time.Now()
+}
+
+// add stores a copy of p, assigning an ID if it has none, and keeps posts newest first.
+func (j *Journal) add(p *client.Post) *client.Post {
+	cp := *p
+	if cp.PageID == "" {
+		j.nextID++
+		cp.PageID = "note-" + strconv.Itoa(j.nextID)
+	}
+	j.posts = append(j.posts, &cp)
+	sort.SliceStable(j.posts, func(a, b int) bool { return j.posts[a].CreatedAt.After(j.posts[b].CreatedAt) })
+	return &cp
+}
+
+func (j *Journal) find(pageID string) (int, error) {
+	for i, p := range j.posts {
+		if p.PageID == pageID {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("note %s not found", pageID)
+}
+
+// Posts returns a snapshot of all stored posts, newest first.
+func (j *Journal) Posts() []*client.Post {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return clonePosts(j.posts, len(j.posts))
+}
+
+// SaveEntry stores a new post with any tags from client.WithTags.
+// Attachments are recorded by path.
+func (j *Journal) SaveEntry(ctx context.Context, text string, imagePaths, audioPaths []string) (*client.Post, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	p := &client.Post{Text: text, Tags: client.EntryTags(ctx), CreatedAt: j.now()}
+	for _, path := range imagePaths {
+		p.Images = append(p.Images, &proto.NoteImage{Url: "file://" + path})
+	}
+	for _, path := range audioPaths {
+		p.Audios = append(p.Audios, &proto.NoteAudio{Url: "file://" + path})
+	}
+	cp := *j.add(p)
+	return &cp, nil
+}
+
+// UpdatePost replaces the text of a post.
+func (j *Journal) UpdatePost(_ context.Context, pageID, content string) (*client.Post, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	i, err := j.find(pageID)
+	if err != nil {
+		return nil, err
+	}
+	j.posts[i].Text = content
+	cp := *j.posts[i]
+	return &cp, nil
+}
+
+// SetPostTags replaces the tags of a post.
+func (j *Journal) SetPostTags(_ context.Context, pageID string, tags []string) (*client.Post, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	i, err := j.find(pageID)
+	if err != nil {
+		return nil, err
+	}
+	j.posts[i].Tags = append([]string(nil), tags...)
+	cp := *j.posts[i]
+	return &cp, nil
+}
+
+// DeletePost removes a post.
+func (j *Journal) DeletePost(_ context.Context, pageID string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	i, err := j.find(pageID)
+	if err != nil {
+		return err
+	}
+	j.posts = append(j.posts[:i], j.posts[i+1:]...)
+	return nil
+}
+
+// ListPosts returns up to count posts, newest first.
+func (j *Journal) ListPosts(_ context.Context, count int) ([]*client.Post, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return clonePosts(j.posts, count), nil
+}
+
+// SearchPosts returns up to maxResults posts whose text contains query, case-insensitively.
+func (j *Journal) SearchPosts(_ context.Context, query string, maxResults int) ([]*client.Post, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	q := strings.ToLower(query)
+	var matches []*client.Post
+	for _, p := range j.posts {
+		if strings.Contains(strings.ToLower(p.Text), q) {
+			matches = append(matches, p)
+		}
+	}
+	return clonePosts(matches, maxResults), nil
+}
+
+// GetRandomPosts returns up to count distinct posts in random order.
+func (j *Journal) GetRandomPosts(_ context.Context, count int) ([]*client.Post, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	shuffled := clonePosts(j.posts, len(j.posts))
+	rand.Shuffle(len(shuffled), func(a, b int) { shuffled[a], shuffled[b] = shuffled[b], shuffled[a] })
+	return clonePosts(shuffled, count), nil
+}
+
+// GetPost returns a copy of one post.
+func (j *Journal) GetPost(_ context.Context, pageID string) (*client.Post, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	i, err := j.find(pageID)
+	if err != nil {
+		return nil, err
+	}
+	cp := *j.posts[i]
+	return &cp, nil
+}
+
+// GetPostFullContent returns the trimmed text of a post.
+func (j *Journal) GetPostFullContent(_ context.Context, pageID string) (string, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	i, err := j.find(pageID)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(j.posts[i].Text), nil
+}
+
+// ListTags counts tag usage across posts.
+func (j *Journal) ListTags(_ context.Context) ([]client.Tag, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	counts := map[string]int32{}
+	for _, p := range j.posts {
+		for _, t := range p.Tags {
+			counts[t]++
+		}
+	}
+	tags := make([]client.Tag, 0, len(counts))
+	for name, n := range counts {
+		tags = append(tags, client.Tag{Name: name, Count: n})
+	}
+	sort.Slice(tags, func(a, b int) bool { return tags[a].Name < tags[b].Name })
+	return tags, nil
+}
+
+// GetStats computes stats over the stored posts. Global stats are the same
+// as personal ones since the fake has a single user.
+func (j *Journal) GetStats(_ context.Context, _ bool) (client.Stats, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	tags := map[string]bool{}
+	var words int64
+	for _, p := range j.posts {
+		words += int64(len(strings.Fields(p.Text)))
+		for _, t := range p.Tags {
+			tags[t] = true
+		}
+	}
+	return client.Stats{
+		TotalBlips:   int64(len(j.posts)),
+		UniqueTags:   int64(len(tags)),
+		WordsWritten: words,
+	}, nil
+}
+
+// TimeSinceLastPost returns the time since the newest post.
+func (j *Journal) TimeSinceLastPost(_ context.Context) (time.Duration, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if len(j.posts) == 0 {
+		return 0, fmt.Errorf("no posts found")
+	}
+	return j.now().Sub(j.posts[0].CreatedAt), nil
+}
+
+// clonePosts copies up to limit posts so callers can't mutate the store.
+// A non-positive limit returns no posts.
+func clonePosts(posts []*client.Post, limit int) []*client.Post {
+	limit = max(min(limit, len(posts)), 0)
+	out := make([]*client.Post, 0, limit)
+	for _, p := range posts[:limit] {
+		cp := *p
+		out = append(out, &cp)
+	}
+	return out
+}
@@ -0,0 +1,126 @@
+package fake
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/icco/etu/client"
+)
+
+func TestJournalDeleteAndUpdate(t *testing.T) {
+	ctx := context.Background()
+	j := NewJournal(&client.Post{PageID: "a", Text: "draft", CreatedAt: time.Now()})
+
+	if _, err := j.UpdatePost(ctx, "a", "final"); err != nil {
+		t.Fatalf("UpdatePost: %v", err)
+	}
+	if text, _ := j.GetPostFullContent(ctx, "a"); text != "final" {
+		t.Errorf("content = %q, want %q", text, "final")
+	}
+	if err := j.DeletePost(ctx, "a"); err != nil {
+		t.Fatalf("DeletePost: %v", err)
+	}
+	if posts := j.Posts(); len(posts) != 0 {
+		t.Errorf("got %d posts after delete, want 0", len(posts))
+	}
+}
+
+func TestJournalListNewestFirst(t *testing.T) {
+	now := time.Now()
+	j := NewJournal(
+		&client.Post{Text: "old", CreatedAt: now.Add(-time.Hour)},
+		&client.Post{Text: "new", CreatedAt: now},
+	)
+
+	posts, err := j.ListPosts(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != 1 || posts[0].Text != "new" {
+		t.Errorf("ListPosts(1) = %v, want only the newest post", posts)
+	}
+}
+
+func TestServerRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	srv := NewServer()
+	defer srv.Close()
+	c := srv.Config()
+	ctx := context.Background()
+
+	if _, err := c.SaveEntry(ctx, "hello fake", nil, nil); err != nil {
+		t.Fatalf("SaveEntry: %v", err)
+	}
+	posts, err := c.ListPosts(ctx, 10)
+	if err != nil {
+		t.Fatalf("ListPosts: %v", err)
+	}
+	if len(posts) != 1 || posts[0].Text != "hello fake" {
+		t.Fatalf("ListPosts = %v, want the saved entry", posts)
+	}
+	if err := c.DeletePost(ctx, posts[0].PageID); err != nil {
+		t.Fatalf("DeletePost: %v", err)
+	}
+	if len(srv.Notes()) != 0 {
+		t.Error("note still stored after DeletePost")
+	}
+}
+
+func TestSaveEntryWithTags(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	srv := NewServer()
+	defer srv.Close()
+	ctx := client.WithTags(context.Background(), "branch:main", "host:laptop")
+
+	if _, err := srv.Config().SaveEntry(ctx, "tagged", nil, nil); err != nil {
+		t.Fatalf("SaveEntry: %v", err)
+	}
+	notes := srv.Notes()
+	if len(notes) != 1 || len(notes[0].GetTags()) != 2 || notes[0].GetTags()[1] != "host:laptop" {
+		t.Fatalf("notes = %v, want tags from the context", notes)
+	}
+
+	j := NewJournal()
+	if _, err := j.SaveEntry(ctx, "tagged", nil, nil); err != nil {
+		t.Fatalf("Journal.SaveEntry: %v", err)
+	}
+	if got := j.Posts()[0].Tags; len(got) != 2 {
+		t.Errorf("Journal tags = %v", got)
+	}
+}
+
+func TestListPostsBetween(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var posts []*client.Post
+	for i := range 500 {
+		posts = append(posts, &client.Post{Text: strconv.Itoa(i), CreatedAt: base.Add(-time.Duration(i) * time.Hour)})
+	}
+	j := NewJournal(posts...)
+	ctx := context.Background()
+
+	from, to := base.AddDate(0, 0, -15), base.AddDate(0, 0, -14)
+	got, complete, err := client.ListPostsBetween(ctx, j, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 24 || !complete.Equal(from) {
+		t.Errorf("got %d posts complete to %v, want 24 complete to %v", len(got), complete, from)
+	}
+	for _, p := range got {
+		if p.CreatedAt.Before(from) || !p.CreatedAt.Before(to) {
+			t.Errorf("post at %v is outside [%v, %v)", p.CreatedAt, from, to)
+		}
+	}
+
+	all, complete, err := client.ListPostsBetween(ctx, j, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 500 || !complete.IsZero() {
+		t.Errorf("got %d posts complete to %v, want all 500", len(all), complete)
+	}
+}
@@ -0,0 +1,307 @@
+package fake
+
+import (
+	"context"
+	"math/rand/v2"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/icco/etu-backend/proto"
+	"github.com/icco/etu/client"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// APIKey is the only key the fake Server accepts.
+	APIKey = "fake-api-key"
+	// UserID is the user the fake Server's APIKey resolves to.
+	UserID = "fake-user"
+
+	bufSize = 1 << 20
+)
+
+// Server is an in-process etu-backend serving the notes, API key, tags and
+// stats services over a bufconn listener. Use Config to get a client for it.
+type Server struct {
+	proto.UnimplementedNotesServiceServer
+	proto.UnimplementedApiKeysServiceServer
+	proto.UnimplementedTagsServiceServer
+	proto.UnimplementedStatsServiceServer
+
+	// OnGetNote, if set, is called with the stored note before GetNote
+	// returns it, e.g. to simulate OCR or transcription finishing.
+	OnGetNote func(n *proto.Note)
+
+	lis *bufconn.Listener
+	srv *grpc.Server
+
+	mu     sync.Mutex
+	notes  []*proto.Note // newest first
+	nextID int
+}
+
+// NewServer starts a Server. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		lis: bufconn.Listen(bufSize),
+		srv: grpc.NewServer(),
+	}
+	proto.RegisterNotesServiceServer(s.srv, s)
+	proto.RegisterApiKeysServiceServer(s.srv, s)
+	proto.RegisterTagsServiceServer(s.srv, s)
+	proto.RegisterStatsServiceServer(s.srv, s)
+	go func() { _ = s.srv.Serve(s.lis) }()
+	return s
+}
+
+// Close stops the server.
+func (s *Server) Close() {
+	s.srv.Stop()
+}
+
+// Config returns a client.Config that talks to s in-process.
+func (s *Server) Config() *client.Config {
+	return &client.Config{
+		APIKey:     APIKey,
+		GRPCTarget: "passthrough:///bufnet",
+		Insecure:   true,
+		MaxRetries: -1,
+		Dialer: func(ctx context.Context, _ string) (net.Conn, error) {
+			return s.lis.DialContext(ctx)
+		},
+	}
+}
+
+// AddNote stores a note directly and returns its ID.
+func (s *Server) AddNote(content string, createdAt time.Time, tags ...string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.insert(&proto.Note{Content: content, Tags: tags, CreatedAt: timestamppb.New(createdAt)}).GetId()
+}
+
+// Notes returns a snapshot of stored notes, newest first.
+func (s *Server) Notes() []*proto.Note {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return cloneNotes(s.notes, len(s.notes))
+}
+
+func (s *Server) insert(n *proto.Note) *proto.Note {
+	s.nextID++
+	n.Id = "note-" + strconv.Itoa(s.nextID)
+	s.notes = append(s.notes, n)
+	sort.SliceStable(s.notes, func(a, b int) bool {
+		return s.notes[a].GetCreatedAt().AsTime().After(s.notes[b].GetCreatedAt().AsTime())
+	})
+	return n
+}
+
+func (s *Server) find(id string) (int, error) {
+	for i, n := range s.notes {
+		if n.GetId() == id {
+			return i, nil
+		}
+	}
+	return -1, status.Errorf(codes.NotFound, "note %s not found", id)
+}
+
+func checkUser(userID string) error {
+	if userID != UserID {
+		return status.Error(codes.PermissionDenied, "unknown user")
+	}
+	return nil
+}
+
+// VerifyApiKey accepts only APIKey.
+func (s *Server) VerifyApiKey(_ context.Context, req *proto.VerifyApiKeyRequest) (*proto.VerifyApiKeyResponse, error) {
+	if req.GetRawKey() != APIKey {
+		return &proto.VerifyApiKeyResponse{Valid: false}, nil
+	}
+	return &proto.VerifyApiKeyResponse{Valid: true, UserId: UserID}, nil
+}
+
+// CreateNote stores a note; uploads become placeholder media entries.
+func (s *Server) CreateNote(_ context.Context, req *proto.CreateNoteRequest) (*proto.CreateNoteResponse, error) {
+	if err := checkUser(req.GetUserId()); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.GetContent()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "content is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := &proto.Note{Content: req.GetContent(), CreatedAt: timestamppb.Now()}
+	for i := range req.GetImages() {
+		n.Images = append(n.Images, &proto.NoteImage{Url: "https://media.invalid/image/" + strconv.Itoa(i)})
+	}
+	for i := range req.GetAudios() {
+		n.Audios = append(n.Audios, &proto.NoteAudio{Url: "https://media.invalid/audio/" + strconv.Itoa(i)})
+	}
+	return &proto.CreateNoteResponse{Note: cloneNote(s.insert(n))}, nil
+}
+
+// GetNote returns one note.
+func (s *Server) GetNote(_ context.Context, req *proto.GetNoteRequest) (*proto.GetNoteResponse, error) {
+	if err := checkUser(req.GetUserId()); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.find(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if s.OnGetNote != nil {
+		s.OnGetNote(s.notes[i])
+	}
+	return &proto.GetNoteResponse{Note: cloneNote(s.notes[i])}, nil
+}
+
+// ListNotes returns up to Limit notes, newest first, optionally filtered by a
+// case-insensitive Search substring.
+func (s *Server) ListNotes(_ context.Context, req *proto.ListNotesRequest) (*proto.ListNotesResponse, error) {
+	if err := checkUser(req.GetUserId()); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	notes := s.notes
+	if q := strings.ToLower(req.GetSearch()); q != "" {
+		notes = nil
+		for _, n := range s.notes {
+			if strings.Contains(strings.ToLower(n.GetContent()), q) {
+				notes = append(notes, n)
+			}
+		}
+	}
+	return &proto.ListNotesResponse{Notes: cloneNotes(notes, int(req.GetLimit()))}, nil
+}
+
+// UpdateNote replaces content and, when UpdateTags is set, tags.
+func (s *Server) UpdateNote(_ context.Context, req *proto.UpdateNoteRequest) (*proto.UpdateNoteResponse, error) {
+	if err := checkUser(req.GetUserId()); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.find(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	n := s.notes[i]
+	if req.Content != nil {
+		n.Content = req.GetContent()
+	}
+	if req.GetUpdateTags() {
+		n.Tags = req.GetTags()
+	}
+	return &proto.UpdateNoteResponse{Note: cloneNote(n)}, nil
+}
+
+// DeleteNote removes a note.
+func (s *Server) DeleteNote(_ context.Context, req *proto.DeleteNoteRequest) (*proto.DeleteNoteResponse, error) {
+	if err := checkUser(req.GetUserId()); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.find(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	s.notes = append(s.notes[:i], s.notes[i+1:]...)
+	return &proto.DeleteNoteResponse{}, nil
+}
+
+// GetRandomNotes returns up to Count distinct notes in random order.
+func (s *Server) GetRandomNotes(_ context.Context, req *proto.GetRandomNotesRequest) (*proto.GetRandomNotesResponse, error) {
+	if err := checkUser(req.GetUserId()); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	shuffled := cloneNotes(s.notes, len(s.notes))
+	rand.Shuffle(len(shuffled), func(a, b int) { shuffled[a], shuffled[b] = shuffled[b], shuffled[a] })
+	return &proto.GetRandomNotesResponse{Notes: shuffled[:min(int(req.GetCount()), len(shuffled))]}, nil
+}
+
+// ListTags counts tag usage across notes.
+func (s *Server) ListTags(_ context.Context, req *proto.ListTagsRequest) (*proto.ListTagsResponse, error) {
+	if err := checkUser(req.GetUserId()); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counts := map[string]int32{}
+	for _, n := range s.notes {
+		for _, t := range n.GetTags() {
+			counts[t]++
+		}
+	}
+	tags := make([]*proto.Tag, 0, len(counts))
+	for name, c := range counts {
+		tags = append(tags, &proto.Tag{Id: name, Name: name, Count: c})
+	}
+	sort.Slice(tags, func(a, b int) bool { return tags[a].GetName() < tags[b].GetName() })
+	return &proto.ListTagsResponse{Tags: tags}, nil
+}
+
+// GetStats computes stats; an empty UserId means global, which for the
+// single-user fake is the same.
+func (s *Server) GetStats(_ context.Context, req *proto.GetStatsRequest) (*proto.GetStatsResponse, error) {
+	if req.GetUserId() != "" {
+		if err := checkUser(req.GetUserId()); err != nil {
+			return nil, err
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tags := map[string]bool{}
+	var words int64
+	for _, n := range s.notes {
+		words += int64(len(strings.Fields(n.GetContent())))
+		for _, t := range n.GetTags() {
+			tags[t] = true
+		}
+	}
+	return &proto.GetStatsResponse{
+		TotalBlips:   int64(len(s.notes)),
+		UniqueTags:   int64(len(tags)),
+		WordsWritten: words,
+	}, nil
+}
+
+// cloneNote copies the fields the fake serves so responses don't alias the store.
+func cloneNote(n *proto.Note) *proto.Note {
+	out := &proto.Note{
+		Id:        n.GetId(),
+		Content:   n.GetContent(),
+		Tags:      append([]string(nil), n.GetTags()...),
+		CreatedAt: n.GetCreatedAt(),
+	}
+	for _, img := range n.GetImages() {
+		out.Images = append(out.Images, &proto.NoteImage{Url: img.GetUrl(), ExtractedText: img.GetExtractedText()})
+	}
+	for _, aud := range n.GetAudios() {
+		out.Audios = append(out.Audios, &proto.NoteAudio{Url: aud.GetUrl(), TranscribedText: aud.GetTranscribedText()})
+	}
+	return out
+}
+
+// cloneNotes copies up to limit notes; a non-positive limit returns none.
+func cloneNotes(notes []*proto.Note, limit int) []*proto.Note {
+	limit = max(min(limit, len(notes)), 0)
+	out := make([]*proto.Note, 0, limit)
+	for _, n := range notes[:limit] {
+		out = append(out, cloneNote(n))
+	}
+	return out
+}
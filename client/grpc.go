@@ -2,7 +2,6 @@ package client
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	"strings"
 	"sync"
@@ -10,7 +9,6 @@ import (
 
 	"github.com/icco/etu-backend/proto"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 )
 
 // notesToPosts converts a slice of proto Notes to client Posts.
@@ -56,8 +54,10 @@ func protoTagsToTags(tags []*proto.Tag) []Tag {
 }
 
 // apiKeyCreds attaches the etu API key to every gRPC request.
+// insecure relaxes the transport security requirement for plaintext loopback dials.
 type apiKeyCreds struct {
-	apiKey string
+	apiKey   string
+	insecure bool
 }
 
 func (a apiKeyCreds) GetRequestMetadata(_ context.Context, _ ...string) (map[string]string, error) {
@@ -71,7 +71,7 @@ func (a apiKeyCreds) GetRequestMetadata(_ context.Context, _ ...string) (map[str
 }
 
 func (a apiKeyCreds) RequireTransportSecurity() bool {
-	return true
+	return !a.insecure
 }
 
 // grpcClients holds the gRPC connection and service clients (lazy-init).
@@ -81,11 +81,12 @@ type grpcClients struct {
 	apiKeysClient proto.ApiKeysServiceClient
 	tagsClient    proto.TagsServiceClient
 	statsClient   proto.StatsServiceClient
-	userID        string
 	connOnce      sync.Once
-	userIDOnce    sync.Once
 	connErr       error
-	userIDErr     error
+
+	// userIDMu guards userID, resolved lazily by ensureUserID.
+	userIDMu sync.Mutex
+	userID   string
 }
 
 func (c *Config) getGRPCClients() (*grpcClients, error) {
@@ -97,10 +98,19 @@ func (c *Config) getGRPCClients() (*grpcClients, error) {
 		c.grpc = &grpcClients{}
 	}
 	c.grpc.connOnce.Do(func() {
-		creds := credentials.NewTLS(&tls.Config{})
+		creds, err := c.transportCredentials()
+		if err != nil {
+			c.grpc.connErr = err
+			return
+		}
 		opts := []grpc.DialOption{
 			grpc.WithTransportCredentials(creds),
-			grpc.WithPerRPCCredentials(apiKeyCreds{apiKey: c.APIKey}),
+			grpc.WithPerRPCCredentials(apiKeyCreds{apiKey: c.APIKey, insecure: c.Insecure}),
+			grpc.WithUnaryInterceptor(c.unaryInterceptor),
+			grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(c.maxMessageSize())),
+		}
+		if c.Dialer != nil {
+			opts = append(opts, grpc.WithContextDialer(c.Dialer))
 		}
 		c.grpc.conn, c.grpc.connErr = grpc.NewClient(c.GRPCTarget, opts...)
 		if c.grpc.connErr != nil {
@@ -116,29 +126,3 @@ func (c *Config) getGRPCClients() (*grpcClients, error) {
 	}
 	return c.grpc, nil
 }
-
-// ensureUserID calls VerifyApiKey and caches user_id for use in note requests.
-func (c *Config) ensureUserID(ctx context.Context) (string, error) {
-	g, err := c.getGRPCClients()
-	if err != nil {
-		return "", err
-	}
-	g.userIDOnce.Do(func() {
-		resp, err := g.apiKeysClient.VerifyApiKey(ctx, &proto.VerifyApiKeyRequest{
-			RawKey: c.APIKey,
-		})
-		if err != nil {
-			g.userIDErr = err
-			return
-		}
-		if !resp.GetValid() {
-			g.userIDErr = fmt.Errorf("API key invalid")
-			return
-		}
-		g.userID = resp.GetUserId()
-	})
-	if g.userIDErr != nil {
-		return "", g.userIDErr
-	}
-	return g.userID, nil
-}
@@ -39,6 +39,11 @@ func TestApiKeyCredsRequireTransportSecurity(t *testing.T) {
 	if !creds.RequireTransportSecurity() {
 		t.Error("RequireTransportSecurity() = false, want true")
 	}
+
+	insecureCreds := apiKeyCreds{apiKey: "test", insecure: true}
+	if insecureCreds.RequireTransportSecurity() {
+		t.Error("RequireTransportSecurity() = true for insecure creds, want false")
+	}
 }
 
 func TestNoteToPost(t *testing.T) {
@@ -0,0 +1,320 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Revision ops.
+const (
+	OpEdit   = "edit"
+	OpTags   = "tags"
+	OpDelete = "delete"
+	OpUndo   = "undo"
+)
+
+// ErrNothingToUndo is returned by Undo when every change has been undone.
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// ErrChangedSince is returned by Undo when the entry was changed again after
+// the revision, so undoing it would throw the later change away.
+var ErrChangedSince = errors.New("entry has changed since")
+
+// Revision records an entry as it was before a change, so the change can be
+// undone.
+type Revision struct {
+	// ID numbers revisions in the order they were made, from 1.
+	ID   int       `json:"id"`
+	Time time.Time `json:"time"`
+	// Op is the change: OpEdit, OpTags, OpDelete, or OpUndo for undoing
+	// Undoes.
+	Op     string `json:"op"`
+	PostID string `json:"post_id"`
+	// Content, Tags and CreatedAt are the entry before the change.
+	Content   string    `json:"content"`
+	Tags      []string  `json:"tags,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// Attachments are the URLs of the entry's images and audio.
+	Attachments []string `json:"attachments,omitempty"`
+	Undoes      int      `json:"undoes,omitempty"`
+	// NewID is the entry an undone delete was recreated as.
+	NewID string `json:"new_id,omitempty"`
+	// After fingerprints what an edit or tag change left, so Undo can tell
+	// whether the entry changed again since. See changeHash.
+	After string `json:"after,omitempty"`
+}
+
+// History is a Journal that records entries before UpdatePost, SetPostTags
+// and DeletePost change them, in a file under ConfigDir(), so the changes
+// can be undone.
+type History struct {
+	Journal
+	mu sync.Mutex
+}
+
+var _ Journal = (*History)(nil)
+
+// NewHistory records j's destructive changes.
+func NewHistory(j Journal) *History {
+	return &History{Journal: j}
+}
+
+// HistoryPath is where revisions are kept.
+func HistoryPath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history.jsonl"), nil
+}
+
+// UpdatePost updates the entry and records what it replaced.
+func (h *History) UpdatePost(ctx context.Context, pageID, content string) (*Post, error) {
+	before, err := h.Journal.GetPost(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+	post, err := h.Journal.UpdatePost(ctx, pageID, content)
+	if err != nil || before.Text == content {
+		return post, err
+	}
+	r := preImage(OpEdit, before)
+	r.After = changeHash(OpEdit, &Post{Text: content})
+	if post != nil {
+		r.After = changeHash(OpEdit, post)
+	}
+	if _, err := h.record(r); err != nil {
+		return post, fmt.Errorf("updated, but recording undo history failed: %w", err)
+	}
+	return post, nil
+}
+
+// SetPostTags replaces the entry's tags and records the ones it had.
+func (h *History) SetPostTags(ctx context.Context, pageID string, tags []string) (*Post, error) {
+	before, err := h.Journal.GetPost(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+	post, err := h.Journal.SetPostTags(ctx, pageID, tags)
+	if err != nil || slices.Equal(before.Tags, tags) {
+		return post, err
+	}
+	r := preImage(OpTags, before)
+	r.After = changeHash(OpTags, &Post{Tags: tags})
+	if post != nil {
+		r.After = changeHash(OpTags, post)
+	}
+	if _, err := h.record(r); err != nil {
+		return post, fmt.Errorf("updated tags, but recording undo history failed: %w", err)
+	}
+	return post, nil
+}
+
+// DeletePost deletes the entry and records it.
+func (h *History) DeletePost(ctx context.Context, pageID string) error {
+	before, err := h.Journal.GetPost(ctx, pageID)
+	if err != nil {
+		return err
+	}
+	if err := h.Journal.DeletePost(ctx, pageID); err != nil {
+		return err
+	}
+	if _, err := h.record(preImage(OpDelete, before)); err != nil {
+		return fmt.Errorf("deleted, but recording undo history failed: %w", err)
+	}
+	return nil
+}
+
+func preImage(op string, p *Post) Revision {
+	r := Revision{Op: op, PostID: p.PageID, Content: p.Text, Tags: p.Tags, CreatedAt: p.CreatedAt}
+	for _, img := range p.Images {
+		r.Attachments = append(r.Attachments, img.GetUrl())
+	}
+	for _, aud := range p.Audios {
+		r.Attachments = append(r.Attachments, aud.GetUrl())
+	}
+	return r
+}
+
+// changeHash fingerprints the part of p that op changes: the text for an
+// edit, the tags for a tag change.
+func changeHash(op string, p *Post) string {
+	h := sha256.New()
+	switch op {
+	case OpEdit:
+		h.Write([]byte(p.Text))
+	case OpTags:
+		tags := slices.Clone(p.Tags)
+		slices.Sort(tags)
+		for _, t := range tags {
+			h.Write([]byte(t + "\x00"))
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// Revisions returns every recorded revision, oldest first.
+func (h *History) Revisions() ([]Revision, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.revisions()
+}
+
+func (h *History) revisions() (revs []Revision, err error) {
+	path, err := HistoryPath()
+	if err != nil {
+		return nil, err
+	}
+	// path is built from ConfigDir() (fixed config dir under user home), not external input.
+	f, err := os.Open(path) //nolint:gosec // G304: path is from fixed config dir, not user-controlled
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 16<<20)
+	for sc.Scan() {
+		var r Revision
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("read history: %w", err)
+		}
+		revs = append(revs, r)
+	}
+	return revs, sc.Err()
+}
+
+// record appends r to the history, numbering it after the last revision.
+func (h *History) record(r Revision) (Revision, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	revs, err := h.revisions()
+	if err != nil {
+		return r, err
+	}
+	r.ID = 1
+	if len(revs) > 0 {
+		r.ID = revs[len(revs)-1].ID + 1
+	}
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	line, err := json.Marshal(r)
+	if err != nil {
+		return r, err
+	}
+	path, err := HistoryPath()
+	if err != nil {
+		return r, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return r, err
+	}
+	// path is built from ConfigDir() (fixed config dir under user home), not external input.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) //nolint:gosec // G304: path is from fixed config dir, not user-controlled
+	if err != nil {
+		return r, err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return r, err
+	}
+	return r, f.Close()
+}
+
+// Undoable returns the edits and deletes not yet undone, newest first.
+func Undoable(revs []Revision) []Revision {
+	undone := map[int]bool{}
+	for _, r := range revs {
+		if r.Op == OpUndo {
+			undone[r.Undoes] = true
+		}
+	}
+	var out []Revision
+	for i := len(revs) - 1; i >= 0; i-- {
+		if r := revs[i]; r.Op != OpUndo && !undone[r.ID] {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Undo reverses revision id, or the latest change not yet undone if id is
+// 0: an edit puts the earlier content back, a tag change the earlier tags,
+// and a delete recreates the entry with its content and tags. It returns
+// the OpUndo revision recorded.
+//
+// An edit or tag change made to the entry after the revision would be lost,
+// so Undo returns ErrChangedSince instead unless force is set. Revisions
+// recorded before After was kept aren't checked.
+//
+// A recreated entry gets a new ID, is dated now since the backend can't set
+// timestamps, and doesn't get its attachments back.
+func (h *History) Undo(ctx context.Context, id int, force bool) (Revision, error) {
+	revs, err := h.Revisions()
+	if err != nil {
+		return Revision{}, err
+	}
+	undoable := Undoable(revs)
+	var target *Revision
+	for i := range undoable {
+		if id == 0 || undoable[i].ID == id {
+			target = &undoable[i]
+			break
+		}
+	}
+	if target == nil {
+		if id == 0 {
+			return Revision{}, ErrNothingToUndo
+		}
+		return Revision{}, fmt.Errorf("revision %d doesn't exist or is already undone", id)
+	}
+
+	undo := Revision{Op: OpUndo, Undoes: target.ID, PostID: target.PostID}
+	var current *Post
+	if target.Op == OpEdit || target.Op == OpTags {
+		if current, err = h.Journal.GetPost(ctx, target.PostID); err != nil {
+			return Revision{}, err
+		}
+		if !force && target.After != "" && changeHash(target.Op, current) != target.After {
+			return Revision{}, fmt.Errorf("can't undo revision %d of %s: %w it was made", target.ID, target.PostID, ErrChangedSince)
+		}
+	}
+	switch target.Op {
+	case OpEdit:
+		if _, err := h.Journal.UpdatePost(ctx, target.PostID, target.Content); err != nil {
+			return Revision{}, err
+		}
+		undo.Content, undo.Tags, undo.CreatedAt = current.Text, current.Tags, current.CreatedAt
+	case OpTags:
+		if _, err := h.Journal.SetPostTags(ctx, target.PostID, target.Tags); err != nil {
+			return Revision{}, err
+		}
+		undo.Content, undo.Tags, undo.CreatedAt = current.Text, current.Tags, current.CreatedAt
+	case OpDelete:
+		post, err := h.Journal.SaveEntry(WithTags(ctx, target.Tags...), target.Content, nil, nil)
+		if err != nil {
+			return Revision{}, err
+		}
+		undo.NewID, undo.CreatedAt = post.PageID, post.CreatedAt
+	default:
+		return Revision{}, fmt.Errorf("can't undo a %s", target.Op)
+	}
+	return h.record(undo)
+}
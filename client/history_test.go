@@ -0,0 +1,189 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// mapJournal keeps entries in a map, for History.
+type mapJournal struct {
+	Journal
+	posts map[string]*Post
+	saved int
+}
+
+func (j *mapJournal) GetPost(_ context.Context, pageID string) (*Post, error) {
+	p, ok := j.posts[pageID]
+	if !ok {
+		return nil, fmt.Errorf("entry %s not found", pageID)
+	}
+	cp := *p
+	return &cp, nil
+}
+
+func (j *mapJournal) UpdatePost(_ context.Context, pageID, content string) (*Post, error) {
+	p, ok := j.posts[pageID]
+	if !ok {
+		return nil, fmt.Errorf("entry %s not found", pageID)
+	}
+	p.Text = content
+	return p, nil
+}
+
+func (j *mapJournal) SetPostTags(_ context.Context, pageID string, tags []string) (*Post, error) {
+	p, ok := j.posts[pageID]
+	if !ok {
+		return nil, fmt.Errorf("entry %s not found", pageID)
+	}
+	p.Tags = tags
+	return p, nil
+}
+
+func (j *mapJournal) DeletePost(_ context.Context, pageID string) error {
+	delete(j.posts, pageID)
+	return nil
+}
+
+func (j *mapJournal) SaveEntry(ctx context.Context, text string, _, _ []string) (*Post, error) {
+	j.saved++
+	p := &Post{PageID: fmt.Sprintf("new%d", j.saved), Text: text, Tags: EntryTags(ctx), CreatedAt: time.Now()}
+	j.posts[p.PageID] = p
+	return p, nil
+}
+
+func TestHistoryUndoEdit(t *testing.T) {
+	setTestHome(t)
+	ctx := context.Background()
+	j := &mapJournal{posts: map[string]*Post{"a": {PageID: "a", Text: "first", Tags: []string{"work"}}}}
+	h := NewHistory(j)
+
+	for _, text := range []string{"second", "second", "third"} {
+		if _, err := h.UpdatePost(ctx, "a", text); err != nil {
+			t.Fatal(err)
+		}
+	}
+	revs, err := h.Revisions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Saving unchanged text isn't a revision.
+	if len(revs) != 2 || revs[0].ID != 1 || revs[0].Content != "first" || revs[1].ID != 2 || revs[1].Content != "second" {
+		t.Fatalf("revisions = %+v", revs)
+	}
+	if !reflect.DeepEqual(revs[0].Tags, []string{"work"}) || revs[0].Op != OpEdit {
+		t.Errorf("revision 1 = %+v", revs[0])
+	}
+
+	undo, err := h.Undo(ctx, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if undo.Undoes != 2 || undo.Content != "third" || j.posts["a"].Text != "second" {
+		t.Errorf("undo = %+v, text %q; want revision 2 undone back to second", undo, j.posts["a"].Text)
+	}
+	if _, err := h.Undo(ctx, 2, false); err == nil {
+		t.Error("undoing revision 2 twice: want error")
+	}
+	if _, err := h.Undo(ctx, 0, false); err != nil || j.posts["a"].Text != "first" {
+		t.Errorf("second undo: text %q, %v; want first", j.posts["a"].Text, err)
+	}
+	if _, err := h.Undo(ctx, 0, false); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("third undo = %v, want ErrNothingToUndo", err)
+	}
+}
+
+func TestHistoryUndoTags(t *testing.T) {
+	setTestHome(t)
+	ctx := context.Background()
+	j := &mapJournal{posts: map[string]*Post{"a": {PageID: "a", Text: "x", Tags: []string{"work"}}}}
+	h := NewHistory(j)
+
+	if _, err := h.SetPostTags(ctx, "a", []string{"work", "idea"}); err != nil {
+		t.Fatal(err)
+	}
+	if revs := mustRevisions(t, h); len(revs) != 1 || revs[0].Op != OpTags || !reflect.DeepEqual(revs[0].Tags, []string{"work"}) {
+		t.Fatalf("revisions = %+v", revs)
+	}
+	undo, err := h.Undo(ctx, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(j.posts["a"].Tags, []string{"work"}) || !reflect.DeepEqual(undo.Tags, []string{"work", "idea"}) {
+		t.Errorf("tags after undo = %v, undo = %+v", j.posts["a"].Tags, undo)
+	}
+}
+
+func TestHistoryUndoChangedSince(t *testing.T) {
+	setTestHome(t)
+	ctx := context.Background()
+	j := &mapJournal{posts: map[string]*Post{"a": {PageID: "a", Text: "first", Tags: []string{"work"}}}}
+	h := NewHistory(j)
+
+	if _, err := h.UpdatePost(ctx, "a", "second"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.SetPostTags(ctx, "a", []string{"idea"}); err != nil {
+		t.Fatal(err)
+	}
+	// Changed elsewhere, so there is no revision for it.
+	j.posts["a"].Text = "second, edited on the web"
+	j.posts["a"].Tags = []string{"idea", "todo"}
+
+	for _, id := range []int{2, 1} {
+		if _, err := h.Undo(ctx, id, false); !errors.Is(err, ErrChangedSince) {
+			t.Errorf("undo %d = %v, want ErrChangedSince", id, err)
+		}
+	}
+	if p := j.posts["a"]; p.Text != "second, edited on the web" || !reflect.DeepEqual(p.Tags, []string{"idea", "todo"}) {
+		t.Fatalf("entry after refused undo = %+v, want it untouched", p)
+	}
+	if _, err := h.Undo(ctx, 1, true); err != nil || j.posts["a"].Text != "first" {
+		t.Errorf("forced undo: text %q, %v; want first", j.posts["a"].Text, err)
+	}
+}
+
+func TestHistoryUndoDelete(t *testing.T) {
+	setTestHome(t)
+	ctx := context.Background()
+	written := time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC)
+	j := &mapJournal{posts: map[string]*Post{"a": {PageID: "a", Text: "gone", Tags: []string{"x", "y"}, CreatedAt: written}}}
+	h := NewHistory(j)
+
+	if err := h.DeletePost(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.DeletePost(ctx, "missing"); err == nil {
+		t.Error("deleting a missing entry: want error")
+	}
+	if got := Undoable(mustRevisions(t, h)); len(got) != 1 || got[0].Op != OpDelete || !got[0].CreatedAt.Equal(written) {
+		t.Fatalf("undoable = %+v", got)
+	}
+
+	undo, err := h.Undo(ctx, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := j.posts[undo.NewID]
+	if undo.NewID != "new1" || p == nil || p.Text != "gone" || !reflect.DeepEqual(p.Tags, []string{"x", "y"}) {
+		t.Fatalf("undo = %+v, recreated %+v", undo, p)
+	}
+	if undo.CreatedAt.Equal(written) {
+		t.Error("CreatedAt = the original time, want the time it was recreated")
+	}
+	if got := Undoable(mustRevisions(t, h)); len(got) != 0 {
+		t.Errorf("undoable after undo = %+v", got)
+	}
+}
+
+func mustRevisions(t *testing.T, h *History) []Revision {
+	t.Helper()
+	revs, err := h.Revisions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return revs
+}
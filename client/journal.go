@@ -0,0 +1,26 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// Journal is the set of journal operations the CLI needs from a backend.
+// *Config implements it over gRPC; the fake package has in-memory and
+// in-process gRPC implementations for tests.
+type Journal interface {
+	SaveEntry(ctx context.Context, text string, imagePaths, audioPaths []string) (*Post, error)
+	UpdatePost(ctx context.Context, pageID, content string) (*Post, error)
+	SetPostTags(ctx context.Context, pageID string, tags []string) (*Post, error)
+	DeletePost(ctx context.Context, pageID string) error
+	ListPosts(ctx context.Context, count int) ([]*Post, error)
+	SearchPosts(ctx context.Context, query string, maxResults int) ([]*Post, error)
+	GetRandomPosts(ctx context.Context, count int) ([]*Post, error)
+	GetPost(ctx context.Context, pageID string) (*Post, error)
+	GetPostFullContent(ctx context.Context, pageID string) (string, error)
+	ListTags(ctx context.Context) ([]Tag, error)
+	GetStats(ctx context.Context, global bool) (Stats, error)
+	TimeSinceLastPost(ctx context.Context) (time.Duration, error)
+}
+
+var _ Journal = (*Config)(nil)
@@ -0,0 +1,270 @@
+package client
+
+import (
+	"context"
+	"encoding/gob"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// linkPattern matches references to other entries in entry text:
+// [[note-id]] or a day as [[2006-01-02]].
+var linkPattern = regexp.MustCompile(`\[\[([^\[\]\s]+)\]\]`)
+
+// ParseLinks returns the references in text, in order and without repeats.
+func ParseLinks(text string) []string {
+	var refs []string
+	for _, m := range linkPattern.FindAllStringSubmatch(text, -1) {
+		if !slices.Contains(refs, m[1]) {
+			refs = append(refs, m[1])
+		}
+	}
+	return refs
+}
+
+// ReplaceLinks calls replace for each reference in text and substitutes
+// what it returns for the whole [[...]].
+func ReplaceLinks(text string, replace func(ref string) string) string {
+	return linkPattern.ReplaceAllStringFunc(text, func(m string) string {
+		return replace(m[2 : len(m)-2])
+	})
+}
+
+// LinkDay reports whether ref is a day reference and which day, in loc.
+func LinkDay(ref string, loc *time.Location) (time.Time, bool) {
+	day, err := time.ParseInLocation("2006-01-02", ref, loc)
+	return day, err == nil
+}
+
+// IndexEntry is what the link index keeps about an entry.
+type IndexEntry struct {
+	ID        string
+	CreatedAt time.Time
+	// Title is the entry's first non-empty line.
+	Title string
+	// Links are the references in the entry's text.
+	Links []string
+}
+
+// LinkIndex records the references between entries, cached on disk so
+// backlinks don't need every entry fetched each time.
+type LinkIndex struct {
+	// Saved is when the index was built from the backend.
+	Saved   time.Time
+	Entries map[string]IndexEntry
+}
+
+// NewLinkIndex indexes posts.
+func NewLinkIndex(posts []*Post) *LinkIndex {
+	x := &LinkIndex{Saved: time.Now(), Entries: make(map[string]IndexEntry, len(posts))}
+	for _, p := range posts {
+		x.Add(p)
+	}
+	return x
+}
+
+// Add indexes p, replacing what was known about it.
+func (x *LinkIndex) Add(p *Post) {
+	title := ""
+	for _, line := range strings.Split(p.Text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			title = line
+			break
+		}
+	}
+	x.Entries[p.PageID] = IndexEntry{ID: p.PageID, CreatedAt: p.CreatedAt, Title: title, Links: ParseLinks(p.Text)}
+}
+
+// Remove drops an entry from the index.
+func (x *LinkIndex) Remove(id string) {
+	delete(x.Entries, id)
+}
+
+// Resolve returns the entries ref points to, oldest first: the entry with
+// that ID, or the entries written that day in loc.
+func (x *LinkIndex) Resolve(ref string, loc *time.Location) []IndexEntry {
+	if e, ok := x.Entries[ref]; ok {
+		return []IndexEntry{e}
+	}
+	day, ok := LinkDay(ref, loc)
+	if !ok {
+		return nil
+	}
+	var out []IndexEntry
+	for _, e := range x.Entries {
+		if t := e.CreatedAt.In(loc); !t.Before(day) && t.Before(day.AddDate(0, 0, 1)) {
+			out = append(out, e)
+		}
+	}
+	sortEntries(out)
+	return out
+}
+
+// Backlinks returns the entries that refer to id, by ID or by the day it
+// was written in loc, oldest first.
+func (x *LinkIndex) Backlinks(id string, loc *time.Location) []IndexEntry {
+	day := ""
+	if e, ok := x.Entries[id]; ok {
+		day = e.CreatedAt.In(loc).Format("2006-01-02")
+	}
+	var out []IndexEntry
+	for _, e := range x.Entries {
+		if e.ID != id && (slices.Contains(e.Links, id) || (day != "" && slices.Contains(e.Links, day))) {
+			out = append(out, e)
+		}
+	}
+	sortEntries(out)
+	return out
+}
+
+func sortEntries(entries []IndexEntry) {
+	sort.Slice(entries, func(a, b int) bool {
+		if !entries[a].CreatedAt.Equal(entries[b].CreatedAt) {
+			return entries[a].CreatedAt.Before(entries[b].CreatedAt)
+		}
+		return entries[a].ID < entries[b].ID
+	})
+}
+
+// RefreshLinkIndex returns the cached index, rebuilding it from every entry
+// first if it is missing or older than maxAge.
+func RefreshLinkIndex(ctx context.Context, j Journal, maxAge time.Duration) (*LinkIndex, error) {
+	x, err := LoadLinkIndex()
+	if err != nil || x == nil || time.Since(x.Saved) > maxAge {
+		posts, _, err := ListPostsBetween(ctx, j, time.Time{}, time.Time{})
+		if err != nil {
+			return nil, err
+		}
+		x = NewLinkIndex(posts)
+		if err := SaveLinkIndex(x); err != nil {
+			return x, err
+		}
+	}
+	return x, nil
+}
+
+// LinkIndexer is a Journal that keeps the cached link index in step with the
+// entries it creates, edits and deletes, so it doesn't wait for a rebuild to
+// show them. Without a cached index it does nothing; the next
+// RefreshLinkIndex builds one.
+type LinkIndexer struct {
+	Journal
+	mu sync.Mutex
+}
+
+var _ Journal = (*LinkIndexer)(nil)
+
+// NewLinkIndexer indexes j's changes.
+func NewLinkIndexer(j Journal) *LinkIndexer {
+	return &LinkIndexer{Journal: j}
+}
+
+// SaveEntry saves the entry and indexes it.
+func (l *LinkIndexer) SaveEntry(ctx context.Context, text string, imagePaths, audioPaths []string) (*Post, error) {
+	post, err := l.Journal.SaveEntry(ctx, text, imagePaths, audioPaths)
+	if err == nil && post != nil {
+		l.update(func(x *LinkIndex) { x.Add(post) })
+	}
+	return post, err
+}
+
+// UpdatePost updates the entry and reindexes it.
+func (l *LinkIndexer) UpdatePost(ctx context.Context, pageID, content string) (*Post, error) {
+	post, err := l.Journal.UpdatePost(ctx, pageID, content)
+	if err == nil {
+		l.update(func(x *LinkIndex) {
+			p := &Post{PageID: pageID, CreatedAt: x.Entries[pageID].CreatedAt, Text: content}
+			if post != nil {
+				p = post
+			}
+			x.Add(p)
+		})
+	}
+	return post, err
+}
+
+// DeletePost deletes the entry and drops it from the index.
+func (l *LinkIndexer) DeletePost(ctx context.Context, pageID string) error {
+	err := l.Journal.DeletePost(ctx, pageID)
+	if err == nil {
+		l.update(func(x *LinkIndex) { x.Remove(pageID) })
+	}
+	return err
+}
+
+// update applies change to the cached index, if there is one, keeping when
+// it was built. Failures are only logged since the change itself was made.
+func (l *LinkIndexer) update(change func(x *LinkIndex)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	x, err := LoadLinkIndex()
+	if err == nil && x != nil {
+		change(x)
+		err = SaveLinkIndex(x)
+	}
+	if err != nil {
+		log.Printf("etu: updating link cache: %v", err)
+	}
+}
+
+func linkIndexPath() (string, error) {
+	return CachePath("links.cache")
+}
+
+// LoadLinkIndex reads the cached link index; it returns nil if there is none.
+func LoadLinkIndex() (x *LinkIndex, err error) {
+	path, err := linkIndexPath()
+	if err != nil {
+		return nil, err
+	}
+	// path is built from CachePath() (fixed config dir under user home), not external input.
+	f, err := os.Open(path) //nolint:gosec // G304: path is from fixed config dir, not user-controlled
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+	x = &LinkIndex{}
+	if err := gob.NewDecoder(f).Decode(x); err != nil {
+		return nil, err
+	}
+	if x.Entries == nil {
+		x.Entries = map[string]IndexEntry{}
+	}
+	return x, nil
+}
+
+// SaveLinkIndex writes the link index to the cache.
+func SaveLinkIndex(x *LinkIndex) (err error) {
+	path, err := linkIndexPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	// path is built from CachePath() (fixed config dir under user home), not external input.
+	f, err := os.Create(path) //nolint:gosec // G304: path is from fixed config dir, not user-controlled
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+	return gob.NewEncoder(f).Encode(x)
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// linkedJournal is a Journal that only lists, for RefreshLinkIndex.
+type linkedJournal struct {
+	Journal
+	posts []*Post
+	lists int
+}
+
+func (j *linkedJournal) ListPosts(_ context.Context, count int) ([]*Post, error) {
+	j.lists++
+	return j.posts[:min(count, len(j.posts))], nil
+}
+
+func TestParseLinks(t *testing.T) {
+	got := ParseLinks("See [[abc]] and [[2026-03-14]], again [[abc]]. Not [[two words]] or [single].")
+	if want := []string{"abc", "2026-03-14"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseLinks = %v, want %v", got, want)
+	}
+	if got := ReplaceLinks("a [[x]] b", func(ref string) string { return "<" + ref + ">" }); got != "a <x> b" {
+		t.Errorf("ReplaceLinks = %q", got)
+	}
+}
+
+// mapJournal from history_test.go also lists, for LinkIndexer.
+func (j *mapJournal) ListPosts(_ context.Context, count int) ([]*Post, error) {
+	var out []*Post
+	for _, p := range j.posts {
+		out = append(out, p)
+	}
+	return out[:min(count, len(out))], nil
+}
+
+func TestLinkIndexer(t *testing.T) {
+	setTestHome(t)
+	ctx := context.Background()
+	j := &mapJournal{posts: map[string]*Post{"a": {PageID: "a", Text: "the original"}}}
+	l := NewLinkIndexer(j)
+
+	// Without a cached index there is nothing to keep in step.
+	if _, err := l.SaveEntry(ctx, "before the index", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if x, err := LoadLinkIndex(); err != nil || x != nil {
+		t.Fatalf("index = %v, %v; want none", x, err)
+	}
+
+	built, err := RefreshLinkIndex(ctx, j, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	post, err := l.SaveEntry(ctx, "follow-up to [[a]]", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.UpdatePost(ctx, "a", "the original, renamed"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.DeletePost(ctx, "new1"); err != nil {
+		t.Fatal(err)
+	}
+
+	x, err := LoadLinkIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := x.Backlinks("a", time.UTC); len(got) != 1 || got[0].ID != post.PageID {
+		t.Errorf("Backlinks(a) = %v, want the new entry", got)
+	}
+	if e := x.Entries["a"]; e.Title != "the original, renamed" {
+		t.Errorf("entry a = %+v, want the edited title", e)
+	}
+	if _, ok := x.Entries["new1"]; ok {
+		t.Error("deleted entry still indexed")
+	}
+	if !x.Saved.Equal(built.Saved) {
+		t.Errorf("Saved = %v, want %v kept so the index still expires", x.Saved, built.Saved)
+	}
+}
+
+func TestLinkIndex(t *testing.T) {
+	setTestHome(t)
+	loc := time.UTC
+	at := func(d, h int) time.Time { return time.Date(2026, 3, d, h, 0, 0, 0, loc) }
+	posts := []*Post{
+		{PageID: "c", Text: "about that day: [[2026-03-10]]", CreatedAt: at(12, 9)},
+		{PageID: "b", Text: "\n  Follow-up to [[a]]\n\nmore", CreatedAt: at(11, 9)},
+		{PageID: "a", Text: "the original", CreatedAt: at(10, 9)},
+		{PageID: "a2", Text: "same day", CreatedAt: at(10, 18)},
+	}
+	j := &linkedJournal{posts: posts}
+
+	x, err := RefreshLinkIndex(context.Background(), j, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := x.Entries["b"]; e.Title != "Follow-up to [[a]]" || !reflect.DeepEqual(e.Links, []string{"a"}) {
+		t.Errorf("entry b = %+v", e)
+	}
+	if got := x.Resolve("2026-03-10", loc); len(got) != 2 || got[0].ID != "a" || got[1].ID != "a2" {
+		t.Errorf("Resolve(day) = %v, want a then a2", got)
+	}
+	if got := x.Resolve("missing", loc); got != nil {
+		t.Errorf("Resolve(missing) = %v", got)
+	}
+	if got := x.Backlinks("a", loc); len(got) != 2 || got[0].ID != "b" || got[1].ID != "c" {
+		t.Errorf("Backlinks(a) = %v, want b by ID and c by day", got)
+	}
+	if got := x.Backlinks("a2", loc); len(got) != 1 || got[0].ID != "c" {
+		t.Errorf("Backlinks(a2) = %v, want c", got)
+	}
+
+	// A fresh cache is used as is; a stale one is rebuilt.
+	if _, err := RefreshLinkIndex(context.Background(), j, time.Hour); err != nil || j.lists != 1 {
+		t.Errorf("fresh cache: lists = %d, err = %v; want no new listing", j.lists, err)
+	}
+	if _, err := RefreshLinkIndex(context.Background(), j, 0); err != nil || j.lists != 2 {
+		t.Errorf("stale cache: lists = %d, err = %v; want a new listing", j.lists, err)
+	}
+}
@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"encoding/gob"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// listBatch is the first limit ListPostsBetween asks for; it doubles
+	// until the range is covered.
+	listBatch = 200
+	// maxListLimit caps how many entries ListPostsBetween fetches.
+	maxListLimit = 20000
+)
+
+// ListPostsBetween returns the entries created in [from, to), newest first.
+// The backend can only list the newest entries, so it asks for more until
+// it gets past from or runs out; a zero from means all entries. It also
+// reports how far back the result is complete, which is from unless
+// maxListLimit cut the listing short.
+func ListPostsBetween(ctx context.Context, j Journal, from, to time.Time) ([]*Post, time.Time, error) {
+	for limit := listBatch; ; limit *= 2 {
+		limit = min(limit, maxListLimit)
+		posts, err := j.ListPosts(ctx, limit)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		exhausted := len(posts) < limit
+		pastFrom := len(posts) > 0 && !from.IsZero() && posts[len(posts)-1].CreatedAt.Before(from)
+		if exhausted || pastFrom || limit == maxListLimit {
+			complete := from
+			if !exhausted && !pastFrom {
+				complete = posts[len(posts)-1].CreatedAt
+			}
+			return PostsBetween(posts, from, to), complete, nil
+		}
+	}
+}
+
+// PostsBetween keeps the posts created in [from, to); zero bounds are open.
+func PostsBetween(posts []*Post, from, to time.Time) []*Post {
+	var out []*Post
+	for _, p := range posts {
+		if !from.IsZero() && p.CreatedAt.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !p.CreatedAt.Before(to) {
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// dayCountsTTL is how long cached day counts are shown before they are
+// dropped, so edits and deletes in months not revisited don't linger.
+const dayCountsTTL = 24 * time.Hour
+
+// DayCounts is how many entries were written on each day, cached on disk so
+// date views can draw at once and refresh in the background.
+type DayCounts struct {
+	// Saved is when the counts were computed.
+	Saved time.Time
+	// Zone is the timezone the days are in.
+	Zone string
+	// From is how far back the counts are complete.
+	From time.Time
+	// Counts maps dates as "2006-01-02" to entry counts.
+	Counts map[string]int
+}
+
+// CountByDay counts posts per day in loc.
+func CountByDay(posts []*Post, loc *time.Location) map[string]int {
+	counts := map[string]int{}
+	for _, p := range posts {
+		counts[p.CreatedAt.In(loc).Format("2006-01-02")]++
+	}
+	return counts
+}
+
+func dayCountsPath() (string, error) {
+	return CachePath("daycounts.cache")
+}
+
+// LoadDayCounts reads the cached day counts; it returns nil if there are none
+// or they are older than dayCountsTTL.
+func LoadDayCounts() (counts *DayCounts, err error) {
+	path, err := dayCountsPath()
+	if err != nil {
+		return nil, err
+	}
+	// path is built from CachePath() (fixed config dir under user home), not external input.
+	f, err := os.Open(path) //nolint:gosec // G304: path is from fixed config dir, not user-controlled
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+	var dc DayCounts
+	if err := gob.NewDecoder(f).Decode(&dc); err != nil {
+		return nil, err
+	}
+	if time.Since(dc.Saved) > dayCountsTTL {
+		return nil, nil
+	}
+	return &dc, nil
+}
+
+// SaveDayCounts writes day counts to the cache.
+func SaveDayCounts(counts *DayCounts) (err error) {
+	path, err := dayCountsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	// path is built from CachePath() (fixed config dir under user home), not external input.
+	f, err := os.Create(path) //nolint:gosec // G304: path is from fixed config dir, not user-controlled
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+	return gob.NewEncoder(f).Encode(counts)
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDayCountsRoundTrip(t *testing.T) {
+	setTestHome(t)
+
+	if got, err := LoadDayCounts(); err != nil || got != nil {
+		t.Fatalf("LoadDayCounts with no cache = %v, %v; want nil, nil", got, err)
+	}
+	loc := time.FixedZone("UTC+9", 9*60*60)
+	posts := []*Post{
+		{CreatedAt: time.Date(2026, 3, 13, 16, 0, 0, 0, time.UTC)}, // the 14th at 01:00 in loc
+		{CreatedAt: time.Date(2026, 3, 14, 2, 0, 0, 0, time.UTC)},
+		{CreatedAt: time.Date(2026, 3, 12, 2, 0, 0, 0, time.UTC)},
+	}
+	in := &DayCounts{Saved: time.Now().Round(0), Zone: loc.String(), Counts: CountByDay(posts, loc)}
+	if want := map[string]int{"2026-03-14": 2, "2026-03-12": 1}; !reflect.DeepEqual(in.Counts, want) {
+		t.Errorf("CountByDay = %v, want %v", in.Counts, want)
+	}
+	if err := SaveDayCounts(in); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadDayCounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Zone != in.Zone || !got.Saved.Equal(in.Saved) || !reflect.DeepEqual(got.Counts, in.Counts) {
+		t.Errorf("LoadDayCounts = %+v, want %+v", got, in)
+	}
+
+	in.Saved = time.Now().Add(-dayCountsTTL - time.Hour)
+	if err := SaveDayCounts(in); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := LoadDayCounts(); err != nil || got != nil {
+		t.Errorf("LoadDayCounts of stale counts = %+v, %v; want nil", got, err)
+	}
+}
+
+func TestViewCountsRoundTrip(t *testing.T) {
+	setTestHome(t)
+
+	views, err := LoadViewCounts()
+	if err != nil || len(views) != 0 {
+		t.Fatalf("LoadViewCounts with no cache = %v, %v; want empty", views, err)
+	}
+	views["a"] += 2
+	views["b"]++
+	if err := SaveViewCounts(views); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadViewCounts()
+	if err != nil || !reflect.DeepEqual(got, views) {
+		t.Errorf("LoadViewCounts = %v, %v; want %v", got, err, views)
+	}
+}
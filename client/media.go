@@ -0,0 +1,396 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // register GIF decoding for downscaling
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultMaxMessageSize matches gRPC's default server receive limit.
+	defaultMaxMessageSize = 4 << 20
+	// messageOverhead reserves room for the note text, IDs and proto framing.
+	messageOverhead = 64 << 10
+	// defaultImageQuality is the JPEG quality used when re-encoding images.
+	defaultImageQuality = 85
+	// progressChunk is how much of a file is read between progress reports.
+	progressChunk = 256 << 10
+)
+
+// UploadProgress reports how far an attachment has been read and prepared.
+// It stops at the request: sending the entry isn't measured.
+type UploadProgress struct {
+	Path  string
+	Done  int64
+	Total int64
+	// Note describes processing applied to the file, e.g. "resized to 2048x1536".
+	Note string
+}
+
+type uploadProgressKey struct{}
+
+// WithUploadProgress returns a context that makes SaveEntry call fn as it
+// reads and prepares each attachment.
+func WithUploadProgress(ctx context.Context, fn func(UploadProgress)) context.Context {
+	return context.WithValue(ctx, uploadProgressKey{}, fn)
+}
+
+func uploadProgressFrom(ctx context.Context) func(UploadProgress) {
+	if fn, ok := ctx.Value(uploadProgressKey{}).(func(UploadProgress)); ok && fn != nil {
+		return fn
+	}
+	return func(UploadProgress) {}
+}
+
+type imageOptionsKey struct{}
+
+// imageOverrides replace the configured image settings for one call.
+type imageOverrides struct {
+	maxDimension int
+	quality      int
+}
+
+// WithImageOptions returns a context that makes SaveEntry downscale images so
+// their longer side is at most maxDimension and re-encode them at quality,
+// in place of the config's settings. Zero keeps the configured value.
+func WithImageOptions(ctx context.Context, maxDimension, quality int) context.Context {
+	return context.WithValue(ctx, imageOptionsKey{}, imageOverrides{maxDimension: maxDimension, quality: quality})
+}
+
+// mediaOptions controls attachment size checks and image re-encoding.
+type mediaOptions struct {
+	// maxBytes is the largest attachment payload allowed; zero disables the check.
+	maxBytes int64
+	// imageMaxDimension downscales images whose longer side exceeds it; zero keeps size.
+	imageMaxDimension int
+	// imageQuality is the JPEG quality for re-encoded images.
+	imageQuality int
+	progress     func(UploadProgress)
+}
+
+// maxMessageSize returns the largest gRPC message the backend is assumed to accept.
+func (c *Config) maxMessageSize() int {
+	if c.MaxMessageSize > 0 {
+		return c.MaxMessageSize
+	}
+	return defaultMaxMessageSize
+}
+
+// MaxAttachmentBytes is how much of a message attachments may fill. It is at
+// least one byte, since zero or less would turn the size check off.
+func (c *Config) MaxAttachmentBytes() int64 {
+	return int64(max(c.maxMessageSize()-messageOverhead, 1))
+}
+
+// mediaOptions returns attachment options derived from c for a call on ctx.
+func (c *Config) mediaOptions(ctx context.Context) mediaOptions {
+	dim, quality := c.ImageMaxDimension, c.ImageQuality
+	if o, ok := ctx.Value(imageOptionsKey{}).(imageOverrides); ok {
+		if o.maxDimension > 0 {
+			dim = o.maxDimension
+		}
+		if o.quality > 0 {
+			quality = o.quality
+		}
+	}
+	if quality <= 0 || quality > 100 {
+		quality = defaultImageQuality
+	}
+	return mediaOptions{
+		maxBytes:          c.MaxAttachmentBytes(),
+		imageMaxDimension: dim,
+		imageQuality:      quality,
+		progress:          uploadProgressFrom(ctx),
+	}
+}
+
+func (o mediaOptions) report(p UploadProgress) {
+	if o.progress != nil {
+		o.progress(p)
+	}
+}
+
+// FormatBytes renders n as a human-readable size such as "3.2 MB".
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// readAttachment reads path in chunks, reporting progress, after checking its
+// size against opts.maxBytes. Images may still be shrunk later, so kind
+// "image" only fails up front when no downscaling is configured.
+func readAttachment(path, kind string, opts mediaOptions) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s %s: %w", kind, path, err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("read %s %s: is a directory", kind, path)
+	}
+	size := info.Size()
+	canShrink := kind == "image" && opts.imageMaxDimension > 0
+	if opts.maxBytes > 0 && size > opts.maxBytes && !canShrink {
+		hint := "trim it or split it across entries"
+		if kind == "image" {
+			hint = "set image_max_dimension or pass --image-max-dimension to downscale it"
+		}
+		return nil, fmt.Errorf("%s %s is %s, over the backend's %s limit per entry: %s",
+			kind, filepath.Base(path), FormatBytes(size), FormatBytes(opts.maxBytes), hint)
+	}
+
+	// Paths come from CLI flags supplied by the user; reading them is the intent.
+	f, err := os.Open(path) //nolint:gosec // G304: user-supplied CLI input
+	if err != nil {
+		return nil, fmt.Errorf("read %s %s: %w", kind, path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var buf bytes.Buffer
+	buf.Grow(int(size))
+	opts.report(UploadProgress{Path: path, Total: size})
+	for {
+		_, err := io.CopyN(&buf, f, progressChunk)
+		opts.report(UploadProgress{Path: path, Done: int64(buf.Len()), Total: size})
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read %s %s: %w", kind, path, err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// prepareImage downscales data when it is a decodable image larger than
+// opts.imageMaxDimension, re-encoding as PNG for PNG sources and JPEG otherwise.
+// Formats Go can't decode (HEIC, WebP, ...) are passed through untouched.
+func prepareImage(data []byte, mimeType string, opts mediaOptions) ([]byte, string, string) {
+	if opts.imageMaxDimension <= 0 {
+		return data, mimeType, ""
+	}
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return data, mimeType, ""
+	}
+	b := src.Bounds()
+	if max(b.Dx(), b.Dy()) <= opts.imageMaxDimension {
+		return data, mimeType, ""
+	}
+	dst := downscale(src, opts.imageMaxDimension)
+
+	var out bytes.Buffer
+	outMIME := "image/jpeg"
+	if format == "png" {
+		outMIME = "image/png"
+		err = png.Encode(&out, dst)
+	} else {
+		err = jpeg.Encode(&out, dst, &jpeg.Options{Quality: opts.imageQuality})
+	}
+	if err != nil {
+		return data, mimeType, ""
+	}
+	db := dst.Bounds()
+	return out.Bytes(), outMIME, fmt.Sprintf("resized to %dx%d", db.Dx(), db.Dy())
+}
+
+// downscale shrinks src so its longer side is maxDim, averaging each
+// destination pixel over the source pixels it covers.
+func downscale(src image.Image, maxDim int) *image.RGBA {
+	sb := src.Bounds()
+	sw, sh := sb.Dx(), sb.Dy()
+	dw, dh := maxDim, maxDim
+	if sw >= sh {
+		dh = max(1, sh*maxDim/sw)
+	} else {
+		dw = max(1, sw*maxDim/sh)
+	}
+
+	rgba := image.NewRGBA(image.Rect(0, 0, sw, sh))
+	draw.Draw(rgba, rgba.Bounds(), src, sb.Min, draw.Src)
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := range dh {
+		y0, y1 := y*sh/dh, max((y+1)*sh/dh, y*sh/dh+1)
+		for x := range dw {
+			x0, x1 := x*sw/dw, max((x+1)*sw/dw, x*sw/dw+1)
+			var r, g, bl, a, n int
+			for sy := y0; sy < y1; sy++ {
+				row := rgba.Pix[sy*rgba.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += int(p[0])
+					g += int(p[1])
+					bl += int(p[2])
+					a += int(p[3])
+					n++
+				}
+			}
+			o := dst.PixOffset(x, y)
+			dst.Pix[o] = uint8(r / n)
+			dst.Pix[o+1] = uint8(g / n)
+			dst.Pix[o+2] = uint8(bl / n)
+			dst.Pix[o+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// checkTotalSize fails when the combined payload would exceed the message limit.
+func checkTotalSize(sizes map[string]int64, maxBytes int64) error {
+	if maxBytes <= 0 {
+		return nil
+	}
+	var total int64
+	for _, n := range sizes {
+		total += n
+	}
+	if total <= maxBytes {
+		return nil
+	}
+	parts := make([]string, 0, len(sizes))
+	for p, n := range sizes {
+		parts = append(parts, fmt.Sprintf("%s (%s)", filepath.Base(p), FormatBytes(n)))
+	}
+	sort.Strings(parts)
+	return fmt.Errorf("attachments total %s, over the backend's %s limit per entry: %s",
+		FormatBytes(total), FormatBytes(maxBytes), strings.Join(parts, ", "))
+}
+
+// AttachmentInfo describes a local file checked by InspectAttachment.
+type AttachmentInfo struct {
+	Path     string
+	MIMEType string
+	Size     int64
+	// Width and Height are set for images whose format Go can decode.
+	Width, Height int
+	// Duration is set for audio whose length is in its header (WAV).
+	Duration time.Duration
+	// Note describes processing SaveEntry will apply, e.g. downscaling.
+	Note string
+}
+
+// InspectAttachment checks that path can be attached as kind ("image" or
+// "audio") before saving: it must exist, be readable, have a matching MIME
+// type and fit the backend's size limit. Image options set on ctx with
+// WithImageOptions apply as they would in SaveEntry.
+func (c *Config) InspectAttachment(ctx context.Context, path, kind string) (*AttachmentInfo, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no such file")
+		}
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("is a directory")
+	}
+	// Paths come from the create form; reading them is the intent.
+	f, err := os.Open(path) //nolint:gosec // G304: user-supplied CLI input
+	if err != nil {
+		return nil, fmt.Errorf("not readable: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	head := make([]byte, 4096)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("not readable: %w", err)
+	}
+	head = head[:n]
+
+	a := &AttachmentInfo{Path: path, MIMEType: detectMIME(head, path), Size: info.Size()}
+	if !isMIMEKind(a.MIMEType, kind) {
+		what := "audio"
+		if kind == "image" {
+			what = "an image"
+		}
+		return nil, fmt.Errorf("not %s (%s)", what, a.MIMEType)
+	}
+
+	opts := c.mediaOptions(ctx)
+	if opts.maxBytes > 0 && a.Size > opts.maxBytes {
+		if kind != "image" || opts.imageMaxDimension <= 0 {
+			return nil, fmt.Errorf("%s is over the backend's %s limit", FormatBytes(a.Size), FormatBytes(opts.maxBytes))
+		}
+		a.Note = "over the size limit; will be downscaled"
+	}
+
+	switch kind {
+	case "image":
+		if _, err := f.Seek(0, io.SeekStart); err == nil {
+			if ic, _, err := image.DecodeConfig(f); err == nil {
+				a.Width, a.Height = ic.Width, ic.Height
+				if a.Note == "" && opts.imageMaxDimension > 0 && max(a.Width, a.Height) > opts.imageMaxDimension {
+					a.Note = "will be downscaled"
+				}
+			}
+		}
+	case "audio":
+		a.Duration = wavDuration(head)
+	}
+	return a, nil
+}
+
+// isMIMEKind reports whether mimeType is acceptable for an attachment of kind.
+// Audio includes the container types sniffing reports for m4a, ogg and webm.
+func isMIMEKind(mimeType, kind string) bool {
+	mimeType, _, _ = strings.Cut(mimeType, ";")
+	switch kind {
+	case "image":
+		return strings.HasPrefix(mimeType, "image/")
+	case "audio":
+		switch mimeType {
+		case "application/ogg", "video/mp4", "video/webm":
+			return true
+		}
+		return strings.HasPrefix(mimeType, "audio/")
+	}
+	return false
+}
+
+// wavDuration reads the length of a WAV file from the start of its data,
+// returning zero when head isn't a WAV header it understands.
+func wavDuration(head []byte) time.Duration {
+	if len(head) < 12 || string(head[:4]) != "RIFF" || string(head[8:12]) != "WAVE" {
+		return 0
+	}
+	var byteRate uint32
+	for off := 12; off+8 <= len(head); {
+		id := string(head[off : off+4])
+		size := binary.LittleEndian.Uint32(head[off+4:])
+		body := off + 8
+		switch id {
+		case "fmt ":
+			if body+12 <= len(head) {
+				byteRate = binary.LittleEndian.Uint32(head[body+8:])
+			}
+		case "data":
+			if byteRate == 0 {
+				return 0
+			}
+			return time.Duration(uint64(size) * uint64(time.Second) / uint64(byteRate))
+		}
+		off = body + int(size) + int(size%2)
+	}
+	return 0
+}
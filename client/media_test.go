@@ -0,0 +1,304 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeTestPNG(t *testing.T, w, h int) string {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "big.png")
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{512, "512 B"},
+		{1536, "1.5 KB"},
+		{4 << 20, "4.0 MB"},
+		{3 << 30, "3.0 GB"},
+	}
+	for _, tt := range tests {
+		if got := FormatBytes(tt.n); got != tt.want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestReadAttachmentTooLarge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memo.mp3")
+	if err := os.WriteFile(path, make([]byte, 2048), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var reported bool
+	opts := mediaOptions{maxBytes: 1024, progress: func(UploadProgress) { reported = true }}
+	_, err := readAttachment(path, "audio", opts)
+	if err == nil {
+		t.Fatal("expected size error")
+	}
+	if !strings.Contains(err.Error(), "memo.mp3 is 2.0 KB") {
+		t.Errorf("error = %q, want file name and size", err)
+	}
+	if reported {
+		t.Error("file was read despite exceeding the limit")
+	}
+}
+
+func TestReadAttachmentReportsProgress(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memo.wav")
+	data := make([]byte, progressChunk*2+10)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var last UploadProgress
+	var calls int
+	got, err := readAttachment(path, "audio", mediaOptions{progress: func(p UploadProgress) {
+		last = p
+		calls++
+	}})
+	if err != nil {
+		t.Fatalf("readAttachment: %v", err)
+	}
+	if len(got) != len(data) {
+		t.Errorf("read %d bytes, want %d", len(got), len(data))
+	}
+	if calls < 3 {
+		t.Errorf("got %d progress reports, want at least 3", calls)
+	}
+	if last.Done != last.Total || last.Total != int64(len(data)) {
+		t.Errorf("final progress = %+v, want complete", last)
+	}
+}
+
+func TestPrepareImageDownscales(t *testing.T) {
+	path := writeTestPNG(t, 400, 200)
+	data, err := os.ReadFile(path) //nolint:gosec // test fixture
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("png stays png", func(t *testing.T) {
+		out, mimeType, note := prepareImage(data, "image/png", mediaOptions{imageMaxDimension: 100, imageQuality: 85})
+		if mimeType != "image/png" {
+			t.Errorf("MIME = %q, want image/png", mimeType)
+		}
+		if note != "resized to 100x50" {
+			t.Errorf("note = %q", note)
+		}
+		cfg, err := png.DecodeConfig(bytes.NewReader(out))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Width != 100 || cfg.Height != 50 {
+			t.Errorf("size = %dx%d, want 100x50", cfg.Width, cfg.Height)
+		}
+	})
+
+	t.Run("small enough", func(t *testing.T) {
+		out, _, note := prepareImage(data, "image/png", mediaOptions{imageMaxDimension: 1000})
+		if note != "" || !bytes.Equal(out, data) {
+			t.Error("image within limit should pass through unchanged")
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		out, _, _ := prepareImage(data, "image/png", mediaOptions{})
+		if !bytes.Equal(out, data) {
+			t.Error("image should pass through when downscaling is disabled")
+		}
+	})
+
+	t.Run("undecodable", func(t *testing.T) {
+		junk := []byte("not an image")
+		out, mimeType, _ := prepareImage(junk, "image/heic", mediaOptions{imageMaxDimension: 10})
+		if !bytes.Equal(out, junk) || mimeType != "image/heic" {
+			t.Error("undecodable image should pass through unchanged")
+		}
+	})
+}
+
+func TestPrepareImageJPEG(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 50, 300))
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	out, mimeType, _ := prepareImage(buf.Bytes(), "image/jpeg", mediaOptions{imageMaxDimension: 60, imageQuality: 70})
+	if mimeType != "image/jpeg" {
+		t.Errorf("MIME = %q, want image/jpeg", mimeType)
+	}
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Width != 10 || cfg.Height != 60 {
+		t.Errorf("size = %dx%d, want 10x60", cfg.Width, cfg.Height)
+	}
+}
+
+func TestCheckTotalSize(t *testing.T) {
+	sizes := map[string]int64{"/a/one.png": 600, "/b/two.mp3": 600}
+	if err := checkTotalSize(sizes, 2000); err != nil {
+		t.Errorf("unexpected error under limit: %v", err)
+	}
+	err := checkTotalSize(sizes, 1000)
+	if err == nil {
+		t.Fatal("expected error over limit")
+	}
+	if !strings.Contains(err.Error(), "one.png (600 B), two.mp3 (600 B)") {
+		t.Errorf("error = %q, want per-file sizes", err)
+	}
+	if err := checkTotalSize(sizes, 0); err != nil {
+		t.Errorf("zero limit should disable the check: %v", err)
+	}
+}
+
+func TestSaveEntryRejectsOversizedBeforeUpload(t *testing.T) {
+	c, b := startFlakyBackend(t, 0)
+	c.MaxMessageSize = messageOverhead + 1024
+
+	path := filepath.Join(t.TempDir(), "long.wav")
+	if err := os.WriteFile(path, make([]byte, 4096), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.SaveEntry(context.Background(), "memo", nil, []string{path}); err == nil {
+		t.Fatal("expected size error")
+	}
+	if len(b.keys) != 0 {
+		t.Errorf("CreateNote called %d times, want 0", len(b.keys))
+	}
+}
+
+func TestSaveEntryDownscalesToFit(t *testing.T) {
+	c, b := startFlakyBackend(t, 0)
+	path := writeTestPNG(t, 800, 800)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.MaxMessageSize = messageOverhead + int(info.Size())/2
+	c.ImageMaxDimension = 64
+
+	if _, err := c.SaveEntry(context.Background(), "screenshot", []string{path}, nil); err != nil {
+		t.Fatalf("SaveEntry: %v", err)
+	}
+	if len(b.keys) != 1 {
+		t.Errorf("CreateNote called %d times, want 1", len(b.keys))
+	}
+}
+
+func TestInspectAttachment(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	png := writeTestPNG(t, 300, 200)
+	var wav bytes.Buffer
+	wav.WriteString("RIFF\x00\x00\x00\x00WAVEfmt ")
+	_ = binary.Write(&wav, binary.LittleEndian, []uint32{16})
+	_ = binary.Write(&wav, binary.LittleEndian, []uint16{1, 1})
+	_ = binary.Write(&wav, binary.LittleEndian, []uint32{8000, 16000})
+	_ = binary.Write(&wav, binary.LittleEndian, []uint16{2, 16})
+	wav.WriteString("data")
+	_ = binary.Write(&wav, binary.LittleEndian, []uint32{48000})
+	wavPath := write("memo.wav", wav.Bytes())
+	notes := write("notes.txt", []byte("plain text, not a picture"))
+	big := write("big.mp3", append([]byte("ID3"), make([]byte, 4096)...))
+
+	tests := []struct {
+		name     string
+		cfg      Config
+		maxDim   int
+		path     string
+		kind     string
+		wantErr  string
+		wantW    int
+		wantDur  time.Duration
+		wantNote string
+	}{
+		{name: "image", path: png, kind: "image", wantW: 300},
+		{name: "image to downscale", cfg: Config{ImageMaxDimension: 100}, path: png, kind: "image", wantW: 300, wantNote: "will be downscaled"},
+		{name: "wav", path: wavPath, kind: "audio", wantDur: 3 * time.Second},
+		{name: "missing", path: filepath.Join(dir, "nope.png"), kind: "image", wantErr: "no such file"},
+		{name: "directory", path: dir, kind: "image", wantErr: "is a directory"},
+		{name: "text as image", path: notes, kind: "image", wantErr: "not an image (text/plain"},
+		{name: "image as audio", path: png, kind: "audio", wantErr: "not audio (image/png)"},
+		{name: "too large", cfg: Config{MaxMessageSize: messageOverhead + 1024}, path: big, kind: "audio", wantErr: "over the backend's 1.0 KB limit"},
+		{name: "image too large", cfg: Config{MaxMessageSize: messageOverhead + 512}, path: png, kind: "image", wantErr: "over the backend's 512 B limit"},
+		{name: "image too large to downscale", cfg: Config{MaxMessageSize: messageOverhead + 512}, maxDim: 100, path: png, kind: "image", wantW: 300, wantNote: "over the size limit; will be downscaled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := WithImageOptions(context.Background(), tt.maxDim, 0)
+			info, err := tt.cfg.InspectAttachment(ctx, tt.path, tt.kind)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("InspectAttachment: %v", err)
+			}
+			if info.Width != tt.wantW || info.Duration != tt.wantDur || info.Note != tt.wantNote {
+				t.Errorf("info = %+v", info)
+			}
+		})
+	}
+}
+
+func TestMediaOptionsLimits(t *testing.T) {
+	for _, tt := range []struct {
+		size int
+		want int64
+	}{
+		{0, defaultMaxMessageSize - messageOverhead},
+		{1 << 20, 1<<20 - messageOverhead},
+		{32 << 10, 1},
+	} {
+		c := &Config{MaxMessageSize: tt.size}
+		if got := c.mediaOptions(context.Background()).maxBytes; got != tt.want {
+			t.Errorf("max_message_size %d: maxBytes = %d, want %d", tt.size, got, tt.want)
+		}
+	}
+
+	c := &Config{ImageMaxDimension: 2048, ImageQuality: 70}
+	opts := c.mediaOptions(WithImageOptions(context.Background(), 512, 0))
+	if opts.imageMaxDimension != 512 || opts.imageQuality != 70 {
+		t.Errorf("with overrides = %+v, want 512 and the configured quality", opts)
+	}
+}
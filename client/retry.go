@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+	crand "crypto/rand"
+	"encoding/hex"
+	"math/rand/v2"
+	"path"
+	"slices"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultCallTimeout = 15 * time.Second
+	defaultMaxRetries  = 3
+	retryBaseDelay     = 200 * time.Millisecond
+	retryMaxDelay      = 5 * time.Second
+
+	// idempotencyKeyHeader carries a per-entry key a backend can use to drop
+	// duplicate CreateNote calls. The backend isn't known to honor it, so
+	// retries don't rely on it.
+	idempotencyKeyHeader = "idempotency-key"
+)
+
+// idempotentMethods are read-only RPCs that are always safe to retry.
+var idempotentMethods = map[string]bool{
+	"ListNotes":      true,
+	"GetNote":        true,
+	"GetRandomNotes": true,
+	"ListTags":       true,
+	"GetStats":       true,
+	"VerifyApiKey":   true,
+}
+
+// callTimeout returns the per-attempt deadline applied to calls without one.
+func (c *Config) callTimeout() time.Duration {
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+	return defaultCallTimeout
+}
+
+// maxRetries returns how many times a failed retryable call is retried.
+// Zero means the default; a negative value disables retries.
+func (c *Config) maxRetries() int {
+	switch {
+	case c.MaxRetries < 0:
+		return 0
+	case c.MaxRetries == 0:
+		return defaultMaxRetries
+	default:
+		return c.MaxRetries
+	}
+}
+
+// newIdempotencyKey returns a random key identifying one logical write.
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	_, _ = crand.Read(b) // crypto/rand.Read never returns an error
+	return hex.EncodeToString(b)
+}
+
+// withIdempotencyKey attaches a fresh idempotency key to outgoing metadata.
+func withIdempotencyKey(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, idempotencyKeyHeader, newIdempotencyKey())
+}
+
+// isRetryable reports whether method may be retried after err. Read-only
+// calls are retried on any transient failure. Writes are only retried when
+// the backend was unavailable before the request was sent, since a write
+// that timed out may still have been applied.
+func isRetryable(method string, err error, sent bool) bool {
+	code := status.Code(err)
+	if idempotentMethods[path.Base(method)] {
+		return code == codes.Unavailable || code == codes.DeadlineExceeded || code == codes.ResourceExhausted
+	}
+	return code == codes.Unavailable && !sent
+}
+
+// backoff returns the delay before retry attempt n (0-based), using
+// exponential growth capped at retryMaxDelay with full jitter.
+func backoff(n int) time.Duration {
+	d := retryBaseDelay << n
+	if d <= 0 || d > retryMaxDelay {
+		d = retryMaxDelay
+	}
+	return rand.N(d) //nolint:gosec // G404: jitter does not need a CSPRNG
+}
+
+// unaryInterceptor applies the default per-attempt deadline, retries
+// retryable failures with backoff, and maps the final error to a
+// user-facing one.
+func (c *Config) unaryInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	retries := c.maxRetries()
+	var err error
+	for attempt := 0; ; attempt++ {
+		var sent bool
+		sent, err = c.invokeOnce(ctx, method, req, reply, cc, invoker, opts...)
+		if err == nil {
+			return nil
+		}
+		if attempt >= retries || ctx.Err() != nil || !isRetryable(method, err, sent) {
+			break
+		}
+		t := time.NewTimer(backoff(attempt))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return newRPCError(method, c, ctx.Err())
+		case <-t.C:
+		}
+	}
+	return newRPCError(method, c, err)
+}
+
+// invokeOnce runs a single attempt, bounded by callTimeout unless ctx already
+// has an earlier deadline. sent reports whether the request got a stream to
+// the backend; gRPC only fills in the peer once it has one.
+func (c *Config) invokeOnce(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) (sent bool, err error) {
+	var p peer.Peer
+	opts = append(slices.Clip(opts), grpc.Peer(&p))
+	timeout := c.callTimeout()
+	if dl, ok := ctx.Deadline(); ok && time.Until(dl) < timeout {
+		err = invoker(ctx, method, req, reply, cc, opts...)
+		return p.Addr != nil, err
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err = invoker(attemptCtx, method, req, reply, cc, opts...)
+	return p.Addr != nil, err
+}
@@ -0,0 +1,261 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// flakyBackend fails the first `failures` calls of each RPC with Unavailable.
+type flakyBackend struct {
+	proto.UnimplementedNotesServiceServer
+	proto.UnimplementedApiKeysServiceServer
+	proto.UnimplementedTagsServiceServer
+
+	mu       sync.Mutex
+	failures int
+	calls    map[string]int
+	keys     []string
+	delay    time.Duration
+}
+
+func (b *flakyBackend) attempt(method string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.calls[method]++
+	if b.calls[method] <= b.failures {
+		return status.Error(codes.Unavailable, "try again")
+	}
+	return nil
+}
+
+func (b *flakyBackend) VerifyApiKey(_ context.Context, _ *proto.VerifyApiKeyRequest) (*proto.VerifyApiKeyResponse, error) {
+	return &proto.VerifyApiKeyResponse{Valid: true, UserId: "u1"}, nil
+}
+
+func (b *flakyBackend) ListTags(ctx context.Context, _ *proto.ListTagsRequest) (*proto.ListTagsResponse, error) {
+	if b.delay > 0 {
+		select {
+		case <-time.After(b.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if err := b.attempt("ListTags"); err != nil {
+		return nil, err
+	}
+	return &proto.ListTagsResponse{Tags: []*proto.Tag{{Name: "work", Count: 1}}}, nil
+}
+
+func (b *flakyBackend) CreateNote(ctx context.Context, _ *proto.CreateNoteRequest) (*proto.CreateNoteResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	b.mu.Lock()
+	b.keys = append(b.keys, strings.Join(md.Get(idempotencyKeyHeader), ","))
+	b.mu.Unlock()
+	if err := b.attempt("CreateNote"); err != nil {
+		return nil, err
+	}
+	return &proto.CreateNoteResponse{Note: &proto.Note{Id: "n1"}}, nil
+}
+
+func (b *flakyBackend) DeleteNote(_ context.Context, _ *proto.DeleteNoteRequest) (*proto.DeleteNoteResponse, error) {
+	if err := b.attempt("DeleteNote"); err != nil {
+		return nil, err
+	}
+	return &proto.DeleteNoteResponse{}, nil
+}
+
+func startFlakyBackend(t *testing.T, failures int) (*Config, *flakyBackend) {
+	t.Helper()
+	setTestHome(t)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	b := &flakyBackend{failures: failures, calls: map[string]int{}}
+	proto.RegisterNotesServiceServer(srv, b)
+	proto.RegisterApiKeysServiceServer(srv, b)
+	proto.RegisterTagsServiceServer(srv, b)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	return &Config{APIKey: "k", GRPCTarget: lis.Addr().String(), Insecure: true}, b
+}
+
+func TestRetryIdempotentCall(t *testing.T) {
+	c, b := startFlakyBackend(t, 2)
+
+	tags, err := c.ListTags(context.Background())
+	if err != nil {
+		t.Fatalf("ListTags: %v", err)
+	}
+	if len(tags) != 1 {
+		t.Errorf("got %d tags, want 1", len(tags))
+	}
+	if b.calls["ListTags"] != 3 {
+		t.Errorf("ListTags called %d times, want 3", b.calls["ListTags"])
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	c, b := startFlakyBackend(t, 10)
+	c.MaxRetries = 1
+
+	_, err := c.ListTags(context.Background())
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("code = %v, want Unavailable (err %v)", status.Code(err), err)
+	}
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("expected *RPCError, got %T", err)
+	}
+	if !strings.Contains(err.Error(), "cannot reach the backend") {
+		t.Errorf("error = %q, want actionable message", err)
+	}
+	if b.calls["ListTags"] != 2 {
+		t.Errorf("ListTags called %d times, want 2", b.calls["ListTags"])
+	}
+}
+
+func TestNoRetryForCreateNoteThatReachedBackend(t *testing.T) {
+	c, b := startFlakyBackend(t, 1)
+
+	if _, err := c.SaveEntry(context.Background(), "hello", nil, nil); status.Code(err) != codes.Unavailable {
+		t.Fatalf("SaveEntry err = %v, want Unavailable without a retry", err)
+	}
+	if len(b.keys) != 1 || b.keys[0] == "" {
+		t.Errorf("CreateNote keys = %q, want one call with an idempotency key", b.keys)
+	}
+}
+
+func TestInvokeOnceReportsSent(t *testing.T) {
+	c, _ := startFlakyBackend(t, 1)
+	invoke := func(t *testing.T, target string) (bool, error) {
+		t.Helper()
+		cc, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = cc.Close() }()
+		plain := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return cc.Invoke(ctx, method, req, reply, opts...)
+		}
+		return c.invokeOnce(context.Background(), "/etu.NotesService/DeleteNote",
+			&proto.DeleteNoteRequest{}, &proto.DeleteNoteResponse{}, cc, plain)
+	}
+
+	if sent, err := invoke(t, c.GRPCTarget); status.Code(err) != codes.Unavailable || !sent {
+		t.Errorf("backend answering Unavailable: sent = %v, err = %v; want sent", sent, err)
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := lis.Addr().String()
+	_ = lis.Close()
+	if sent, err := invoke(t, closed); status.Code(err) != codes.Unavailable || sent {
+		t.Errorf("nothing listening: sent = %v, err = %v; want not sent", sent, err)
+	}
+}
+
+func TestNoRetryForNonIdempotentCall(t *testing.T) {
+	c, b := startFlakyBackend(t, 1)
+
+	if err := c.DeletePost(context.Background(), "n1"); err == nil {
+		t.Fatal("expected DeletePost to fail without retry")
+	}
+	if b.calls["DeleteNote"] != 1 {
+		t.Errorf("DeleteNote called %d times, want 1", b.calls["DeleteNote"])
+	}
+}
+
+func TestDefaultDeadline(t *testing.T) {
+	c, b := startFlakyBackend(t, 0)
+	b.delay = time.Second
+	c.Timeout = 50 * time.Millisecond
+	c.MaxRetries = -1
+
+	start := time.Now()
+	_, err := c.ListTags(context.Background())
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("code = %v, want DeadlineExceeded (err %v)", status.Code(err), err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("call took %v, want it bounded by the 50ms timeout", elapsed)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "down")
+	deadline := status.Error(codes.DeadlineExceeded, "slow")
+
+	tests := []struct {
+		name   string
+		method string
+		err    error
+		sent   bool
+		want   bool
+	}{
+		{"read unavailable", "/etu.NotesService/ListNotes", unavailable, true, true},
+		{"read deadline", "/etu.NotesService/ListNotes", deadline, true, true},
+		{"read not found", "/etu.NotesService/GetNote", status.Error(codes.NotFound, ""), true, false},
+		{"read unauthenticated", "/etu.TagsService/ListTags", status.Error(codes.Unauthenticated, ""), true, false},
+		{"write unavailable before sending", "/etu.NotesService/CreateNote", unavailable, false, true},
+		{"write unavailable after sending", "/etu.NotesService/CreateNote", unavailable, true, false},
+		{"write deadline", "/etu.NotesService/CreateNote", deadline, false, false},
+		{"delete unavailable after sending", "/etu.NotesService/DeleteNote", unavailable, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.method, tt.err, tt.sent); got != tt.want {
+				t.Errorf("isRetryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoffBounds(t *testing.T) {
+	for n := range 20 {
+		d := backoff(n)
+		if d < 0 || d > retryMaxDelay {
+			t.Errorf("backoff(%d) = %v, want within [0, %v]", n, d, retryMaxDelay)
+		}
+	}
+}
+
+func TestNewRPCErrorMessages(t *testing.T) {
+	c := &Config{GRPCTarget: "grpc.example.com:443"}
+	tests := []struct {
+		code codes.Code
+		want string
+	}{
+		{codes.Unauthenticated, "API key"},
+		{codes.Unavailable, "grpc.example.com:443"},
+		{codes.DeadlineExceeded, "timeout"},
+		{codes.NotFound, "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			err := newRPCError("/etu.NotesService/GetNote", c, status.Error(tt.code, "x"))
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("message %q does not mention %q", err, tt.want)
+			}
+			if status.Code(err) != tt.code {
+				t.Errorf("status.Code = %v, want %v", status.Code(err), tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+)
+
+// testPKI is a throwaway CA with a server and client certificate signed by it.
+type testPKI struct {
+	caFile     string
+	serverCert tls.Certificate
+	clientCert string
+	clientKey  string
+	pool       *x509.CertPool
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "etu test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issue := func(serial int64, usage x509.ExtKeyUsage) ([]byte, []byte) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: "etu.test"},
+			DNSNames:     []string{"etu.test"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	}
+
+	write := func(name string, data []byte) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	srvCert, srvKey := issue(2, x509.ExtKeyUsageServerAuth)
+	serverCert, err := tls.X509KeyPair(srvCert, srvKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cliCert, cliKey := issue(3, x509.ExtKeyUsageClientAuth)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+	return &testPKI{
+		caFile:     write("ca.pem", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})),
+		serverCert: serverCert,
+		clientCert: write("client.pem", cliCert),
+		clientKey:  write("client-key.pem", cliKey),
+		pool:       pool,
+	}
+}
+
+// statsServer is a minimal StatsService that records the authorization header.
+type statsServer struct {
+	proto.UnimplementedStatsServiceServer
+	gotAuth chan string
+}
+
+func (s *statsServer) GetStats(ctx context.Context, _ *proto.GetStatsRequest) (*proto.GetStatsResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get("authorization"); len(v) > 0 {
+		s.gotAuth <- v[0]
+	}
+	return &proto.GetStatsResponse{TotalBlips: 7}, nil
+}
+
+// startStatsServer serves a statsServer on a loopback port with the given options.
+func startStatsServer(t *testing.T, opts ...grpc.ServerOption) (string, *statsServer) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer(opts...)
+	ss := &statsServer{gotAuth: make(chan string, 1)}
+	proto.RegisterStatsServiceServer(srv, ss)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String(), ss
+}
+
+func TestTransportCustomCA(t *testing.T) {
+	pki := newTestPKI(t)
+	addr, ss := startStatsServer(t, grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{pki.serverCert},
+		MinVersion:   tls.VersionTLS12,
+	})))
+
+	c := &Config{APIKey: "abc", GRPCTarget: addr, CAFile: pki.caFile, ServerName: "etu.test"}
+	stats, err := c.GetStats(context.Background(), true)
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if stats.TotalBlips != 7 {
+		t.Errorf("TotalBlips = %d, want 7", stats.TotalBlips)
+	}
+	if got := <-ss.gotAuth; got != "etu_abc" {
+		t.Errorf("authorization = %q, want %q", got, "etu_abc")
+	}
+}
+
+func TestTransportCustomCAWrongServerName(t *testing.T) {
+	pki := newTestPKI(t)
+	addr, _ := startStatsServer(t, grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{pki.serverCert},
+		MinVersion:   tls.VersionTLS12,
+	})))
+
+	c := &Config{APIKey: "abc", GRPCTarget: addr, CAFile: pki.caFile, ServerName: "other.test", MaxRetries: -1}
+	if _, err := c.GetStats(context.Background(), true); err == nil {
+		t.Error("expected certificate name mismatch error")
+	}
+}
+
+func TestTransportMutualTLS(t *testing.T) {
+	pki := newTestPKI(t)
+	addr, _ := startStatsServer(t, grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{pki.serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pki.pool,
+		MinVersion:   tls.VersionTLS12,
+	})))
+
+	t.Run("with client cert", func(t *testing.T) {
+		c := &Config{
+			APIKey: "abc", GRPCTarget: addr, CAFile: pki.caFile, ServerName: "etu.test",
+			CertFile: pki.clientCert, KeyFile: pki.clientKey,
+		}
+		if _, err := c.GetStats(context.Background(), true); err != nil {
+			t.Fatalf("GetStats: %v", err)
+		}
+	})
+
+	t.Run("without client cert", func(t *testing.T) {
+		c := &Config{APIKey: "abc", GRPCTarget: addr, CAFile: pki.caFile, ServerName: "etu.test", MaxRetries: -1}
+		if _, err := c.GetStats(context.Background(), true); err == nil {
+			t.Error("expected handshake failure without client certificate")
+		}
+	})
+}
+
+func TestTransportInsecureLoopback(t *testing.T) {
+	addr, ss := startStatsServer(t)
+
+	c := &Config{APIKey: "abc", GRPCTarget: addr, Insecure: true}
+	if _, err := c.GetStats(context.Background(), true); err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if got := <-ss.gotAuth; got != "etu_abc" {
+		t.Errorf("authorization = %q, want %q", got, "etu_abc")
+	}
+}
+
+func TestValidateTransport(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"default tls", Config{GRPCTarget: "grpc.example.com:443"}, false},
+		{"insecure localhost", Config{GRPCTarget: "localhost:50051", Insecure: true}, false},
+		{"insecure ipv4 loopback", Config{GRPCTarget: "127.0.0.1:50051", Insecure: true}, false},
+		{"insecure ipv6 loopback", Config{GRPCTarget: "[::1]:50051", Insecure: true}, false},
+		{"insecure unix socket", Config{GRPCTarget: "unix:///run/etu.sock", Insecure: true}, false},
+		{"insecure remote host", Config{GRPCTarget: "grpc.example.com:443", Insecure: true}, true},
+		{"insecure private ip", Config{GRPCTarget: "10.0.0.5:50051", Insecure: true}, true},
+		{"insecure with ca", Config{GRPCTarget: "localhost:50051", Insecure: true, CAFile: "ca.pem"}, true},
+		{"cert without key", Config{GRPCTarget: "localhost:50051", CertFile: "c.pem"}, true},
+		{"key without cert", Config{GRPCTarget: "localhost:50051", KeyFile: "k.pem"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.validateTransport()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateTransport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTransportCredentialsBadCA(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c := &Config{GRPCTarget: "localhost:50051", CAFile: path}
+	if _, err := c.transportCredentials(); err == nil {
+		t.Error("expected error for CA bundle without certificates")
+	}
+}
@@ -0,0 +1,173 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// userIDCacheTTL is how long a verified user ID is trusted before re-verifying.
+const userIDCacheTTL = 24 * time.Hour
+
+type userIDCacheData struct {
+	Key    string
+	UserID string
+	Saved  time.Time
+}
+
+// userIDCacheKey identifies the API key and target a cached user ID belongs to,
+// without storing the key itself.
+func (c *Config) userIDCacheKey() string {
+	sum := sha256.Sum256([]byte(c.APIKey + "\x00" + c.GRPCTarget))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *Config) userIDCachePath() (string, error) {
+	return CachePath("userid.cache")
+}
+
+// loadCachedUserID returns the cached user ID if it matches this key/target and is fresh.
+func (c *Config) loadCachedUserID() (userID string, err error) {
+	path, err := c.userIDCachePath()
+	if err != nil {
+		return "", err
+	}
+	// path is built from CachePath() (fixed config dir under user home), not external input.
+	f, err := os.Open(path) //nolint:gosec // G304: path is from fixed config dir, not user-controlled
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+	var data userIDCacheData
+	if err := gob.NewDecoder(f).Decode(&data); err != nil {
+		return "", err
+	}
+	if data.Key != c.userIDCacheKey() || time.Since(data.Saved) > userIDCacheTTL {
+		return "", nil
+	}
+	return data.UserID, nil
+}
+
+// saveCachedUserID persists userID for this key/target.
+func (c *Config) saveCachedUserID(userID string) (err error) {
+	path, err := c.userIDCachePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	// path is built from CachePath() (fixed config dir under user home), not external input.
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600) //nolint:gosec // G304: path is from fixed config dir, not user-controlled
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+	return gob.NewEncoder(f).Encode(userIDCacheData{Key: c.userIDCacheKey(), UserID: userID, Saved: time.Now()})
+}
+
+// invalidateUserID forgets the user ID in memory and on disk.
+func (c *Config) invalidateUserID() {
+	if c.grpc != nil {
+		c.grpc.userIDMu.Lock()
+		c.grpc.userID = ""
+		c.grpc.userIDMu.Unlock()
+	}
+	path, err := c.userIDCachePath()
+	if err != nil {
+		return
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		log.Printf("etu: removing user id cache: %v", err)
+	}
+}
+
+// ensureUserID returns the user ID for the API key, from memory, the on-disk
+// cache, or VerifyApiKey in that order. fromCache reports whether the ID was
+// not verified by this call, so callers know a rejection may mean it is stale.
+// Failures are not remembered: the next call tries again.
+func (c *Config) ensureUserID(ctx context.Context) (userID string, fromCache bool, err error) {
+	g, err := c.getGRPCClients()
+	if err != nil {
+		return "", false, err
+	}
+	g.userIDMu.Lock()
+	defer g.userIDMu.Unlock()
+	if g.userID != "" {
+		return g.userID, true, nil
+	}
+
+	cached, err := c.loadCachedUserID()
+	if err != nil {
+		log.Printf("etu: reading user id cache: %v", err)
+	}
+	if cached != "" {
+		g.userID = cached
+		return cached, true, nil
+	}
+
+	resp, err := g.apiKeysClient.VerifyApiKey(ctx, &proto.VerifyApiKeyRequest{
+		RawKey: c.APIKey,
+	})
+	if err != nil {
+		return "", false, err
+	}
+	if !resp.GetValid() {
+		return "", false, fmt.Errorf("API key invalid")
+	}
+	g.userID = resp.GetUserId()
+	if err := c.saveCachedUserID(g.userID); err != nil {
+		log.Printf("etu: writing user id cache: %v", err)
+	}
+	return g.userID, false, nil
+}
+
+// isAuthError reports whether err means the backend rejected our identity.
+func isAuthError(err error) bool {
+	code := status.Code(err)
+	return code == codes.Unauthenticated || code == codes.PermissionDenied
+}
+
+// withUserID runs call with the current user ID. If the backend rejects a
+// cached ID, the cache is dropped and call is retried once with a freshly
+// verified one.
+func (c *Config) withUserID(ctx context.Context, call func(userID string) error) error {
+	userID, fromCache, err := c.ensureUserID(ctx)
+	if err != nil {
+		return err
+	}
+	err = call(userID)
+	if err == nil || !isAuthError(err) {
+		return err
+	}
+	c.invalidateUserID()
+	if !fromCache {
+		return err
+	}
+	userID, _, verr := c.ensureUserID(ctx)
+	if verr != nil {
+		return verr
+	}
+	return call(userID)
+}
@@ -0,0 +1,158 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// authBackend verifies every key as userID and only accepts requests for it.
+type authBackend struct {
+	proto.UnimplementedApiKeysServiceServer
+	proto.UnimplementedTagsServiceServer
+
+	mu            sync.Mutex
+	userID        string
+	verifyCalls   int
+	verifyFailure error
+}
+
+func (b *authBackend) VerifyApiKey(_ context.Context, _ *proto.VerifyApiKeyRequest) (*proto.VerifyApiKeyResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.verifyCalls++
+	if err := b.verifyFailure; err != nil {
+		b.verifyFailure = nil
+		return nil, err
+	}
+	return &proto.VerifyApiKeyResponse{Valid: true, UserId: b.userID}, nil
+}
+
+func (b *authBackend) ListTags(_ context.Context, req *proto.ListTagsRequest) (*proto.ListTagsResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if req.GetUserId() != b.userID {
+		return nil, status.Error(codes.PermissionDenied, "wrong user")
+	}
+	return &proto.ListTagsResponse{}, nil
+}
+
+func startAuthBackend(t *testing.T) (string, *authBackend) {
+	t.Helper()
+	setTestHome(t)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	b := &authBackend{userID: "user-1"}
+	proto.RegisterApiKeysServiceServer(srv, b)
+	proto.RegisterTagsServiceServer(srv, b)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String(), b
+}
+
+func TestUserIDPersistedAcrossConfigs(t *testing.T) {
+	addr, b := startAuthBackend(t)
+
+	first := &Config{APIKey: "k", GRPCTarget: addr, Insecure: true}
+	if _, err := first.ListTags(context.Background()); err != nil {
+		t.Fatalf("ListTags: %v", err)
+	}
+	second := &Config{APIKey: "k", GRPCTarget: addr, Insecure: true}
+	if _, err := second.ListTags(context.Background()); err != nil {
+		t.Fatalf("ListTags: %v", err)
+	}
+	if b.verifyCalls != 1 {
+		t.Errorf("VerifyApiKey called %d times, want 1", b.verifyCalls)
+	}
+}
+
+func TestUserIDCacheKeyedByAPIKey(t *testing.T) {
+	addr, b := startAuthBackend(t)
+
+	if _, err := (&Config{APIKey: "k1", GRPCTarget: addr, Insecure: true}).ListTags(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&Config{APIKey: "k2", GRPCTarget: addr, Insecure: true}).ListTags(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if b.verifyCalls != 2 {
+		t.Errorf("VerifyApiKey called %d times, want 2 (one per key)", b.verifyCalls)
+	}
+}
+
+func TestUserIDStaleCacheReverified(t *testing.T) {
+	addr, b := startAuthBackend(t)
+
+	c := &Config{APIKey: "k", GRPCTarget: addr, Insecure: true}
+	if err := c.saveCachedUserID("stale-user"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ListTags(context.Background()); err != nil {
+		t.Fatalf("ListTags: %v", err)
+	}
+	if b.verifyCalls != 1 {
+		t.Errorf("VerifyApiKey called %d times, want 1", b.verifyCalls)
+	}
+	cached, err := c.loadCachedUserID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached != "user-1" {
+		t.Errorf("cached user ID = %q, want %q", cached, "user-1")
+	}
+}
+
+func TestUserIDTransientFailureNotSticky(t *testing.T) {
+	addr, b := startAuthBackend(t)
+	b.verifyFailure = status.Error(codes.Internal, "boom")
+
+	c := &Config{APIKey: "k", GRPCTarget: addr, Insecure: true}
+	if _, err := c.ListTags(context.Background()); err == nil {
+		t.Fatal("expected first call to fail")
+	}
+	if _, err := c.ListTags(context.Background()); err != nil {
+		t.Fatalf("second call should re-verify and succeed: %v", err)
+	}
+}
+
+func TestLoadCachedUserIDExpired(t *testing.T) {
+	setTestHome(t)
+
+	c := &Config{APIKey: "k", GRPCTarget: "localhost:1"}
+	if err := c.saveCachedUserID("user-1"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.loadCachedUserID()
+	if err != nil || got != "user-1" {
+		t.Fatalf("loadCachedUserID() = %q, %v; want user-1", got, err)
+	}
+
+	path, err := c.userIDCachePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * userIDCacheTTL)
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(userIDCacheData{Key: c.userIDCacheKey(), UserID: "user-1", Saved: old}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := c.loadCachedUserID(); got != "" {
+		t.Errorf("expired cache returned %q, want empty", got)
+	}
+}
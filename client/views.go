@@ -0,0 +1,62 @@
+package client
+
+import (
+	"encoding/gob"
+	"os"
+	"path/filepath"
+)
+
+// ViewCounts maps entry IDs to how many times etu random has shown them.
+type ViewCounts map[string]int
+
+func viewCountsPath() (string, error) {
+	return CachePath("views.cache")
+}
+
+// LoadViewCounts reads the view counts; it returns an empty map if there are none.
+func LoadViewCounts() (views ViewCounts, err error) {
+	path, err := viewCountsPath()
+	if err != nil {
+		return nil, err
+	}
+	// path is built from CachePath() (fixed config dir under user home), not external input.
+	f, err := os.Open(path) //nolint:gosec // G304: path is from fixed config dir, not user-controlled
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ViewCounts{}, nil
+		}
+		return nil, err
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+	views = ViewCounts{}
+	if err := gob.NewDecoder(f).Decode(&views); err != nil {
+		return nil, err
+	}
+	return views, nil
+}
+
+// SaveViewCounts writes the view counts to the cache.
+func SaveViewCounts(views ViewCounts) (err error) {
+	path, err := viewCountsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	// path is built from CachePath() (fixed config dir under user home), not external input.
+	f, err := os.Create(path) //nolint:gosec // G304: path is from fixed config dir, not user-controlled
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+	return gob.NewEncoder(f).Encode(views)
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/icco/etu/client"
+)
+
+// runCommand runs name and returns its stdout; a seam for tests.
+var runCommand = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...) //nolint:gosec // G204: fixed tools or the user's configured command
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %w: %s", filepath.Base(name), err, msg)
+		}
+		return nil, fmt.Errorf("%s: %w", filepath.Base(name), err)
+	}
+	return out, nil
+}
+
+// pipeCommand runs name with input on stdin; a seam for tests.
+var pipeCommand = func(ctx context.Context, input, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...) //nolint:gosec // G204: fixed clipboard tools
+	cmd.Stdin = strings.NewReader(input)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s: %w: %s", filepath.Base(name), err, msg)
+		}
+		return fmt.Errorf("%s: %w", filepath.Base(name), err)
+	}
+	return nil
+}
+
+// copyToClipboard puts text on the clipboard using wl-copy (Wayland),
+// xclip (X11) or pbcopy (macOS).
+func copyToClipboard(ctx context.Context, text string) error {
+	if _, err := lookPath("wl-copy"); err == nil && os.Getenv("WAYLAND_DISPLAY") != "" {
+		return pipeCommand(ctx, text, "wl-copy")
+	}
+	if _, err := lookPath("xclip"); err == nil {
+		return pipeCommand(ctx, text, "xclip", "-selection", "clipboard", "-i")
+	}
+	if _, err := lookPath("pbcopy"); err == nil {
+		return pipeCommand(ctx, text, "pbcopy")
+	}
+	return fmt.Errorf("no clipboard tool found; install wl-clipboard, xclip or pbcopy")
+}
+
+// pickImageType chooses the best image MIME type from a newline-separated
+// list of clipboard targets, preferring PNG. It returns "" when none is an image.
+func pickImageType(targets string) string {
+	var first string
+	for _, t := range strings.Fields(targets) {
+		if t == "image/png" {
+			return t
+		}
+		if first == "" && strings.HasPrefix(t, "image/") {
+			first = t
+		}
+	}
+	return first
+}
+
+// clipboardImage returns the image currently on the clipboard using
+// wl-paste (Wayland), xclip (X11) or pngpaste (macOS).
+func clipboardImage(ctx context.Context) ([]byte, error) {
+	errNoImage := fmt.Errorf("the clipboard doesn't contain an image")
+
+	if _, err := lookPath("wl-paste"); err == nil && os.Getenv("WAYLAND_DISPLAY") != "" {
+		types, err := runCommand(ctx, "wl-paste", "--list-types")
+		if err != nil {
+			return nil, err
+		}
+		t := pickImageType(string(types))
+		if t == "" {
+			return nil, errNoImage
+		}
+		return runCommand(ctx, "wl-paste", "--no-newline", "--type", t)
+	}
+	if _, err := lookPath("xclip"); err == nil {
+		targets, err := runCommand(ctx, "xclip", "-selection", "clipboard", "-t", "TARGETS", "-o")
+		if err != nil {
+			return nil, err
+		}
+		t := pickImageType(string(targets))
+		if t == "" {
+			return nil, errNoImage
+		}
+		return runCommand(ctx, "xclip", "-selection", "clipboard", "-t", t, "-o")
+	}
+	if _, err := lookPath("pngpaste"); err == nil {
+		data, err := runCommand(ctx, "pngpaste", "-")
+		if err != nil {
+			return nil, errNoImage
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("no clipboard tool found; install wl-clipboard, xclip or pngpaste")
+}
+
+// defaultScreenshotCommand picks a region screenshot tool found on PATH.
+func defaultScreenshotCommand() string {
+	has := func(name string) bool {
+		_, err := lookPath(name)
+		return err == nil
+	}
+	switch {
+	case runtime.GOOS == "darwin" && has("screencapture"):
+		return "screencapture -i -t png {file}"
+	case has("grim") && has("slurp"):
+		return `grim -g "$(slurp)" -`
+	case has("maim"):
+		return "maim -s"
+	case has("gnome-screenshot"):
+		return "gnome-screenshot -a -f {file}"
+	}
+	return ""
+}
+
+// screenshotImage runs command through the shell and returns the captured
+// image, read from stdout or from the file substituted for {file}.
+func screenshotImage(ctx context.Context, command, dir string) ([]byte, error) {
+	if command == "" {
+		command = defaultScreenshotCommand()
+	}
+	if command == "" {
+		return nil, fmt.Errorf("no screenshot tool found; set screenshot_command in the config file (e.g. `maim -s`)")
+	}
+
+	if !strings.Contains(command, "{file}") {
+		data, err := runCommand(ctx, "sh", "-c", command)
+		if err != nil {
+			return nil, fmt.Errorf("screenshot cancelled or failed: %w", err)
+		}
+		return data, nil
+	}
+
+	path := filepath.Join(dir, "screenshot-capture.png")
+	quoted := "'" + strings.ReplaceAll(path, "'", `'\''`) + "'"
+	if _, err := runCommand(ctx, "sh", "-c", strings.ReplaceAll(command, "{file}", quoted)); err != nil {
+		return nil, fmt.Errorf("screenshot cancelled or failed: %w", err)
+	}
+	defer func() { _ = os.Remove(path) }()
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is our own temp file
+	if err != nil {
+		return nil, fmt.Errorf("screenshot cancelled or failed: %w", err)
+	}
+	return data, nil
+}
+
+// saveImage validates that data is an image and writes it to dir under a
+// timestamped name with an extension matching its type.
+func saveImage(data []byte, dir, prefix string) (string, error) {
+	if len(data) == 0 {
+		return "", fmt.Errorf("no image data captured")
+	}
+	mimeType := client.DetectMIME(data, "")
+	if !strings.HasPrefix(mimeType, "image/") {
+		return "", fmt.Errorf("captured data is %s, not an image", mimeType)
+	}
+	ext := ".img"
+	switch mimeType {
+	case "image/png":
+		ext = ".png"
+	case "image/jpeg":
+		ext = ".jpg"
+	default:
+		if exts, err := mime.ExtensionsByType(mimeType); err == nil && len(exts) > 0 {
+			ext = exts[0]
+		}
+	}
+	path := filepath.Join(dir, prefix+"-"+time.Now().Format("20060102-150405.000")+ext)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("save image: %w", err)
+	}
+	return path, nil
+}
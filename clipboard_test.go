@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var pngHeader = []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}
+
+// fakeTools installs the named tools on a fake PATH and answers their
+// invocations with respond.
+func fakeTools(t *testing.T, tools []string, respond func(name string, args []string) ([]byte, error)) {
+	t.Helper()
+	origLook, origRun := lookPath, runCommand
+	t.Cleanup(func() { lookPath, runCommand = origLook, origRun })
+	lookPath = func(name string) (string, error) {
+		for _, tool := range tools {
+			if tool == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", exec.ErrNotFound
+	}
+	runCommand = func(_ context.Context, name string, args ...string) ([]byte, error) {
+		return respond(name, args)
+	}
+}
+
+func TestPickImageType(t *testing.T) {
+	tests := []struct {
+		targets, want string
+	}{
+		{"TARGETS\nimage/jpeg\nimage/png\n", "image/png"},
+		{"text/plain\nimage/jpeg\n", "image/jpeg"},
+		{"UTF8_STRING\ntext/plain\n", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := pickImageType(tt.targets); got != tt.want {
+			t.Errorf("pickImageType(%q) = %q, want %q", tt.targets, got, tt.want)
+		}
+	}
+}
+
+func TestClipboardImageXclip(t *testing.T) {
+	t.Setenv("WAYLAND_DISPLAY", "")
+	var calls []string
+	fakeTools(t, []string{"xclip", "wl-paste"}, func(name string, args []string) ([]byte, error) {
+		calls = append(calls, name+" "+strings.Join(args, " "))
+		if args[len(args)-2] == "TARGETS" {
+			return []byte("TARGETS\nimage/png\n"), nil
+		}
+		return pngHeader, nil
+	})
+
+	data, err := clipboardImage(context.Background())
+	if err != nil {
+		t.Fatalf("clipboardImage: %v", err)
+	}
+	if string(data) != string(pngHeader) {
+		t.Errorf("data = %v", data)
+	}
+	if want := "xclip -selection clipboard -t image/png -o"; calls[len(calls)-1] != want {
+		t.Errorf("last call = %q, want %q", calls[len(calls)-1], want)
+	}
+}
+
+func TestClipboardImageWayland(t *testing.T) {
+	t.Setenv("WAYLAND_DISPLAY", "wayland-0")
+	fakeTools(t, []string{"wl-paste"}, func(_ string, args []string) ([]byte, error) {
+		if args[0] == "--list-types" {
+			return []byte("text/plain\n"), nil
+		}
+		return nil, errors.New("unexpected read")
+	})
+
+	if _, err := clipboardImage(context.Background()); err == nil || !strings.Contains(err.Error(), "doesn't contain an image") {
+		t.Errorf("err = %v, want no-image error", err)
+	}
+}
+
+func TestClipboardImageNoTool(t *testing.T) {
+	fakeTools(t, nil, nil)
+	if _, err := clipboardImage(context.Background()); err == nil || !strings.Contains(err.Error(), "install") {
+		t.Errorf("err = %v, want install hint", err)
+	}
+}
+
+func TestScreenshotImage(t *testing.T) {
+	t.Run("stdout", func(t *testing.T) {
+		fakeTools(t, nil, func(name string, args []string) ([]byte, error) {
+			if name != "sh" || args[1] != "maim -s" {
+				t.Errorf("ran %s %v", name, args)
+			}
+			return pngHeader, nil
+		})
+		data, err := screenshotImage(context.Background(), "maim -s", t.TempDir())
+		if err != nil || len(data) != len(pngHeader) {
+			t.Errorf("screenshotImage = %v, %v", data, err)
+		}
+	})
+
+	t.Run("file placeholder", func(t *testing.T) {
+		dir := t.TempDir()
+		fakeTools(t, nil, func(_ string, args []string) ([]byte, error) {
+			want := "screencapture -i '" + filepath.Join(dir, "screenshot-capture.png") + "'"
+			if args[1] != want {
+				t.Errorf("command = %q, want %q", args[1], want)
+			}
+			return nil, os.WriteFile(filepath.Join(dir, "screenshot-capture.png"), pngHeader, 0600)
+		})
+		data, err := screenshotImage(context.Background(), "screencapture -i {file}", dir)
+		if err != nil || len(data) != len(pngHeader) {
+			t.Errorf("screenshotImage = %v, %v", data, err)
+		}
+	})
+
+	t.Run("default tool", func(t *testing.T) {
+		fakeTools(t, []string{"grim", "slurp"}, func(_ string, args []string) ([]byte, error) {
+			if args[1] != `grim -g "$(slurp)" -` {
+				t.Errorf("command = %q", args[1])
+			}
+			return pngHeader, nil
+		})
+		if _, err := screenshotImage(context.Background(), "", t.TempDir()); err != nil {
+			t.Errorf("screenshotImage: %v", err)
+		}
+	})
+
+	t.Run("unconfigured", func(t *testing.T) {
+		fakeTools(t, nil, nil)
+		if _, err := screenshotImage(context.Background(), "", t.TempDir()); err == nil || !strings.Contains(err.Error(), "screenshot_command") {
+			t.Errorf("err = %v, want config hint", err)
+		}
+	})
+}
+
+func TestSaveImage(t *testing.T) {
+	dir := t.TempDir()
+	path, err := saveImage(pngHeader, dir, "clipboard")
+	if err != nil {
+		t.Fatalf("saveImage: %v", err)
+	}
+	if !strings.HasPrefix(filepath.Base(path), "clipboard-") || filepath.Ext(path) != ".png" {
+		t.Errorf("path = %s", path)
+	}
+
+	if _, err := saveImage([]byte("just some text"), dir, "clipboard"); err == nil || !strings.Contains(err.Error(), "not an image") {
+		t.Errorf("err = %v, want not-an-image error", err)
+	}
+	if _, err := saveImage(nil, dir, "clipboard"); err == nil {
+		t.Error("expected error for empty data")
+	}
+}
+
+func TestCommandCreatePasteImage(t *testing.T) {
+	srv := startFakeBackend(t)
+	t.Setenv("WAYLAND_DISPLAY", "")
+	fakeTools(t, []string{"xclip"}, func(_ string, args []string) ([]byte, error) {
+		if args[len(args)-2] == "TARGETS" {
+			return []byte("image/png\n"), nil
+		}
+		return pngHeader, nil
+	})
+
+	if _, err := runCLI(t, "look at this", "create", "--paste-image"); err != nil {
+		t.Fatalf("create --paste-image: %v", err)
+	}
+	notes := srv.Notes()
+	if len(notes) != 1 || len(notes[0].GetImages()) != 1 {
+		t.Fatalf("notes = %v, want one note with one image", notes)
+	}
+}
+
+func TestCopyToClipboard(t *testing.T) {
+	t.Setenv("WAYLAND_DISPLAY", "")
+	fakeTools(t, []string{"xclip", "pbcopy"}, nil)
+	orig := pipeCommand
+	t.Cleanup(func() { pipeCommand = orig })
+	var got []string
+	pipeCommand = func(_ context.Context, input, name string, args ...string) error {
+		got = append(got, input, name+" "+strings.Join(args, " "))
+		return nil
+	}
+
+	if err := copyToClipboard(context.Background(), "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"hello", "xclip -selection clipboard -i"}; !slices.Equal(got, want) {
+		t.Errorf("ran %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/icco/etu/client"
+	"github.com/icco/etu/client/fake"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// startFakeBackend points the CLI at an in-process fake etu-backend.
+func startFakeBackend(t *testing.T) *fake.Server {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	srv := fake.NewServer()
+	t.Cleanup(srv.Close)
+	cfg = srv.Config()
+	history = client.NewHistory(client.NewLinkIndexer(cfg))
+	journal = history
+	return srv
+}
+
+// resetFlags restores every flag to its default so state doesn't leak between runs.
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			_ = sv.Replace(nil)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, c := range cmd.Commands() {
+		resetFlags(c)
+	}
+}
+
+// runCLI executes the root command with args and stdin, returning stdout.
+func runCLI(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+	resetFlags(rootCmd)
+	var out bytes.Buffer
+	rootCmd.SetArgs(args)
+	rootCmd.SetIn(strings.NewReader(stdin))
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&out)
+	err := rootCmd.ExecuteContext(context.Background())
+	return out.String(), err
+}
+
+func TestCommandCreateFromStdin(t *testing.T) {
+	srv := startFakeBackend(t)
+
+	if _, err := runCLI(t, "switching to the report\n", "create"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	notes := srv.Notes()
+	if len(notes) != 1 {
+		t.Fatalf("got %d notes, want 1", len(notes))
+	}
+	if got := notes[0].GetContent(); got != "switching to the report\n" {
+		t.Errorf("content = %q", got)
+	}
+}
+
+func TestCommandCreateWithAttachments(t *testing.T) {
+	srv := startFakeBackend(t)
+	dir := t.TempDir()
+	img := filepath.Join(dir, "shot.png")
+	if err := os.WriteFile(img, []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}, 0600); err != nil {
+		t.Fatal(err)
+	}
+	aud := filepath.Join(dir, "memo.wav")
+	if err := os.WriteFile(aud, []byte("RIFF....WAVE"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := runCLI(t, "with media", "create", "-i", img, "-a", aud); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	notes := srv.Notes()
+	if len(notes) != 1 || len(notes[0].GetImages()) != 1 || len(notes[0].GetAudios()) != 1 {
+		t.Fatalf("notes = %v, want one note with one image and one audio", notes)
+	}
+}
+
+func TestCommandCreateMissingAttachment(t *testing.T) {
+	srv := startFakeBackend(t)
+
+	if _, err := runCLI(t, "text", "create", "-i", "/nonexistent/x.png"); err == nil {
+		t.Fatal("expected error for missing attachment")
+	}
+	if n := len(srv.Notes()); n != 0 {
+		t.Errorf("got %d notes, want 0", n)
+	}
+}
+
+func TestCommandLast(t *testing.T) {
+	srv := startFakeBackend(t)
+	srv.AddNote("older", time.Now().Add(-2*time.Hour))
+	srv.AddNote("newest", time.Now().Add(-time.Hour))
+
+	out, err := runCLI(t, "", "last")
+	if err != nil {
+		t.Fatalf("last: %v", err)
+	}
+	if out != "newest" {
+		t.Errorf("output = %q, want %q", out, "newest")
+	}
+}
+
+func TestCommandLastEmpty(t *testing.T) {
+	startFakeBackend(t)
+
+	if _, err := runCLI(t, "", "last"); err == nil {
+		t.Error("expected error with no entries")
+	}
+}
+
+func TestCommandRandom(t *testing.T) {
+	srv := startFakeBackend(t)
+	srv.AddNote("only entry", time.Now())
+
+	out, err := runCLI(t, "", "random")
+	if err != nil {
+		t.Fatalf("random: %v", err)
+	}
+	if out != "only entry" {
+		t.Errorf("output = %q, want %q", out, "only entry")
+	}
+}
+
+func TestCommandTags(t *testing.T) {
+	srv := startFakeBackend(t)
+	srv.AddNote("a", time.Now(), "work")
+	srv.AddNote("b", time.Now(), "work", "life")
+
+	out, err := runCLI(t, "", "tags")
+	if err != nil {
+		t.Fatalf("tags: %v", err)
+	}
+	if want := "work (2)\nlife (1)\n"; out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+}
+
+func TestCommandStats(t *testing.T) {
+	srv := startFakeBackend(t)
+	srv.AddNote("one two three", time.Now(), "work")
+
+	out, err := runCLI(t, "", "stats")
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	if want := "Blips: 1\nTags: 1\nWords written: 3\n"; out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+
+	out, err = runCLI(t, "", "stats", "--global")
+	if err != nil {
+		t.Fatalf("stats --global: %v", err)
+	}
+	if !strings.Contains(out, "Community\nBlips: 1\n") {
+		t.Errorf("output = %q, want a community block", out)
+	}
+}
+
+func TestCommandTimeSince(t *testing.T) {
+	srv := startFakeBackend(t)
+	srv.AddNote("x", time.Now().Add(-3*time.Hour))
+
+	out, err := runCLI(t, "", "timesince")
+	if err != nil {
+		t.Fatalf("timesince: %v", err)
+	}
+	if out != "3.0h" {
+		t.Errorf("output = %q, want %q", out, "3.0h")
+	}
+}
+
+func TestCommandTimeSinceNoEntries(t *testing.T) {
+	startFakeBackend(t)
+
+	out, err := runCLI(t, "", "timesince")
+	if err != nil {
+		t.Fatalf("timesince: %v", err)
+	}
+	if out != "???" {
+		t.Errorf("output = %q, want sentinel %q", out, "???")
+	}
+}
+
+func TestCommandRejectsBadAPIKey(t *testing.T) {
+	startFakeBackend(t)
+	cfg.APIKey = "wrong"
+
+	if _, err := runCLI(t, "", "tags"); err == nil {
+		t.Error("expected error for invalid API key")
+	}
+}
+
+func TestCommandRequiresAPIKey(t *testing.T) {
+	startFakeBackend(t)
+	cfg.APIKey = ""
+
+	if _, err := runCLI(t, "", "tags"); err == nil {
+		t.Error("expected validation error without API key")
+	}
+}
+
+func TestCommandCreateQuick(t *testing.T) {
+	tests := []struct {
+		name  string
+		stdin string
+		args  []string
+		want  string
+	}{
+		{"args joined", "ignored", []string{"create", "fixing", "the", "build"}, "fixing the build"},
+		{"messages as paragraphs", "", []string{"create", "-m", "Shipped 1.2", "-m", "Next: docs"}, "Shipped 1.2\n\nNext: docs"},
+		{"args then messages", "", []string{"create", "standup", "-m", "all good"}, "standup\n\nall good"},
+		{"dash reads stdin", "from a hook\n", []string{"create", "-"}, "from a hook"},
+		{"etu dash", "from a launcher\n", []string{"-"}, "from a launcher"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := startFakeBackend(t)
+			if _, err := runCLI(t, tt.stdin, tt.args...); err != nil {
+				t.Fatalf("%v: %v", tt.args, err)
+			}
+			notes := srv.Notes()
+			if len(notes) != 1 || notes[0].GetContent() != tt.want {
+				t.Fatalf("notes = %v, want content %q", notes, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandCreatePrintIDAndTags(t *testing.T) {
+	srv := startFakeBackend(t)
+
+	out, err := runCLI(t, "", "create", "release cut", "--tag", "release", "--tag", "work", "--print-id")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	notes := srv.Notes()
+	if len(notes) != 1 {
+		t.Fatalf("got %d notes, want 1", len(notes))
+	}
+	if want := notes[0].GetId() + "\n"; out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+	if got := notes[0].GetTags(); len(got) != 2 || got[0] != "release" || got[1] != "work" {
+		t.Errorf("tags = %v, want [release work]", got)
+	}
+}
+
+func TestCommandCreateQuickErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"dash with other args", []string{"create", "-", "more"}},
+		{"blank message", []string{"create", "-m", "  "}},
+		{"unknown root argument", []string{"hello"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := startFakeBackend(t)
+			if _, err := runCLI(t, "", tt.args...); err == nil {
+				t.Errorf("%v: expected error", tt.args)
+			}
+			if n := len(srv.Notes()); n != 0 {
+				t.Errorf("got %d notes, want 0", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/icco/etu/client"
+)
+
+// processingPollInterval is how often --wait checks for attachment text.
+var processingPollInterval = 2 * time.Second
+
+var errProcessingTimeout = errors.New("attachments still without text")
+
+// attachmentProgress counts attachments and how many have no OCR or
+// transcription text. The backend doesn't say whether processing is done, so
+// an attachment without text may still be queued or may have none to give.
+func attachmentProgress(p *client.Post) (images, imagesNoText, audios, audiosNoText int) {
+	for _, img := range p.Images {
+		images++
+		if img.GetExtractedText() == "" {
+			imagesNoText++
+		}
+	}
+	for _, aud := range p.Audios {
+		audios++
+		if aud.GetTranscribedText() == "" {
+			audiosNoText++
+		}
+	}
+	return images, imagesNoText, audios, audiosNoText
+}
+
+// processingStatus describes n attachments of which noText have no text.
+func processingStatus(n, noText int, what string) string {
+	if noText == 0 {
+		return fmt.Sprintf("%d (%s done)", n, what)
+	}
+	if noText == n {
+		return fmt.Sprintf("%d (no %s text)", n, what)
+	}
+	return fmt.Sprintf("%d (%s text for %d, none for %d)", n, what, n-noText, noText)
+}
+
+// formatCreated summarizes a newly saved entry: ID, time, tags and which
+// attachments have OCR or transcription text.
+func formatCreated(p *client.Post) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Saved %s (%s)\n", p.PageID, formatTime(p.CreatedAt))
+	if len(p.Tags) > 0 {
+		fmt.Fprintf(&b, "Tags: %s\n", strings.Join(p.Tags, ", "))
+	} else {
+		b.WriteString("Tags: none yet\n")
+	}
+	images, imagesNoText, audios, audiosNoText := attachmentProgress(p)
+	if images > 0 {
+		fmt.Fprintf(&b, "Images: %s\n", processingStatus(images, imagesNoText, "OCR"))
+	}
+	if audios > 0 {
+		fmt.Fprintf(&b, "Audio: %s\n", processingStatus(audios, audiosNoText, "transcription"))
+	}
+	return b.String()
+}
+
+// waitForProcessing polls the entry until every attachment has text,
+// returning the latest copy. It returns errProcessingTimeout, along with the
+// latest copy, if some still have none after timeout.
+func waitForProcessing(ctx context.Context, j client.Journal, p *client.Post, timeout time.Duration) (*client.Post, error) {
+	// The deadline only governs polling; a request in flight is left to
+	// finish so a slow answer isn't mistaken for a failure.
+	deadline, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(processingPollInterval)
+	defer ticker.Stop()
+	for {
+		_, imagesNoText, _, audiosNoText := attachmentProgress(p)
+		if imagesNoText+audiosNoText == 0 {
+			return p, nil
+		}
+		select {
+		case <-deadline.Done():
+			if ctx.Err() != nil {
+				return p, ctx.Err()
+			}
+			return p, errProcessingTimeout
+		case <-ticker.C:
+		}
+		latest, err := j.GetPost(ctx, p.PageID)
+		if err != nil {
+			return p, err
+		}
+		p = latest
+	}
+}
+
+// waitWithSpinner runs waitForProcessing, with a spinner unless quiet.
+func waitWithSpinner(ctx context.Context, j client.Journal, p *client.Post, timeout time.Duration, quiet bool) (*client.Post, error) {
+	if quiet {
+		return waitForProcessing(ctx, j, p, timeout)
+	}
+	var latest *client.Post
+	var waitErr error
+	err := spinner.New().
+		Title("Waiting for OCR and transcription...").
+		Action(func() {
+			latest, waitErr = waitForProcessing(ctx, j, p, timeout)
+		}).
+		Run()
+	if err != nil {
+		return p, err
+	}
+	return latest, waitErr
+}
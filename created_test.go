@@ -0,0 +1,109 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/icco/etu-backend/proto"
+	"github.com/icco/etu/client"
+)
+
+func TestFormatCreated(t *testing.T) {
+	useDisplayTime(t, timeFormat{loc: time.UTC, layout: "2006-01-02 15:04", now: time.Now})
+	at := time.Date(2026, 3, 14, 9, 26, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name string
+		post *client.Post
+		want []string
+	}{
+		{
+			name: "plain",
+			post: &client.Post{PageID: "note-1", CreatedAt: at},
+			want: []string{"Saved note-1 (2026-03-14 09:26)", "Tags: none yet"},
+		},
+		{
+			name: "tags and media without text",
+			post: &client.Post{
+				PageID:    "note-2",
+				CreatedAt: at,
+				Tags:      []string{"work", "release"},
+				Images:    []*proto.NoteImage{{Url: "a"}, {Url: "b", ExtractedText: "hi"}},
+				Audios:    []*proto.NoteAudio{{Url: "c"}},
+			},
+			want: []string{"Tags: work, release", "Images: 2 (OCR text for 1, none for 1)", "Audio: 1 (no transcription text)"},
+		},
+		{
+			name: "processed",
+			post: &client.Post{
+				PageID:    "note-3",
+				CreatedAt: at,
+				Images:    []*proto.NoteImage{{Url: "a", ExtractedText: "hi"}},
+				Audios:    []*proto.NoteAudio{{Url: "c", TranscribedText: "hello"}},
+			},
+			want: []string{"Images: 1 (OCR done)", "Audio: 1 (transcription done)"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := formatCreated(tc.post)
+			for _, w := range tc.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("formatCreated() = %q, missing %q", got, w)
+				}
+			}
+		})
+	}
+}
+
+func TestCommandCreateWait(t *testing.T) {
+	srv := startFakeBackend(t)
+	old := processingPollInterval
+	processingPollInterval = time.Millisecond
+	t.Cleanup(func() { processingPollInterval = old })
+
+	calls := 0
+	srv.OnGetNote = func(n *proto.Note) {
+		calls++
+		if calls >= 2 {
+			for _, img := range n.Images {
+				img.ExtractedText = "whiteboard"
+			}
+		}
+	}
+	img := filepath.Join(t.TempDir(), "board.png")
+	if err := os.WriteFile(img, pngHeader, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCLI(t, "", "create", "-i", img, "--wait", "meeting notes")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if !strings.Contains(out, "Images: 1 (OCR done)") {
+		t.Errorf("output = %q, want processed image", out)
+	}
+	if calls < 2 {
+		t.Errorf("GetNote called %d times, want at least 2", calls)
+	}
+}
+
+func TestCommandCreateWaitTimeout(t *testing.T) {
+	startFakeBackend(t)
+	old := processingPollInterval
+	processingPollInterval = time.Millisecond
+	t.Cleanup(func() { processingPollInterval = old })
+
+	img := filepath.Join(t.TempDir(), "board.png")
+	if err := os.WriteFile(img, pngHeader, 0600); err != nil {
+		t.Fatal(err)
+	}
+	out, err := runCLI(t, "", "create", "-i", img, "--wait", "--wait-timeout", "20ms", "meeting notes")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if !strings.Contains(out, "Images: 1 (no OCR text)") {
+		t.Errorf("output = %q, want image without text", out)
+	}
+}
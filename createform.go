@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+)
+
+// createAction is what the user asked for when the create form exited.
+type createAction int
+
+const (
+	createSubmit createAction = iota
+	createRecord
+	createPasteImage
+	createScreenshot
+	createTemplate
+)
+
+// createShortcuts maps keys in the create form to the actions they trigger.
+var createShortcuts = map[string]createAction{
+	"ctrl+r": createRecord,
+	"ctrl+g": createPasteImage,
+	"ctrl+s": createScreenshot,
+	"ctrl+o": createTemplate,
+}
+
+// createFields holds the create form's values across runs, so the form can
+// be left to record audio or grab an image and reopened where the user was.
+type createFields struct {
+	text   string
+	images string
+	audio  string
+	// keep is the attachments left checked in the review step.
+	keep []string
+}
+
+// Help shown above the attachment fields' per-file status lines.
+const (
+	imagesHelp = "Drag & drop image files, directories or globs here, or paste paths (one per line). Press ctrl+g to paste an image from the clipboard or ctrl+s to take a screenshot."
+	audioHelp  = "Drag & drop audio files, directories or globs here, or paste paths (one per line). Press ctrl+r to record a voice note."
+)
+
+// formAttachments returns the expanded attachment paths entered in the form.
+func (f *createFields) formAttachments() (images, audio []string) {
+	return expandPaths(parsePaths(f.images), "image"), expandPaths(parsePaths(f.audio), "audio")
+}
+
+// addPath appends path on its own line to a newline-separated path list.
+func addPath(list *string, path string) {
+	if strings.TrimSpace(*list) != "" && !strings.HasSuffix(*list, "\n") {
+		*list += "\n"
+	}
+	*list += path
+}
+
+// newCreateForm builds the create form. Attachments are checked with the
+// image options set on ctx.
+func newCreateForm(ctx context.Context, fields *createFields) *huh.Form {
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewText().
+				Value(&fields.text).
+				Placeholder("Write your journal entry here... (ctrl+o inserts a template)").
+				Validate(func(value string) error {
+					if len(strings.TrimSpace(value)) == 0 {
+						return fmt.Errorf("journal entry cannot be empty")
+					}
+					return nil
+				}).
+				WithHeight(12).
+				WithWidth(100),
+			huh.NewText().
+				Value(&fields.images).
+				Title("Images").
+				DescriptionFunc(func() string { return attachmentsDescription(ctx, imagesHelp, fields.images, "image") }, &fields.images).
+				Validate(validateAttachments(ctx, "image")).
+				Placeholder("/path/to/image.jpg").
+				WithHeight(3).
+				WithWidth(100),
+			huh.NewText().
+				Value(&fields.audio).
+				Title("Audio").
+				DescriptionFunc(func() string { return attachmentsDescription(ctx, audioHelp, fields.audio, "audio") }, &fields.audio).
+				Validate(validateAttachments(ctx, "audio")).
+				Placeholder("/path/to/recording.mp3").
+				WithHeight(3).
+				WithWidth(100),
+		),
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Attachments").
+				Description("Uncheck anything you don't want to attach.").
+				OptionsFunc(func() []huh.Option[string] {
+					images, audio := fields.formAttachments()
+					var opts []huh.Option[string]
+					for _, p := range append(images, audio...) {
+						opts = append(opts, huh.NewOption(filepath.Base(p), p).Selected(true))
+					}
+					return opts
+				}, []*string{&fields.images, &fields.audio}).
+				Value(&fields.keep),
+		).WithHideFunc(func() bool {
+			images, audio := fields.formAttachments()
+			return len(images)+len(audio) == 0
+		}),
+	)
+}
+
+// createFormModel wraps the create form to add shortcuts that leave the form,
+// such as recording audio or pasting an image.
+type createFormModel struct {
+	form   *huh.Form
+	action createAction
+}
+
+func newCreateFormModel(ctx context.Context, fields *createFields) createFormModel {
+	form := newCreateForm(ctx, fields)
+	form.SubmitCmd = tea.Quit
+	form.CancelCmd = tea.Interrupt
+	return createFormModel{form: form}
+}
+
+func (m createFormModel) Init() tea.Cmd {
+	return m.form.Init()
+}
+
+func (m createFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		if action, ok := createShortcuts[msg.String()]; ok {
+			m.action = action
+			return m, tea.Quit
+		}
+	}
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	return m, cmd
+}
+
+func (m createFormModel) View() string {
+	if m.action != createSubmit {
+		return ""
+	}
+	return m.form.View()
+}
+
+// runCreateForm shows the create form until the user submits, aborts or
+// picks a shortcut, and reports which.
+func runCreateForm(ctx context.Context, fields *createFields) (createAction, error) {
+	final, err := tea.NewProgram(newCreateFormModel(ctx, fields)).Run()
+	if errors.Is(err, tea.ErrInterrupted) {
+		return createSubmit, huh.ErrUserAborted
+	}
+	if err != nil {
+		return createSubmit, err
+	}
+	m := final.(createFormModel)
+	if m.action == createSubmit && m.form.State != huh.StateCompleted {
+		return createSubmit, huh.ErrUserAborted
+	}
+	return m.action, nil
+}
+
+// attachmentCapture adds recordings, clipboard images and screenshots to the
+// create form's fields. Captured files live in a temp dir until cleanup.
+type attachmentCapture struct {
+	cmd    *cobra.Command
+	fields *createFields
+	dir    string
+}
+
+func (a *attachmentCapture) tempDir() (string, error) {
+	if a.dir == "" {
+		dir, err := os.MkdirTemp("", "etu-attach-")
+		if err != nil {
+			return "", err
+		}
+		a.dir = dir
+	}
+	return a.dir, nil
+}
+
+// run performs a capture action and adds the resulting file to the form.
+func (a *attachmentCapture) run(action createAction) error {
+	dir, err := a.tempDir()
+	if err != nil {
+		return err
+	}
+	ctx := a.cmd.Context()
+	switch action {
+	case createRecord:
+		recorder, _ := a.cmd.Flags().GetString("recorder")
+		limit, _ := a.cmd.Flags().GetDuration("record-limit")
+		path, err := recordVoiceNote(ctx, recorder, limit, cfg.MaxAttachmentBytes(), dir)
+		if err != nil {
+			return err
+		}
+		addPath(&a.fields.audio, path)
+	case createPasteImage, createScreenshot:
+		var data []byte
+		prefix := "clipboard"
+		if action == createPasteImage {
+			data, err = clipboardImage(ctx)
+		} else {
+			prefix = "screenshot"
+			data, err = screenshotImage(ctx, cfg.ScreenshotCommand, dir)
+		}
+		if err != nil {
+			return err
+		}
+		path, err := saveImage(data, dir, prefix)
+		if err != nil {
+			return err
+		}
+		addPath(&a.fields.images, path)
+	}
+	return nil
+}
+
+// cleanup removes captured files once the entry has been saved.
+func (a *attachmentCapture) cleanup() {
+	if a.dir != "" {
+		_ = os.RemoveAll(a.dir)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCreateFormModelRecordKey(t *testing.T) {
+	fields := &createFields{text: "draft"}
+	m := newCreateFormModel(context.Background(), fields)
+	m.Init()
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if got := updated.(createFormModel).action; got != createRecord {
+		t.Errorf("action = %v, want createRecord", got)
+	}
+	if cmd == nil {
+		t.Error("expected the form to quit so recording can start")
+	}
+	if fields.text != "draft" {
+		t.Errorf("text = %q, want it kept across the recording", fields.text)
+	}
+}
+
+func TestAddPath(t *testing.T) {
+	tests := []struct {
+		list, path, want string
+	}{
+		{"", "/tmp/a.wav", "/tmp/a.wav"},
+		{"/x.mp3", "/tmp/a.wav", "/x.mp3\n/tmp/a.wav"},
+		{"/x.mp3\n", "/tmp/a.wav", "/x.mp3\n/tmp/a.wav"},
+		{"  \n", "/tmp/a.wav", "  \n/tmp/a.wav"},
+	}
+	for _, tt := range tests {
+		list := tt.list
+		addPath(&list, tt.path)
+		if list != tt.want {
+			t.Errorf("addPath(%q, %q) = %q, want %q", tt.list, tt.path, list, tt.want)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/icco/etu/client"
+	"github.com/spf13/cobra"
+)
+
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Render a day, week or month of entries as a Markdown or HTML report.",
+	Long: `Render the entries of a day, week or month as a report, grouped by day and
+by tag, with image text and audio transcripts. The report is written to
+stdout or to --output.
+
+The report is a Go template. To change it, save your own as
+~/.config/etu/templates/digest/markdown.tmpl (or html.tmpl), or pass
+--template. --default-template prints the built-in one to start from.`,
+	Example: `  etu digest --period week > week.md
+  etu digest --period week --previous --format html -o last-week.html
+  etu digest --default-template > ~/.config/etu/templates/digest/markdown.tmpl`,
+	Args: cobra.NoArgs,
+	RunE: runDigest,
+}
+
+const defaultMarkdownDigest = `# {{.Title}}
+
+{{.Entries}} {{plural .Entries "entry" "entries"}} · {{.Words}} words · {{.From | date}} to {{.Last | date}}
+
+## By day
+{{range .Days}}
+### {{.Date | date}}
+{{range .Entries}}
+- **{{.Time}}**{{range .Tags}} ` + "`#{{.}}`" + `{{end}} {{indent .Text}}
+{{- range .Images}}
+  - Image: {{.URL}}{{if .Text}}
+{{quote .Text}}{{end}}
+{{- end}}
+{{- range .Audios}}
+  - Audio: {{.URL}}{{if .Text}}
+{{quote .Text}}{{end}}
+{{- end}}
+{{- end}}
+{{end}}
+{{- if .Tags}}
+## By tag
+{{range .Tags}}
+### {{.Name}} ({{len .Entries}})
+{{range .Entries}}
+- {{.CreatedAt | date}} {{.Time}}: {{firstLine .Text}}
+{{- end}}
+{{end}}
+{{- end}}
+`
+
+const defaultHTMLDigest = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 46em; margin: 2em auto; line-height: 1.5; }
+.entry { white-space: pre-wrap; }
+.meta, .media { color: #666; }
+blockquote { margin: 0.25em 0 0.25em 1em; color: #444; white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">{{.Entries}} {{plural .Entries "entry" "entries"}} · {{.Words}} words · {{.From | date}} to {{.Last | date}}</p>
+<h2>By day</h2>
+{{range .Days}}
+<h3>{{.Date | date}}</h3>
+<ul>
+{{- range .Entries}}
+<li><strong>{{.Time}}</strong>{{range .Tags}} <code>#{{.}}</code>{{end}}
+<div class="entry">{{.Text}}</div>
+{{- range .Images}}
+<div class="media">Image: <a href="{{.URL}}">{{.URL}}</a>{{if .Text}}<blockquote>{{.Text}}</blockquote>{{end}}</div>
+{{- end}}
+{{- range .Audios}}
+<div class="media">Audio: <a href="{{.URL}}">{{.URL}}</a>{{if .Text}}<blockquote>{{.Text}}</blockquote>{{end}}</div>
+{{- end}}
+</li>
+{{- end}}
+</ul>
+{{end}}
+{{- if .Tags}}
+<h2>By tag</h2>
+{{range .Tags}}
+<h3>{{.Name}} ({{len .Entries}})</h3>
+<ul>
+{{- range .Entries}}
+<li>{{.CreatedAt | date}} {{.Time}}: {{firstLine .Text}}</li>
+{{- end}}
+</ul>
+{{end}}
+{{- end}}
+</body>
+</html>
+`
+
+// digestFormats maps each format to its built-in template.
+var digestFormats = map[string]string{
+	"markdown": defaultMarkdownDigest,
+	"html":     defaultHTMLDigest,
+}
+
+// digestMedia is an attachment and the text extracted from it.
+type digestMedia struct {
+	URL  string
+	Text string
+}
+
+// digestEntry is one entry as digest templates see it.
+type digestEntry struct {
+	ID        string
+	CreatedAt time.Time
+	Time      string // time of day in the display timezone and clock
+	Text      string
+	Tags      []string
+	Images    []digestMedia
+	Audios    []digestMedia
+}
+
+// digestDay is a day's entries, oldest first.
+type digestDay struct {
+	Date    time.Time
+	Entries []digestEntry
+}
+
+// digestTag is the entries with a tag, oldest first.
+type digestTag struct {
+	Name    string
+	Entries []digestEntry
+}
+
+// digestData is what digest templates are executed with.
+type digestData struct {
+	Title     string
+	From      time.Time
+	To        time.Time // exclusive
+	Last      time.Time // the last day of the range
+	Generated time.Time
+	Entries   int
+	Words     int
+	Days      []digestDay
+	Tags      []digestTag // by name
+}
+
+// newDigestData groups newest-first posts by day and tag, oldest first.
+func newDigestData(title string, from, to time.Time, posts []*client.Post, now time.Time) digestData {
+	d := digestData{
+		Title:     title,
+		From:      from,
+		To:        to,
+		Last:      to.AddDate(0, 0, -1),
+		Generated: now,
+		Entries:   len(posts),
+	}
+	byTag := map[string][]digestEntry{}
+	days := groupByDay(posts, from.Location())
+	for i := len(days) - 1; i >= 0; i-- {
+		day := digestDay{Date: days[i].date}
+		for j := len(days[i].posts) - 1; j >= 0; j-- {
+			p := days[i].posts[j]
+			e := digestEntry{
+				ID:        p.PageID,
+				CreatedAt: p.CreatedAt.In(from.Location()),
+				Time:      p.CreatedAt.In(from.Location()).Format(displayTime.clock),
+				Text:      strings.TrimSpace(p.Text),
+				Tags:      p.Tags,
+			}
+			for _, img := range p.Images {
+				e.Images = append(e.Images, digestMedia{URL: img.GetUrl(), Text: strings.TrimSpace(img.GetExtractedText())})
+			}
+			for _, aud := range p.Audios {
+				e.Audios = append(e.Audios, digestMedia{URL: aud.GetUrl(), Text: strings.TrimSpace(aud.GetTranscribedText())})
+			}
+			d.Words += len(strings.Fields(p.Text))
+			day.Entries = append(day.Entries, e)
+			for _, t := range p.Tags {
+				byTag[t] = append(byTag[t], e)
+			}
+		}
+		d.Days = append(d.Days, day)
+	}
+	for name, entries := range byTag {
+		d.Tags = append(d.Tags, digestTag{Name: name, Entries: entries})
+	}
+	sort.Slice(d.Tags, func(a, b int) bool { return d.Tags[a].Name < d.Tags[b].Name })
+	return d
+}
+
+// digestFuncs are the helpers available to digest templates.
+var digestFuncs = map[string]any{
+	"date":      func(t time.Time) string { return t.Format("Mon Jan 2 2006") },
+	"time":      formatTime,
+	"firstLine": firstLine,
+	"plural":    plural,
+	"join":      strings.Join,
+	// indent continues multi-line text inside a Markdown list item.
+	"indent": func(s string) string { return strings.ReplaceAll(s, "\n", "\n  ") },
+	// quote renders text as a Markdown blockquote nested in a list item.
+	"quote": func(s string) string { return "    > " + strings.ReplaceAll(s, "\n", "\n    > ") },
+}
+
+// digestTemplate returns the template source for format: the file given,
+// else the user's override, else the built-in one.
+func digestTemplate(format, file string) (string, error) {
+	if file != "" {
+		src, err := os.ReadFile(file) //nolint:gosec // G304: the user's own template
+		if err != nil {
+			return "", fmt.Errorf("read digest template: %w", err)
+		}
+		return string(src), nil
+	}
+	dir, err := client.TemplatesDir()
+	if err != nil {
+		return "", err
+	}
+	src, err := os.ReadFile(filepath.Join(dir, "digest", format+templateExt)) //nolint:gosec // G304: path is under the templates dir
+	if err == nil {
+		return string(src), nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("read digest template: %w", err)
+	}
+	return digestFormats[format], nil
+}
+
+// renderDigest executes src for format with data, escaping HTML for html.
+func renderDigest(w io.Writer, format, src string, data digestData) error {
+	var buf bytes.Buffer
+	if format == "html" {
+		tmpl, err := htmltemplate.New("digest").Funcs(digestFuncs).Parse(src)
+		if err != nil {
+			return fmt.Errorf("parse digest template: %w", err)
+		}
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return fmt.Errorf("render digest: %w", err)
+		}
+	} else {
+		tmpl, err := template.New("digest").Funcs(digestFuncs).Parse(src)
+		if err != nil {
+			return fmt.Errorf("parse digest template: %w", err)
+		}
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return fmt.Errorf("render digest: %w", err)
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func runDigest(cmd *cobra.Command, _ []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	format = strings.ToLower(format)
+	if format == "md" {
+		format = "markdown"
+	}
+	if _, ok := digestFormats[format]; !ok {
+		return fmt.Errorf("unknown format %q: use markdown or html", format)
+	}
+	if show, _ := cmd.Flags().GetBool("default-template"); show {
+		fmt.Fprint(cmd.OutOrStdout(), digestFormats[format])
+		return nil
+	}
+
+	period, _ := cmd.Flags().GetString("period")
+	previous, _ := cmd.Flags().GetBool("previous")
+	now := time.Now().In(displayTime.loc)
+	from, to, _, err := reviewPeriod(period, now, previous)
+	if err != nil {
+		return err
+	}
+	file, _ := cmd.Flags().GetString("template")
+	src, err := digestTemplate(format, file)
+	if err != nil {
+		return err
+	}
+
+	posts, _, err := client.ListPostsBetween(cmd.Context(), journal, from, to)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("Journal digest: %s – %s", from.Format("Jan 2"), to.AddDate(0, 0, -1).Format("Jan 2 2006"))
+	if daysBetween(from, to) == 1 {
+		title = "Journal digest: " + from.Format("Mon Jan 2 2006")
+	}
+	data := newDigestData(title, from, to, posts, now)
+
+	output, _ := cmd.Flags().GetString("output")
+	if output == "" || output == "-" {
+		return renderDigest(cmd.OutOrStdout(), format, src, data)
+	}
+	var buf bytes.Buffer
+	if err := renderDigest(&buf, format, src, data); err != nil {
+		return err
+	}
+	if err := os.WriteFile(output, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("write digest: %w", err)
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Wrote %d %s to %s\n", data.Entries, plural(data.Entries, "entry", "entries"), output)
+	return nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/icco/etu-backend/proto"
+	"github.com/icco/etu/client"
+)
+
+func TestNewDigestData(t *testing.T) {
+	useDisplayTime(t, timeFormat{loc: time.UTC, layout: "2006-01-02 15:04", clock: "15:04", now: time.Now})
+	from := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	at := func(d, h int) time.Time { return time.Date(2026, 3, d, h, 0, 0, 0, time.UTC) }
+	posts := []*client.Post{
+		{PageID: "c", Text: "evening", Tags: []string{"home"}, CreatedAt: at(10, 20)},
+		{PageID: "b", Text: "standup notes", Tags: []string{"work"}, CreatedAt: at(10, 9),
+			Audios: []*proto.NoteAudio{{Url: "https://x/a.m4a", TranscribedText: "said things"}}},
+		{PageID: "a", Text: "whiteboard", Tags: []string{"work"}, CreatedAt: at(9, 14),
+			Images: []*proto.NoteImage{{Url: "https://x/i.png", ExtractedText: " plan \n"}}},
+	}
+	d := newDigestData("Week", from, from.AddDate(0, 0, 7), posts, at(12, 0))
+
+	if d.Entries != 3 || d.Words != 4 || !d.Last.Equal(at(15, 0)) {
+		t.Errorf("Entries, Words, Last = %d, %d, %v", d.Entries, d.Words, d.Last)
+	}
+	if len(d.Days) != 2 || !d.Days[0].Date.Equal(from) || len(d.Days[1].Entries) != 2 {
+		t.Fatalf("Days = %+v, want Mar 9 then Mar 10 with 2 entries", d.Days)
+	}
+	if e := d.Days[1].Entries[0]; e.ID != "b" || e.Time != "09:00" || e.Audios[0].Text != "said things" {
+		t.Errorf("first entry of Mar 10 = %+v, want b at 09:00 with its transcript", e)
+	}
+	if got := d.Days[0].Entries[0].Images[0].Text; got != "plan" {
+		t.Errorf("image text = %q, want trimmed", got)
+	}
+	if len(d.Tags) != 2 || d.Tags[0].Name != "home" || d.Tags[1].Name != "work" || d.Tags[1].Entries[0].ID != "a" {
+		t.Errorf("Tags = %+v, want home then work, oldest first", d.Tags)
+	}
+}
+
+func TestRenderDigest(t *testing.T) {
+	useDisplayTime(t, timeFormat{loc: time.UTC, layout: "2006-01-02 15:04", clock: "15:04", now: time.Now})
+	from := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	posts := []*client.Post{{
+		PageID: "a", Text: "<b>line one</b>\nline two", Tags: []string{"work"}, CreatedAt: from.Add(14 * time.Hour),
+		Images: []*proto.NoteImage{{Url: "https://x/i.png", ExtractedText: "ocr text"}},
+	}}
+	d := newDigestData("Journal digest", from, from.AddDate(0, 0, 7), posts, from)
+
+	var md strings.Builder
+	if err := renderDigest(&md, "markdown", defaultMarkdownDigest, d); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# Journal digest\n",
+		"1 entry · 4 words · Mon Mar 9 2026 to Sun Mar 15 2026",
+		"### Mon Mar 9 2026\n",
+		"- **14:00** `#work` <b>line one</b>\n  line two\n",
+		"  - Image: https://x/i.png\n    > ocr text\n",
+		"### work (1)\n",
+		"- Mon Mar 9 2026 14:00: <b>line one</b>",
+	} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("markdown missing %q:\n%s", want, md.String())
+		}
+	}
+
+	var html strings.Builder
+	if err := renderDigest(&html, "html", defaultHTMLDigest, d); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html.String(), "&lt;b&gt;line one&lt;/b&gt;") || !strings.Contains(html.String(), "<blockquote>ocr text</blockquote>") {
+		t.Errorf("html should escape entries and include OCR text:\n%s", html.String())
+	}
+
+	if err := renderDigest(&md, "markdown", "{{.Nope}}", d); err == nil {
+		t.Error("unknown field: want error")
+	}
+}
+
+func TestCommandDigest(t *testing.T) {
+	srv := startFakeBackend(t)
+	useDisplayTime(t, displayTime)
+	now := time.Now()
+	srv.AddNote("wrote the digest", now.Add(-time.Minute), "etu")
+	srv.AddNote("ancient", now.AddDate(0, 0, -40))
+
+	out, err := runCLI(t, "", "digest", "--period", "day")
+	if err != nil {
+		t.Fatalf("digest: %v", err)
+	}
+	if !strings.Contains(out, "wrote the digest") || !strings.Contains(out, "### etu (1)") || strings.Contains(out, "ancient") {
+		t.Errorf("output:\n%s", out)
+	}
+
+	// A template under the config dir overrides the built-in one.
+	dir, err := client.TemplatesDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "digest"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	custom := "{{range .Days}}{{range .Entries}}* {{.Text}}\n{{end}}{{end}}"
+	if err := os.WriteFile(filepath.Join(dir, "digest", "html.tmpl"), []byte(custom), 0600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "digest.html")
+	if _, err := runCLI(t, "", "digest", "--period", "month", "--format", "html", "-o", path); err != nil {
+		t.Fatalf("digest -o: %v", err)
+	}
+	got, err := os.ReadFile(path) //nolint:gosec // G304: test temp file
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "* wrote the digest\n" {
+		t.Errorf("file = %q, want the custom template's output", got)
+	}
+
+	if _, err := runCLI(t, "", "digest", "--format", "pdf"); err == nil {
+		t.Error("digest --format pdf: want error")
+	}
+}
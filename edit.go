@@ -20,7 +20,7 @@ var editCmd = &cobra.Command{
 
 func editPost(cmd *cobra.Command, _ []string) error {
 	// Show list of posts to select from
-	model := newPostListModel(cfg, 25, "Select entry to edit", true)
+	model := newPostListModel(cmd.Context(), journal, 25, "Select entry to edit", true)
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	finalModel, err := p.Run()
 	if err != nil {
@@ -40,7 +40,7 @@ func editPost(cmd *cobra.Command, _ []string) error {
 	err = spinner.New().
 		Title("Loading full content...").
 		Action(func() {
-			text, fetchErr = cfg.GetPostFullContent(cmd.Context(), selectedPost.PageID)
+			text, fetchErr = journal.GetPostFullContent(cmd.Context(), selectedPost.PageID)
 		}).
 		Run()
 
@@ -56,7 +56,7 @@ func editPost(cmd *cobra.Command, _ []string) error {
 		huh.NewGroup(
 			huh.NewText().
 				Value(&text).
-				Title(fmt.Sprintf("Edit entry from %s", selectedPost.CreatedAt.Format("2006-01-02 15:04"))).
+				Title(fmt.Sprintf("Edit entry from %s", formatTime(selectedPost.CreatedAt))).
 				Validate(func(value string) error {
 					if len(strings.TrimSpace(value)) == 0 {
 						return fmt.Errorf("journal entry cannot be empty")
@@ -86,7 +86,7 @@ func editPost(cmd *cobra.Command, _ []string) error {
 	err = spinner.New().
 		Title("Updating entry...").
 		Action(func() {
-			_, updateErr = cfg.UpdatePost(cmd.Context(), selectedPost.PageID, text)
+			_, updateErr = journal.UpdatePost(cmd.Context(), selectedPost.PageID, text)
 		}).
 		Run()
 
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/icco/etu/client"
+	"github.com/spf13/cobra"
+)
+
+// contextTimeout bounds how long any one provider may take, so a slow git
+// or tmux never holds up saving.
+const contextTimeout = 2 * time.Second
+
+// ContextFact is one piece of context about where an entry was written.
+type ContextFact struct {
+	// Key labels the fact in the footer, e.g. "branch".
+	Key string
+	// Value is the footer text, e.g. "main".
+	Value string
+	// Tag is the tag form of the fact, e.g. "branch:main"; empty for facts
+	// that make poor tags.
+	Tag string
+}
+
+// ContextProvider captures context for new entries. To add one, implement
+// it and append it to contextProviders.
+type ContextProvider interface {
+	// Name is how the provider is selected in config and --context.
+	Name() string
+	// Collect returns facts about the current environment, or none when the
+	// provider doesn't apply (e.g. outside a git repository).
+	Collect(ctx context.Context) ([]ContextFact, error)
+}
+
+// contextProviders are the built-in providers, in footer order.
+var contextProviders = []ContextProvider{
+	gitContext{},
+	cwdContext{},
+	tmuxContext{},
+	hostContext{},
+}
+
+// gitContext reports the repository, branch and last commit of the working directory.
+type gitContext struct{}
+
+func (gitContext) Name() string { return "git" }
+
+func (gitContext) Collect(ctx context.Context) ([]ContextFact, error) {
+	top, err := runCommand(ctx, "git", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, nil // not in a repository
+	}
+	repo := filepath.Base(strings.TrimSpace(string(top)))
+	facts := []ContextFact{{Key: "repo", Value: repo, Tag: "repo:" + repo}}
+	if out, err := runCommand(ctx, "git", "rev-parse", "--abbrev-ref", "HEAD"); err == nil {
+		branch := strings.TrimSpace(string(out))
+		facts = append(facts, ContextFact{Key: "branch", Value: branch, Tag: "branch:" + branch})
+	}
+	if out, err := runCommand(ctx, "git", "log", "-1", "--format=%h %s"); err == nil {
+		facts = append(facts, ContextFact{Key: "commit", Value: strings.TrimSpace(string(out))})
+	}
+	return facts, nil
+}
+
+// cwdContext reports the working directory.
+type cwdContext struct{}
+
+func (cwdContext) Name() string { return "cwd" }
+
+func (cwdContext) Collect(context.Context) ([]ContextFact, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	return []ContextFact{{Key: "cwd", Value: wd, Tag: "dir:" + filepath.Base(wd)}}, nil
+}
+
+// tmuxContext reports the tmux session when running inside tmux.
+type tmuxContext struct{}
+
+func (tmuxContext) Name() string { return "tmux" }
+
+func (tmuxContext) Collect(ctx context.Context) ([]ContextFact, error) {
+	if os.Getenv("TMUX") == "" {
+		return nil, nil
+	}
+	out, err := runCommand(ctx, "tmux", "display-message", "-p", "#S")
+	if err != nil {
+		return nil, err
+	}
+	session := strings.TrimSpace(string(out))
+	return []ContextFact{{Key: "tmux", Value: session, Tag: "tmux:" + session}}, nil
+}
+
+// hostContext reports the machine's hostname.
+type hostContext struct{}
+
+func (hostContext) Name() string { return "hostname" }
+
+func (hostContext) Collect(context.Context) ([]ContextFact, error) {
+	host, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	return []ContextFact{{Key: "host", Value: host, Tag: "host:" + host}}, nil
+}
+
+// findContextProviders resolves provider names, in the order given.
+func findContextProviders(names []string) ([]ContextProvider, error) {
+	var out []ContextProvider
+	for _, name := range names {
+		var found ContextProvider
+		for _, p := range contextProviders {
+			if p.Name() == name {
+				found = p
+				break
+			}
+		}
+		if found == nil {
+			valid := make([]string, 0, len(contextProviders))
+			for _, p := range contextProviders {
+				valid = append(valid, p.Name())
+			}
+			return nil, fmt.Errorf("unknown context provider %q (available: %s)", name, strings.Join(valid, ", "))
+		}
+		out = append(out, found)
+	}
+	return out, nil
+}
+
+// collectContext runs each provider, warning on w about any that fail.
+func collectContext(ctx context.Context, providers []ContextProvider, w io.Writer) []ContextFact {
+	var facts []ContextFact
+	for _, p := range providers {
+		pctx, cancel := context.WithTimeout(ctx, contextTimeout)
+		got, err := p.Collect(pctx)
+		cancel()
+		if err != nil {
+			fmt.Fprintf(w, "etu: skipping %s context: %v\n", p.Name(), err)
+			continue
+		}
+		facts = append(facts, got...)
+	}
+	return facts
+}
+
+// contextFooter renders facts as a footer appended to an entry.
+func contextFooter(facts []ContextFact) string {
+	var b strings.Builder
+	b.WriteString("\n\n---\n")
+	for _, f := range facts {
+		fmt.Fprintf(&b, "%s: %s\n", f.Key, f.Value)
+	}
+	return b.String()
+}
+
+// addEntryContext applies the configured context providers to an entry,
+// returning the text to save and a context carrying any tags.
+func addEntryContext(cmd *cobra.Command, text string) (context.Context, string, error) {
+	ctx := cmd.Context()
+	names := cfg.Context
+	if cmd.Flags().Changed("context") {
+		names, _ = cmd.Flags().GetStringSlice("context")
+	}
+	if off, _ := cmd.Flags().GetBool("no-context"); off || len(names) == 0 {
+		return ctx, text, nil
+	}
+	providers, err := findContextProviders(names)
+	if err != nil {
+		return ctx, text, err
+	}
+	facts := collectContext(ctx, providers, cmd.ErrOrStderr())
+	if len(facts) == 0 {
+		return ctx, text, nil
+	}
+
+	switch cfg.ContextFormat {
+	case "", "footer":
+		return ctx, strings.TrimRight(text, "\n") + contextFooter(facts), nil
+	case "tags":
+		var tags []string
+		for _, f := range facts {
+			if f.Tag != "" {
+				tags = append(tags, f.Tag)
+			}
+		}
+		return client.WithTags(ctx, tags...), text, nil
+	default:
+		return ctx, text, fmt.Errorf("unknown context_format %q: use footer or tags", cfg.ContextFormat)
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGitContext(t *testing.T) {
+	fakeTools(t, nil, func(name string, args []string) ([]byte, error) {
+		switch strings.Join(args, " ") {
+		case "rev-parse --show-toplevel":
+			return []byte("/home/me/src/etu\n"), nil
+		case "rev-parse --abbrev-ref HEAD":
+			return []byte("main\n"), nil
+		case "log -1 --format=%h %s":
+			return []byte("abc1234 Fix the thing\n"), nil
+		}
+		return nil, errors.New("unexpected " + name)
+	})
+
+	facts, err := gitContext{}.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	want := []ContextFact{
+		{Key: "repo", Value: "etu", Tag: "repo:etu"},
+		{Key: "branch", Value: "main", Tag: "branch:main"},
+		{Key: "commit", Value: "abc1234 Fix the thing"},
+	}
+	if !reflect.DeepEqual(facts, want) {
+		t.Errorf("facts = %+v, want %+v", facts, want)
+	}
+}
+
+func TestContextProvidersNotApplicable(t *testing.T) {
+	fakeTools(t, nil, func(string, []string) ([]byte, error) {
+		return nil, errors.New("fatal: not a git repository")
+	})
+	t.Setenv("TMUX", "")
+
+	for _, p := range []ContextProvider{gitContext{}, tmuxContext{}} {
+		facts, err := p.Collect(context.Background())
+		if err != nil || len(facts) != 0 {
+			t.Errorf("%s: facts = %v, err = %v; want nothing", p.Name(), facts, err)
+		}
+	}
+}
+
+func TestFindContextProviders(t *testing.T) {
+	got, err := findContextProviders([]string{"hostname", "git"})
+	if err != nil {
+		t.Fatalf("findContextProviders: %v", err)
+	}
+	if len(got) != 2 || got[0].Name() != "hostname" || got[1].Name() != "git" {
+		t.Errorf("providers = %v", got)
+	}
+	if _, err := findContextProviders([]string{"weather"}); err == nil || !strings.Contains(err.Error(), "available: git, cwd, tmux, hostname") {
+		t.Errorf("err = %v, want list of providers", err)
+	}
+}
+
+// failingContext always fails, to check that saving carries on.
+type failingContext struct{}
+
+func (failingContext) Name() string { return "broken" }
+
+func (failingContext) Collect(context.Context) ([]ContextFact, error) {
+	return nil, errors.New("boom")
+}
+
+func TestCollectContextSkipsFailures(t *testing.T) {
+	var warn bytes.Buffer
+	facts := collectContext(context.Background(), []ContextProvider{failingContext{}, cwdContext{}}, &warn)
+	if len(facts) != 1 || facts[0].Key != "cwd" {
+		t.Errorf("facts = %v, want only cwd", facts)
+	}
+	if !strings.Contains(warn.String(), "skipping broken context: boom") {
+		t.Errorf("warning = %q", warn.String())
+	}
+}
+
+func TestContextFooter(t *testing.T) {
+	got := contextFooter([]ContextFact{{Key: "branch", Value: "main"}, {Key: "host", Value: "laptop"}})
+	if want := "\n\n---\nbranch: main\nhost: laptop\n"; got != want {
+		t.Errorf("footer = %q, want %q", got, want)
+	}
+}
+
+func TestCommandCreateWithContext(t *testing.T) {
+	host, err := os.Hostname()
+	if err != nil {
+		t.Skip("no hostname")
+	}
+
+	t.Run("footer from flag", func(t *testing.T) {
+		srv := startFakeBackend(t)
+		if _, err := runCLI(t, "deploying\n", "create", "--context", "hostname"); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		if got, want := srv.Notes()[0].GetContent(), "deploying\n\n---\nhost: "+host+"\n"; got != want {
+			t.Errorf("content = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("tags from config", func(t *testing.T) {
+		srv := startFakeBackend(t)
+		cfg.Context = []string{"hostname"}
+		cfg.ContextFormat = "tags"
+		if _, err := runCLI(t, "deploying", "create"); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		note := srv.Notes()[0]
+		if note.GetContent() != "deploying" || !reflect.DeepEqual(note.GetTags(), []string{"host:" + host}) {
+			t.Errorf("note = %q %v", note.GetContent(), note.GetTags())
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		srv := startFakeBackend(t)
+		cfg.Context = []string{"hostname"}
+		if _, err := runCLI(t, "deploying", "create", "--no-context"); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		if got := srv.Notes()[0].GetContent(); got != "deploying" {
+			t.Errorf("content = %q", got)
+		}
+	})
+}
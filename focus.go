@@ -0,0 +1,357 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/icco/etu/client"
+	"github.com/spf13/cobra"
+)
+
+var focusCmd = &cobra.Command{
+	Use:   "focus [duration] [intent...]",
+	Short: "Run a focus timer that journals its start and a reflection at the end.",
+	Long: `Start a focus session: save a note with what you mean to do, count down,
+then write a reflection that is saved with how long you focused. The
+duration defaults to 25m; a bare number is minutes. Both notes are tagged
+"focus".
+
+Space pauses and resumes, s ends the session early, and q abandons it
+without a reflection. --today prints today's sessions.`,
+	Example: `  etu focus 25m "writing report"
+  etu focus 50 reviewing PRs
+  etu focus --today`,
+	Args: cobra.ArbitraryArgs,
+	RunE: runFocus,
+}
+
+const (
+	// focusTag is added to start and reflection notes.
+	focusTag = "focus"
+	// defaultFocus is the session length when none is given.
+	defaultFocus = 25 * time.Minute
+)
+
+// focusMetaPattern matches the line a reflection note records the session in.
+var focusMetaPattern = regexp.MustCompile(`(?m)^Focused (\S+) of (\S+)`)
+
+// focusArgs reads an optional leading duration and the intent after it.
+func focusArgs(args []string) (time.Duration, string, error) {
+	planned := defaultFocus
+	if len(args) > 0 {
+		if mins, err := strconv.Atoi(args[0]); err == nil {
+			planned, args = time.Duration(mins)*time.Minute, args[1:]
+		} else if d, err := time.ParseDuration(args[0]); err == nil {
+			planned, args = d, args[1:]
+		}
+	}
+	if planned < time.Minute {
+		return 0, "", fmt.Errorf("a focus session needs at least a minute")
+	}
+	intent := strings.TrimSpace(strings.Join(args, " "))
+	if intent == "" {
+		intent = "focus"
+	}
+	return planned, intent, nil
+}
+
+// focusLength renders d to the minute as "25m" or "1h05m", which
+// time.ParseDuration reads back.
+func focusLength(d time.Duration) string {
+	m := int(d.Round(time.Minute) / time.Minute)
+	if m < 60 {
+		return fmt.Sprintf("%dm", m)
+	}
+	return fmt.Sprintf("%dh%02dm", m/60, m%60)
+}
+
+// focusStartText is the note saved when a session starts.
+func focusStartText(intent string, planned time.Duration) string {
+	return fmt.Sprintf("Focus: %s (%s)", intent, focusLength(planned))
+}
+
+// reflectionText is the reflection note: what was written, then a line
+// recording the session.
+func reflectionText(reflection, startID string, focused, planned, paused time.Duration) string {
+	meta := fmt.Sprintf("Focused %s of %s", focusLength(focused), focusLength(planned))
+	if paused >= time.Minute {
+		meta += fmt.Sprintf(", paused %s", focusLength(paused))
+	}
+	if startID != "" {
+		meta += fmt.Sprintf(". Started in [[%s]]", startID)
+	}
+	return strings.TrimSpace(reflection) + "\n\n" + meta + "."
+}
+
+type focusTickMsg time.Time
+
+func focusTick() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg { return focusTickMsg(t) })
+}
+
+type reflectionSavedMsg struct {
+	post *client.Post
+	err  error
+}
+
+func saveReflection(j client.Journal, text string) tea.Cmd {
+	return func() tea.Msg {
+		post, err := j.SaveEntry(client.WithTags(context.Background(), focusTag), text, nil, nil)
+		return reflectionSavedMsg{post: post, err: err}
+	}
+}
+
+// focusModel counts a session down, then asks for a reflection.
+type focusModel struct {
+	journal client.Journal
+	intent  string
+	planned time.Duration
+	startID string
+	now     func() time.Time
+
+	started  time.Time
+	pausedAt time.Time // zero unless paused
+	paused   time.Duration
+	focused  time.Duration // set when the countdown ends
+
+	reflecting bool
+	saving     bool
+	editor     textarea.Model
+	bar        progress.Model
+	saved      *client.Post
+	status     string
+	quitting   bool
+}
+
+func newFocusModel(j client.Journal, intent string, planned time.Duration, startID string, now func() time.Time) focusModel {
+	ta := textarea.New()
+	ta.Placeholder = "How did it go?"
+	ta.ShowLineNumbers = false
+	ta.SetWidth(76)
+	ta.SetHeight(6)
+	ta.SetValue(intent + ": ")
+	return focusModel{
+		journal: j,
+		intent:  intent,
+		planned: planned,
+		startID: startID,
+		now:     now,
+		started: now(),
+		editor:  ta,
+		bar:     progress.New(progress.WithDefaultGradient(), progress.WithWidth(40), progress.WithoutPercentage()),
+	}
+}
+
+// elapsed is the time focused so far, not counting pauses.
+func (m focusModel) elapsed() time.Duration {
+	end := m.now()
+	if !m.pausedAt.IsZero() {
+		end = m.pausedAt
+	}
+	return end.Sub(m.started) - m.paused
+}
+
+// finish stops the countdown and opens the reflection editor.
+func (m focusModel) finish() (focusModel, tea.Cmd) {
+	if !m.pausedAt.IsZero() {
+		m.paused += m.now().Sub(m.pausedAt)
+		m.pausedAt = time.Time{}
+	}
+	m.focused = min(m.elapsed(), m.planned)
+	m.reflecting = true
+	m.editor.CursorEnd()
+	return m, m.editor.Focus()
+}
+
+func (m focusModel) Init() tea.Cmd {
+	return focusTick()
+}
+
+func (m focusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.editor.SetWidth(min(max(msg.Width-8, 20), 100))
+		return m, nil
+
+	case focusTickMsg:
+		if m.reflecting {
+			return m, nil
+		}
+		if m.pausedAt.IsZero() && m.elapsed() >= m.planned {
+			return m.finish()
+		}
+		return m, focusTick()
+
+	case reflectionSavedMsg:
+		m.saving = false
+		if msg.err != nil {
+			m.status = "Saving failed: " + msg.err.Error()
+			return m, nil
+		}
+		m.saved = msg.post
+		m.quitting = true
+		return m, tea.Quit
+
+	case tea.KeyMsg:
+		if m.reflecting {
+			return m.updateEditor(msg)
+		}
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			m.quitting = true
+			return m, tea.Quit
+		case " ", "p":
+			if m.pausedAt.IsZero() {
+				m.pausedAt = m.now()
+			} else {
+				m.paused += m.now().Sub(m.pausedAt)
+				m.pausedAt = time.Time{}
+			}
+		case "s", "enter":
+			return m.finish()
+		}
+	}
+	return m, nil
+}
+
+func (m focusModel) updateEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.saving {
+		return m, nil
+	}
+	switch msg.String() {
+	case "ctrl+c", "esc":
+		m.quitting = true
+		return m, tea.Quit
+	case "ctrl+s":
+		m.saving = true
+		m.status = "Saving..."
+		return m, saveReflection(m.journal, reflectionText(m.editor.Value(), m.startID, m.focused, m.planned, m.paused))
+	}
+	var cmd tea.Cmd
+	m.editor, cmd = m.editor.Update(msg)
+	return m, cmd
+}
+
+func (m focusModel) View() string {
+	if m.quitting {
+		return ""
+	}
+	var s strings.Builder
+	s.WriteString(timelineHeaderStyle.Render("Focus: " + m.intent))
+	s.WriteString("\n\n")
+	if m.reflecting {
+		fmt.Fprintf(&s, "Focused %s of %s.\n\n", focusLength(m.focused), focusLength(m.planned))
+		s.WriteString(m.editor.View())
+		s.WriteString("\n")
+		s.WriteString(timelineHelpStyle.Render("ctrl+s save reflection · esc skip"))
+	} else {
+		elapsed := m.elapsed()
+		left := max(m.planned-elapsed, 0)
+		s.WriteString(lipgloss.NewStyle().Bold(true).Render(formatClock(left)))
+		s.WriteString("  " + m.bar.ViewAs(min(float64(elapsed)/float64(m.planned), 1)))
+		if !m.pausedAt.IsZero() {
+			s.WriteString(timelineLongStyle.Render("  paused"))
+		}
+		s.WriteString("\n\n")
+		s.WriteString(timelineHelpStyle.Render("space pause/resume · s finish now · q abandon"))
+	}
+	if m.status != "" {
+		s.WriteString("\n" + m.status)
+	}
+	return docStyle.Render(s.String())
+}
+
+// focusSession is a finished session read back from its reflection note.
+type focusSession struct {
+	at      time.Time
+	intent  string
+	focused time.Duration
+}
+
+// focusSessions finds the reflection notes in posts, oldest first.
+func focusSessions(posts []*client.Post) []focusSession {
+	var sessions []focusSession
+	for i := len(posts) - 1; i >= 0; i-- {
+		p := posts[i]
+		m := focusMetaPattern.FindStringSubmatch(p.Text)
+		if m == nil || !hasAnyTag(p, []string{focusTag}) {
+			continue
+		}
+		focused, err := time.ParseDuration(m[1])
+		if err != nil {
+			continue
+		}
+		intent, _, _ := strings.Cut(firstLine(p.Text), ":")
+		sessions = append(sessions, focusSession{at: p.CreatedAt, intent: intent, focused: focused})
+	}
+	return sessions
+}
+
+// printFocusSummary writes the day's sessions and total focused time.
+func printFocusSummary(w io.Writer, sessions []focusSession) {
+	var total time.Duration
+	for _, s := range sessions {
+		total += s.focused
+	}
+	fmt.Fprintf(w, "Today: %d focus %s, %s focused\n", len(sessions), plural(len(sessions), "session", "sessions"), focusLength(total))
+	for _, s := range sessions {
+		fmt.Fprintf(w, "  %s  %6s  %s\n", s.at.In(displayTime.loc).Format(displayTime.clock), focusLength(s.focused), s.intent)
+	}
+}
+
+// todaysFocus lists today's finished sessions.
+func todaysFocus(ctx context.Context, j client.Journal) ([]focusSession, error) {
+	today := startOfDay(time.Now(), displayTime.loc)
+	posts, _, err := client.ListPostsBetween(ctx, j, today, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	return focusSessions(posts), nil
+}
+
+func runFocus(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+	if today, _ := cmd.Flags().GetBool("today"); today {
+		sessions, err := todaysFocus(cmd.Context(), journal)
+		if err != nil {
+			return err
+		}
+		printFocusSummary(w, sessions)
+		return nil
+	}
+	planned, intent, err := focusArgs(args)
+	if err != nil {
+		return err
+	}
+	if !isInteractive(w) {
+		return fmt.Errorf("etu focus needs a terminal")
+	}
+
+	start, err := journal.SaveEntry(client.WithTags(cmd.Context(), focusTag), focusStartText(intent, planned), nil, nil)
+	if err != nil {
+		return err
+	}
+	final, err := tea.NewProgram(newFocusModel(journal, intent, planned, start.PageID, time.Now), tea.WithAltScreen()).Run()
+	if err != nil {
+		return err
+	}
+	m := final.(focusModel)
+	if m.saved != nil {
+		fmt.Fprintf(w, "Saved reflection %s.\n", m.saved.PageID)
+	}
+	sessions, err := todaysFocus(cmd.Context(), journal)
+	if err != nil {
+		return err
+	}
+	printFocusSummary(w, sessions)
+	return nil
+}
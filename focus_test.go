@@ -0,0 +1,128 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/icco/etu/client"
+	"github.com/icco/etu/client/fake"
+)
+
+func TestFocusArgs(t *testing.T) {
+	for _, tc := range []struct {
+		args    []string
+		planned time.Duration
+		intent  string
+	}{
+		{nil, 25 * time.Minute, "focus"},
+		{[]string{"50m", "writing report"}, 50 * time.Minute, "writing report"},
+		{[]string{"45", "reviewing", "PRs"}, 45 * time.Minute, "reviewing PRs"},
+		{[]string{"1h30m"}, 90 * time.Minute, "focus"},
+		{[]string{"inbox", "zero"}, 25 * time.Minute, "inbox zero"},
+	} {
+		planned, intent, err := focusArgs(tc.args)
+		if err != nil || planned != tc.planned || intent != tc.intent {
+			t.Errorf("focusArgs(%q) = %v, %q, %v; want %v, %q", tc.args, planned, intent, err, tc.planned, tc.intent)
+		}
+	}
+	if _, _, err := focusArgs([]string{"30s"}); err == nil {
+		t.Error("focusArgs(30s): want error")
+	}
+	if got := focusLength(65*time.Minute + 20*time.Second); got != "1h05m" {
+		t.Errorf("focusLength = %q, want 1h05m", got)
+	}
+}
+
+func TestFocusModelSession(t *testing.T) {
+	useDisplayTime(t, timeFormat{loc: time.UTC, layout: "2006-01-02 15:04", clock: "15:04", now: time.Now})
+	clock := time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)
+	now := func() time.Time { return clock }
+	j := fake.NewJournal()
+	m := newFocusModel(j, "writing report", 25*time.Minute, "start-1", now)
+
+	step := func(msg tea.Msg) tea.Cmd {
+		t.Helper()
+		updated, cmd := m.Update(msg)
+		m = updated.(focusModel)
+		return cmd
+	}
+	clock = clock.Add(10 * time.Minute)
+	step(focusTickMsg(clock))
+	if !strings.Contains(m.View(), "15:00") {
+		t.Errorf("view after 10m should show 15:00 left:\n%s", m.View())
+	}
+	step(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	clock = clock.Add(10 * time.Minute)
+	step(focusTickMsg(clock))
+	if m.reflecting || !strings.Contains(m.View(), "paused") || m.elapsed() != 10*time.Minute {
+		t.Fatalf("paused 10m: reflecting %v, elapsed %v", m.reflecting, m.elapsed())
+	}
+	step(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	clock = clock.Add(15 * time.Minute)
+	step(focusTickMsg(clock))
+	if !m.reflecting || m.focused != 25*time.Minute || m.paused != 10*time.Minute {
+		t.Fatalf("after 25m focused: reflecting %v, focused %v, paused %v", m.reflecting, m.focused, m.paused)
+	}
+	if got := m.editor.Value(); got != "writing report: " {
+		t.Errorf("reflection prefilled with %q", got)
+	}
+
+	step(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("first draft done")})
+	cmd := step(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if cmd == nil {
+		t.Fatal("ctrl+s should save")
+	}
+	step(cmd())
+	if m.saved == nil {
+		t.Fatalf("not saved: %q", m.status)
+	}
+	saved := j.Posts()[0]
+	want := "writing report: first draft done\n\nFocused 25m of 25m, paused 10m. Started in [[start-1]]."
+	if saved.Text != want || !hasAnyTag(saved, []string{focusTag}) {
+		t.Errorf("reflection = %q, tags %v; want %q tagged focus", saved.Text, saved.Tags, want)
+	}
+
+	var out strings.Builder
+	printFocusSummary(&out, focusSessions([]*client.Post{
+		saved,
+		{Text: "Focus: writing report (25m)", Tags: []string{focusTag}},
+		{Text: "Focused 5m of 5m but not tagged"},
+	}))
+	if !strings.HasPrefix(out.String(), "Today: 1 focus session, 25m focused\n") || !strings.Contains(out.String(), "   25m  writing report\n") {
+		t.Errorf("summary:\n%s", out.String())
+	}
+}
+
+func TestFocusModelFinishEarlyAndAbandon(t *testing.T) {
+	clock := time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)
+	m := newFocusModel(fake.NewJournal(), "inbox", 25*time.Minute, "", func() time.Time { return clock })
+	clock = clock.Add(7 * time.Minute)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if got := updated.(focusModel); !got.reflecting || got.focused != 7*time.Minute {
+		t.Errorf("s after 7m: reflecting %v, focused %v", got.reflecting, got.focused)
+	}
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if got := updated.(focusModel); !got.quitting || got.saved != nil {
+		t.Error("esc in the reflection should quit without saving")
+	}
+}
+
+func TestCommandFocusToday(t *testing.T) {
+	srv := startFakeBackend(t)
+	now := time.Now()
+	srv.AddNote("writing: went well\n\nFocused 25m of 25m.", now.Add(-time.Minute), focusTag)
+	srv.AddNote("old: yesterday\n\nFocused 50m of 50m.", now.AddDate(0, 0, -2), focusTag)
+
+	out, err := runCLI(t, "", "focus", "--today")
+	if err != nil {
+		t.Fatalf("focus --today: %v", err)
+	}
+	if !strings.HasPrefix(out, "Today: 1 focus session, 25m focused\n") || !strings.Contains(out, "writing") {
+		t.Errorf("output:\n%s", out)
+	}
+	if _, err := runCLI(t, "", "focus", "25m", "x"); err == nil {
+		t.Error("focus without a terminal: want error")
+	}
+}
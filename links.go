@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/icco/etu/client"
+	"github.com/spf13/cobra"
+)
+
+var linksCmd = &cobra.Command{
+	Use:   "links <id>",
+	Short: "Show the entries an entry links to and the ones linking back.",
+	Long: `Show an entry's neighborhood: the entries it refers to with [[note-id]] or
+[[2006-01-02]], and the entries that refer to it or to its day. --depth
+follows links further out.
+
+Links come from an index of every entry, cached in the config directory and
+rebuilt when it is more than 10 minutes old or with --refresh. Changes made
+with etu are indexed as they happen.`,
+	Example: `  etu links 3f2a9c
+  etu links 3f2a9c --depth 2`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLinks,
+}
+
+// linkIndexMaxAge is how old the cached link index may get before it is
+// rebuilt from the backend.
+const linkIndexMaxAge = 10 * time.Minute
+
+var linkStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("75")).Underline(true)
+
+// loadLinkIndex returns the link index, rebuilding it if it is stale or
+// refresh is set.
+func loadLinkIndex(ctx context.Context, j client.Journal, refresh bool) (*client.LinkIndex, error) {
+	maxAge := linkIndexMaxAge
+	if refresh {
+		maxAge = 0
+	}
+	x, err := client.RefreshLinkIndex(ctx, j, maxAge)
+	if err != nil && x != nil {
+		log.Printf("etu: writing link cache: %v", err)
+		err = nil
+	}
+	return x, err
+}
+
+// indexLine describes an indexed entry on one line.
+func indexLine(e client.IndexEntry) string {
+	return fmt.Sprintf("%s  %s  %s", e.ID, formatTime(e.CreatedAt), truncate(e.Title, 60))
+}
+
+// linkText shows each reference in text as its target's first line. A date
+// reference shows the day's first entry and how many more there are, or the
+// day itself if it has none; unknown references are left as written.
+func linkText(x *client.LinkIndex, text string, loc *time.Location) string {
+	return client.ReplaceLinks(text, func(ref string) string {
+		targets := x.Resolve(ref, loc)
+		day, isDay := client.LinkDay(ref, loc)
+		switch {
+		case len(targets) == 0 && isDay:
+			return linkStyle.Render("[[" + day.Format("Mon Jan 2 2006") + "]]")
+		case len(targets) == 0:
+			return "[[" + ref + "]]"
+		}
+		title := truncate(targets[0].Title, 40)
+		if isDay && len(targets) > 1 {
+			title += fmt.Sprintf(" +%d more", len(targets)-1)
+		}
+		return linkStyle.Render("[[" + title + "]]")
+	})
+}
+
+// writeLinks lists the entries text refers to and the entries referring to
+// id, and returns them all in the order listed, without repeats.
+func writeLinks(w io.Writer, x *client.LinkIndex, id, text string, loc *time.Location) []client.IndexEntry {
+	var listed []client.IndexEntry
+	seen := map[string]bool{id: true}
+	list := func(e client.IndexEntry) {
+		if !seen[e.ID] {
+			seen[e.ID] = true
+			listed = append(listed, e)
+		}
+	}
+
+	var links []client.IndexEntry
+	for _, ref := range client.ParseLinks(text) {
+		for _, e := range x.Resolve(ref, loc) {
+			if e.ID != id {
+				links = append(links, e)
+			}
+		}
+	}
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("170"))
+	for _, section := range []struct {
+		title   string
+		entries []client.IndexEntry
+	}{
+		{"Links:", links},
+		{"Backlinks:", x.Backlinks(id, loc)},
+	} {
+		if len(section.entries) == 0 {
+			continue
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, headerStyle.Render(section.title))
+		for _, e := range section.entries {
+			fmt.Fprintf(w, "  %s\n", indexLine(e))
+			list(e)
+		}
+	}
+	return listed
+}
+
+// followLink asks which linked entry to open next; it returns "" when the
+// user is done.
+func followLink(entries []client.IndexEntry) (string, error) {
+	opts := []huh.Option[string]{huh.NewOption("Done", "")}
+	for _, e := range entries {
+		opts = append(opts, huh.NewOption(formatTime(e.CreatedAt)+"  "+truncate(e.Title, 60), e.ID))
+	}
+	var id string
+	err := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Open a linked entry?").
+				Options(opts...).
+				Value(&id),
+		),
+	).Run()
+	return id, err
+}
+
+// printLinkGraph writes id's links and backlinks as a tree, following them
+// depth levels out. Entries already shown aren't expanded again.
+func printLinkGraph(w io.Writer, x *client.LinkIndex, id string, depth int, loc *time.Location) {
+	fmt.Fprintln(w, indexLine(x.Entries[id]))
+	writeNeighbors(w, x, id, depth, "  ", map[string]bool{id: true}, loc)
+}
+
+func writeNeighbors(w io.Writer, x *client.LinkIndex, id string, depth int, indent string, seen map[string]bool, loc *time.Location) {
+	if depth == 0 {
+		return
+	}
+	node := func(indent, arrow string, e client.IndexEntry) {
+		fmt.Fprintf(w, "%s%s %s\n", indent, arrow, indexLine(e))
+		if !seen[e.ID] {
+			seen[e.ID] = true
+			writeNeighbors(w, x, e.ID, depth-1, indent+"    ", seen, loc)
+		}
+	}
+	for _, ref := range x.Entries[id].Links {
+		targets := x.Resolve(ref, loc)
+		if _, ok := client.LinkDay(ref, loc); ok {
+			fmt.Fprintf(w, "%s→ %s  %d %s\n", indent, ref, len(targets), plural(len(targets), "entry", "entries"))
+			for _, t := range targets {
+				node(indent+"    ", "·", t)
+			}
+			continue
+		}
+		if len(targets) == 0 {
+			fmt.Fprintf(w, "%s→ %s  (not found)\n", indent, ref)
+			continue
+		}
+		node(indent, "→", targets[0])
+	}
+	for _, b := range x.Backlinks(id, loc) {
+		node(indent, "←", b)
+	}
+}
+
+func runLinks(cmd *cobra.Command, args []string) error {
+	depth, _ := cmd.Flags().GetInt("depth")
+	if depth < 1 {
+		return fmt.Errorf("--depth must be at least 1")
+	}
+	refresh, _ := cmd.Flags().GetBool("refresh")
+	x, err := loadLinkIndex(cmd.Context(), journal, refresh)
+	if err != nil {
+		return err
+	}
+	id := args[0]
+	if _, ok := x.Entries[id]; !ok {
+		// Newer than the index, or past how far back it reaches.
+		post, err := journal.GetPost(cmd.Context(), id)
+		if err != nil {
+			return err
+		}
+		x.Add(post)
+	}
+	printLinkGraph(cmd.OutOrStdout(), x, id, depth, displayTime.loc)
+	return nil
+}
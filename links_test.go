@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/icco/etu/client"
+)
+
+func TestLinkTextAndWriteLinks(t *testing.T) {
+	useDisplayTime(t, timeFormat{loc: time.UTC, layout: "2006-01-02 15:04", clock: "15:04", now: time.Now})
+	at := func(d, h int) time.Time { return time.Date(2026, 3, d, h, 0, 0, 0, time.UTC) }
+	x := client.NewLinkIndex([]*client.Post{
+		{PageID: "a", Text: "moving to Lisbon", CreatedAt: at(10, 9)},
+		{PageID: "b", Text: "Follow-up to [[a]]", CreatedAt: at(11, 9)},
+		{PageID: "c", Text: "still thinking about [[b]]", CreatedAt: at(12, 9)},
+	})
+
+	if got := linkText(x, "see [[a]], [[2026-03-11]], [[2026-03-14]] and [[gone]]", time.UTC); got != "see [[moving to Lisbon]], [[Follow-up to [[a]]]], [[Sat Mar 14 2026]] and [[gone]]" {
+		t.Errorf("linkText = %q", got)
+	}
+	busy := client.NewLinkIndex([]*client.Post{
+		{PageID: "m", Text: "morning pages", CreatedAt: at(10, 7)},
+		{PageID: "n", Text: "standup", CreatedAt: at(10, 10)},
+	})
+	if got := linkText(busy, "[[2026-03-10]]", time.UTC); got != "[[morning pages +1 more]]" {
+		t.Errorf("linkText of a busy day = %q", got)
+	}
+
+	var out strings.Builder
+	listed := writeLinks(&out, x, "b", "Follow-up to [[a]] and [[b]]", time.UTC)
+	want := "\nLinks:\n  a  2026-03-10 09:00  moving to Lisbon\n\nBacklinks:\n  c  2026-03-12 09:00  still thinking about [[b]]\n"
+	if out.String() != want {
+		t.Errorf("writeLinks wrote:\n%q\nwant:\n%q", out.String(), want)
+	}
+	if len(listed) != 2 || listed[0].ID != "a" || listed[1].ID != "c" {
+		t.Errorf("listed = %v, want a and c but not b itself", listed)
+	}
+}
+
+func TestCommandLinks(t *testing.T) {
+	srv := startFakeBackend(t)
+	useDisplayTime(t, timeFormat{loc: time.UTC, layout: "2006-01-02 15:04", clock: "15:04", now: time.Now})
+	day := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	a := srv.AddNote("the original", day)
+	b := srv.AddNote("Follow-up to [["+a+"]]", day.Add(24*time.Hour))
+	srv.AddNote("about [["+b+"]] and [[2026-03-10]]", day.Add(48*time.Hour))
+
+	out, err := runCLI(t, "", "links", b)
+	if err != nil {
+		t.Fatalf("links: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], b+"  ") || !strings.HasPrefix(lines[1], "  → "+a) || !strings.Contains(lines[2], "← ") {
+		t.Errorf("links %s:\n%s", b, out)
+	}
+
+	out, err = runCLI(t, "", "links", a, "--depth", "2")
+	if err != nil {
+		t.Fatalf("links --depth 2: %v", err)
+	}
+	if !strings.Contains(out, "  ← "+b) || !strings.Contains(out, "      ← ") || !strings.Contains(out, "about [[") {
+		t.Errorf("links %s --depth 2:\n%s", a, out)
+	}
+
+	// Entries newer than the cached index are fetched directly.
+	d := srv.AddNote("brand new [["+a+"]]", time.Now())
+	out, err = runCLI(t, "", "links", d)
+	if err != nil || !strings.Contains(out, "→ "+a) {
+		t.Errorf("links on an unindexed entry: %v\n%s", err, out)
+	}
+
+	if _, err := runCLI(t, "", "links", "missing"); err == nil {
+		t.Error("links missing: want error")
+	}
+}
@@ -7,10 +7,14 @@ import (
 	"io"
 	"log"
 	"math"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/icco/etu/client"
@@ -25,17 +29,31 @@ var (
 	docStyle          = lipgloss.NewStyle().Margin(1, 2)
 	itemStyle         = lipgloss.NewStyle().PaddingLeft(4)
 	selectedItemStyle = lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("170"))
+	markedItemStyle   = lipgloss.NewStyle().PaddingLeft(4).Foreground(lipgloss.Color("34"))
 )
 
+// bulkKeys are shown in the list help when bulk actions are enabled.
+var bulkKeys = []key.Binding{
+	key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "mark")),
+	key.NewBinding(key.WithKeys("*"), key.WithHelp("*", "mark all")),
+	key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete")),
+	key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "export")),
+	key.NewBinding(key.WithKeys("+"), key.WithHelp("+/-", "add/remove tag")),
+	key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "copy")),
+}
+
 type listItem struct {
 	post *client.Post
 }
 
-func (i listItem) Title() string       { return i.post.CreatedAt.Format("2006-01-02 15:04") }
+func (i listItem) Title() string       { return formatTime(i.post.CreatedAt) }
 func (i listItem) Description() string { return i.post.Text }
 func (i listItem) FilterValue() string { return i.post.Text }
 
-type itemDelegate struct{}
+// itemDelegate renders a post per line, with a * for marked ones.
+type itemDelegate struct {
+	marked map[string]bool
+}
 
 func (d itemDelegate) Height() int                             { return 1 }
 func (d itemDelegate) Spacing() int                            { return 0 }
@@ -46,17 +64,23 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, item list.Ite
 		return
 	}
 
+	marker := ">"
+	if d.marked[i.post.PageID] {
+		marker = "*"
+	}
 	var str string
 	if len(i.post.Tags) > 0 {
 		tags := "[" + strings.Join(i.post.Tags, ", ") + "]"
-		str = fmt.Sprintf("> %s %s - %s", i.Title(), tags, i.Description())
+		str = fmt.Sprintf("%s %s %s - %s", marker, i.Title(), tags, i.Description())
 	} else {
-		str = fmt.Sprintf("> %s - %s", i.Title(), i.Description())
+		str = fmt.Sprintf("%s %s - %s", marker, i.Title(), i.Description())
 	}
 
 	style := itemStyle
 	if index == m.Index() {
 		style = selectedItemStyle
+	} else if d.marked[i.post.PageID] {
+		style = markedItemStyle
 	}
 
 	if _, err := fmt.Fprint(w, style.Render(str)); err != nil {
@@ -71,11 +95,20 @@ type postListModel struct {
 	loadErr  error
 	posts    []*client.Post
 	selected *client.Post
-	cfg      *client.Config
+	ctx      context.Context
+	journal  client.Journal
 	count    int
 	title    string
 	query    string
 	quitting bool
+
+	// bulk enables marking entries and acting on them together.
+	bulk    bool
+	marked  map[string]bool // by PageID
+	prompt  bulkAction      // waiting for confirmation or input
+	input   textinput.Model
+	running bool
+	status  string
 }
 
 type postsLoadedMsg struct {
@@ -83,28 +116,29 @@ type postsLoadedMsg struct {
 	err   error
 }
 
-func loadPosts(cfg *client.Config, count int, query string) tea.Cmd {
+func loadPosts(ctx context.Context, j client.Journal, count int, query string) tea.Cmd {
 	return func() tea.Msg {
 		var posts []*client.Post
 		var err error
 		if query != "" {
-			posts, err = cfg.SearchPosts(context.Background(), query, count)
+			posts, err = j.SearchPosts(ctx, query, count)
 		} else {
-			posts, err = cfg.ListPosts(context.Background(), count)
+			posts, err = j.ListPosts(ctx, count)
 		}
 		return postsLoadedMsg{posts: posts, err: err}
 	}
 }
 
-func newPostListModel(cfg *client.Config, count int, title string, startLoading bool) postListModel {
+func newPostListModel(ctx context.Context, j client.Journal, count int, title string, startLoading bool) postListModel {
 	// Initialize spinner
 	sp := spinner.New()
 	sp.Spinner = spinner.Dot
 	sp.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("170"))
 
 	// Create empty list initially - will be populated when data loads
+	marked := map[string]bool{}
 	var items []list.Item
-	l := list.New(items, itemDelegate{}, 0, listBuffer)
+	l := list.New(items, itemDelegate{marked: marked}, 0, listBuffer)
 	l.Title = title
 	l.SetShowStatusBar(false)
 	l.SetFilteringEnabled(false)
@@ -117,10 +151,63 @@ func newPostListModel(cfg *client.Config, count int, title string, startLoading
 		list:    l,
 		spinner: sp,
 		loading: startLoading,
-		cfg:     cfg,
+		ctx:     ctx,
+		journal: j,
 		count:   count,
 		title:   title,
+		marked:  marked,
+		input:   textinput.New(),
+	}
+}
+
+// enableBulk turns on marking entries with space and * and acting on them
+// with d, e, + and -, and y.
+func (m *postListModel) enableBulk() {
+	m.bulk = true
+	// d pages down by default.
+	m.list.KeyMap.NextPage.SetKeys("right", "l", "pgdown", "f")
+	m.list.AdditionalShortHelpKeys = func() []key.Binding { return bulkKeys[:3] }
+	m.list.AdditionalFullHelpKeys = func() []key.Binding { return bulkKeys }
+}
+
+// refreshTitle shows the list title and how many entries are marked.
+func (m *postListModel) refreshTitle() {
+	m.list.Title = m.title
+	if m.query != "" {
+		m.list.Title = fmt.Sprintf("Search Results (%d)", len(m.posts))
+	}
+	if n := len(m.marked); n > 0 {
+		m.list.Title += fmt.Sprintf(" · %d marked", n)
+	}
+}
+
+// setPosts shows posts in the list.
+func (m *postListModel) setPosts(posts []*client.Post) {
+	m.posts = posts
+	items := make([]list.Item, len(posts))
+	for i, p := range posts {
+		items[i] = listItem{post: p}
 	}
+	m.list.SetItems(items)
+	m.list.SetHeight(int(math.Min(float64(listMaxSize+listBuffer), float64(len(items)+listBuffer))))
+	m.refreshTitle()
+}
+
+// targets are the marked posts in list order, or the highlighted one if
+// none are marked.
+func (m postListModel) targets() []*client.Post {
+	var posts []*client.Post
+	for _, p := range m.posts {
+		if m.marked[p.PageID] {
+			posts = append(posts, p)
+		}
+	}
+	if len(posts) == 0 {
+		if item, ok := m.list.SelectedItem().(listItem); ok {
+			posts = append(posts, item.post)
+		}
+	}
+	return posts
 }
 
 func (m postListModel) Init() tea.Cmd {
@@ -130,7 +217,7 @@ func (m postListModel) Init() tea.Cmd {
 	// Start loading posts asynchronously
 	return tea.Batch(
 		m.spinner.Tick,
-		loadPosts(m.cfg, m.count, m.query),
+		loadPosts(m.ctx, m.journal, m.count, m.query),
 	)
 }
 
@@ -142,26 +229,13 @@ func (m postListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.loadErr = msg.err
 			return m, nil
 		}
-		m.posts = msg.posts
+		m.setPosts(msg.posts)
 
-		// Update list with results
-		if len(m.posts) > 0 {
-			var items []list.Item
-			for _, p := range m.posts {
-				items = append(items, listItem{post: p})
-			}
-
-			m.list.SetItems(items)
-			m.list.SetHeight(int(math.Min(float64(listMaxSize+listBuffer), float64(len(items)+listBuffer))))
-			if m.query != "" {
-				m.list.Title = fmt.Sprintf("Search Results (%d)", len(m.posts))
-			} else {
-				m.list.Title = m.title
-			}
-		}
+	case bulkDoneMsg:
+		return m.bulkDone(msg), nil
 
 	case spinner.TickMsg:
-		if m.loading {
+		if m.loading || m.running {
 			var cmd tea.Cmd
 			m.spinner, cmd = m.spinner.Update(msg)
 			return m, cmd
@@ -172,6 +246,14 @@ func (m postListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.prompt != bulkNone {
+			return m.updatePrompt(msg)
+		}
+		if m.bulk && !m.loading && len(m.posts) > 0 {
+			if next, cmd, ok := m.updateBulk(msg); ok {
+				return next, cmd
+			}
+		}
 		switch keypress := msg.String(); keypress {
 		case "q", "ctrl+c":
 			m.quitting = true
@@ -199,6 +281,148 @@ func (m postListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// updateBulk handles the keys that mark entries and start bulk actions. It
+// reports whether it handled msg.
+func (m postListModel) updateBulk(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
+	if m.running {
+		// Wait for the running action; only ctrl+c gets through.
+		return m, nil, msg.String() != "ctrl+c"
+	}
+	switch msg.String() {
+	case " ":
+		if item, ok := m.list.SelectedItem().(listItem); ok {
+			m.toggleMark(item.post.PageID, !m.marked[item.post.PageID])
+			m.list.CursorDown()
+		}
+	case "*":
+		// Mark every visible entry, or unmark them if they all are.
+		visible := m.list.VisibleItems()
+		all := true
+		for _, item := range visible {
+			all = all && m.marked[item.(listItem).post.PageID]
+		}
+		for _, item := range visible {
+			m.toggleMark(item.(listItem).post.PageID, !all)
+		}
+	case "d":
+		m.prompt = bulkDelete
+	case "e":
+		return m.ask(bulkExport, "Export to: ", defaultExportPath(time.Now()))
+	case "+":
+		return m.ask(bulkAddTag, "Add tag: ", "")
+	case "-":
+		return m.ask(bulkRemoveTag, "Remove tag: ", "")
+	case "y":
+		next, cmd := m.start(bulkCopy, "")
+		return next, cmd, true
+	default:
+		return m, nil, false
+	}
+	m.status = ""
+	m.refreshTitle()
+	return m, nil, true
+}
+
+func (m *postListModel) toggleMark(id string, on bool) {
+	if on {
+		m.marked[id] = true
+	} else {
+		delete(m.marked, id)
+	}
+}
+
+// ask prompts for the tag or file action needs.
+func (m postListModel) ask(action bulkAction, prompt, value string) (tea.Model, tea.Cmd, bool) {
+	m.prompt = action
+	m.status = ""
+	m.input.Prompt = prompt
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	return m, m.input.Focus(), true
+}
+
+// start runs action on the targets in the background.
+func (m postListModel) start(action bulkAction, arg string) (tea.Model, tea.Cmd) {
+	posts := m.targets()
+	if len(posts) == 0 {
+		return m, nil
+	}
+	m.running = true
+	m.status = ""
+	return m, tea.Batch(m.spinner.Tick, runBulkAction(m.ctx, m.journal, action, arg, posts))
+}
+
+// updatePrompt handles keys while a bulk action waits for confirmation or
+// input.
+func (m postListModel) updatePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	action := m.prompt
+	if action == bulkDelete {
+		m.prompt = bulkNone
+		if msg.String() == "y" {
+			return m.start(bulkDelete, "")
+		}
+		return m, nil
+	}
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.prompt = bulkNone
+		m.input.Blur()
+		return m, nil
+	case "enter":
+		m.prompt = bulkNone
+		m.input.Blur()
+		arg := strings.TrimSpace(m.input.Value())
+		if action != bulkExport {
+			arg = strings.TrimPrefix(arg, "#")
+		}
+		if arg == "" {
+			return m, nil
+		}
+		return m.start(action, arg)
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// bulkDone shows how a bulk action went and updates the entries it changed.
+func (m postListModel) bulkDone(msg bulkDoneMsg) postListModel {
+	m.running = false
+	m.status = bulkSummary(msg)
+	posts := slices.Clone(m.posts)
+	switch msg.action {
+	case bulkDelete:
+		gone := map[string]bool{}
+		for _, r := range msg.results {
+			if r.err == nil {
+				gone[r.post.PageID] = true
+				delete(m.marked, r.post.PageID)
+			}
+		}
+		posts = slices.DeleteFunc(posts, func(p *client.Post) bool { return gone[p.PageID] })
+	case bulkAddTag, bulkRemoveTag:
+		updated := map[string]*client.Post{}
+		for _, r := range msg.results {
+			if r.err == nil && r.updated != nil {
+				updated[r.post.PageID] = r.updated
+			}
+		}
+		for i, p := range posts {
+			if u := updated[p.PageID]; u != nil {
+				posts[i] = u
+			}
+		}
+	default:
+		return m
+	}
+	index := m.list.Index()
+	m.setPosts(posts)
+	if len(posts) > 0 {
+		m.list.Select(min(index, len(posts)-1))
+	}
+	return m
+}
+
 func (m postListModel) View() string {
 	if m.quitting {
 		return ""
@@ -227,5 +451,17 @@ func (m postListModel) View() string {
 		s.WriteString("\n  No entries found.\n")
 	}
 
+	switch {
+	case m.prompt == bulkDelete:
+		n := len(m.targets())
+		fmt.Fprintf(&s, "\n  Delete %d %s? y to confirm, any other key to cancel", n, plural(n, "entry", "entries"))
+	case m.prompt != bulkNone:
+		s.WriteString("\n  " + m.input.View())
+	case m.running:
+		s.WriteString("\n  " + m.spinner.View() + " Working...")
+	case m.status != "":
+		s.WriteString("\n  " + strings.ReplaceAll(m.status, "\n", "\n  "))
+	}
+
 	return docStyle.Render(s.String())
 }
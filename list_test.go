@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/icco/etu/client"
+	"github.com/icco/etu/client/fake"
+)
+
+// The list, show, edit, delete and search commands all pick an entry through
+// postListModel; these tests drive it against an in-memory journal.
+
+func TestPostListModelSelectsEntry(t *testing.T) {
+	now := time.Now()
+	j := fake.NewJournal(
+		&client.Post{Text: "first", CreatedAt: now.Add(-2 * time.Hour)},
+		&client.Post{Text: "second", CreatedAt: now.Add(-time.Hour)},
+	)
+
+	m := newPostListModel(context.Background(), j, 25, "test", true)
+	msg := loadPosts(context.Background(), j, m.count, m.query)()
+	updated, _ := m.Update(msg)
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyDown})
+	updated, cmd := updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	got := updated.(postListModel)
+	if got.selected == nil {
+		t.Fatal("expected a selected post")
+	}
+	if got.selected.Text != "first" {
+		t.Errorf("selected %q, want %q", got.selected.Text, "first")
+	}
+	if cmd == nil {
+		t.Error("expected quit command after selection")
+	}
+}
+
+func TestPostListModelSearch(t *testing.T) {
+	j := fake.NewJournal(
+		&client.Post{Text: "deploy notes", CreatedAt: time.Now()},
+		&client.Post{Text: "lunch", CreatedAt: time.Now()},
+	)
+
+	m := newPostListModel(context.Background(), j, 50, "Search Results", false)
+	m.query = "deploy"
+	updated, _ := m.Update(loadPosts(context.Background(), j, m.count, m.query)())
+
+	got := updated.(postListModel)
+	if len(got.posts) != 1 || got.posts[0].Text != "deploy notes" {
+		t.Errorf("posts = %v, want only the deploy note", got.posts)
+	}
+}
+
+func TestPostListModelQuitWithoutSelection(t *testing.T) {
+	j := fake.NewJournal(&client.Post{Text: "x", CreatedAt: time.Now()})
+
+	m := newPostListModel(context.Background(), j, 25, "test", true)
+	updated, _ := m.Update(loadPosts(context.Background(), j, m.count, m.query)())
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+
+	if updated.(postListModel).selected != nil {
+		t.Error("expected no selection after quitting")
+	}
+}
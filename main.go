@@ -2,11 +2,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -24,16 +26,31 @@ var (
 	CommitSHA = ""
 
 	cfg *client.Config
+	// journal is the backend commands talk to; main points it at cfg, tests at a fake.
+	journal client.Journal
+	// history records edits and deletes made through journal for etu undo.
+	history *client.History
 
 	rootCmd = &cobra.Command{
-		Use:   "etu",
+		Use:   "etu [-]",
 		Short: "Etu. A personal command line journal.",
-		Args:  cobra.NoArgs,
+		Long:  "Etu. A personal command line journal.\n\nRun `etu -` to create an entry from stdin.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 || (len(args) == 1 && args[0] == "-") {
+				return nil
+			}
+			return fmt.Errorf("unknown command %q for %q", args[0], cmd.CommandPath())
+		},
 		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			if err := applyDisplayFlags(cmd); err != nil {
+				return err
+			}
+
 			// Skip API key validation for these commands (they don't need the backend)
 			curr := cmd
 			for curr != nil {
-				if curr.Name() == "completion" || curr.Name() == "help" || curr.Name() == "__complete" {
+				switch curr.Name() {
+				case "completion", "help", "__complete", "template":
 					return nil
 				}
 				curr = curr.Parent()
@@ -45,7 +62,12 @@ var (
 
 			return nil
 		},
-		RunE: func(cmd *cobra.Command, _ []string) error {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				// `etu -` is shorthand for `etu create -`.
+				createCmd.SetContext(cmd.Context())
+				return createPost(createCmd, args)
+			}
 			return cmd.Help()
 		},
 	}
@@ -53,9 +75,17 @@ var (
 	createCmd = &cobra.Command{
 		Use:     "create",
 		Aliases: []string{"c", "new"},
-		Short:   "Create a new journal entry (attach images with -i, audio with -a, or in TUI).",
-		Args:    cobra.NoArgs,
-		RunE:    createPost,
+		Short:   "Create a new journal entry (attach images with -i, audio with -a or --record, or in TUI).",
+		Long: `Create a new journal entry.
+
+With no arguments and a terminal on stdin, an interactive form opens. Otherwise
+the entry text comes from the arguments (joined with spaces), each -m/--message
+(as separate paragraphs), or stdin when piped or given as "-".`,
+		Example: `  etu create "switching to the release notes"
+  etu create -m "Shipped 1.2" -m "Next: docs" --tag release
+  git log -1 --format=%B | etu create - --print-id`,
+		Args: cobra.ArbitraryArgs,
+		RunE: createPost,
 	}
 
 	deleteCmd = &cobra.Command{
@@ -85,8 +115,14 @@ var (
 		Use:     "list",
 		Aliases: []string{"l"},
 		Short:   "List journal entries, with an optional starting datetime.",
-		Args:    cobra.NoArgs,
-		RunE:    listPosts,
+		Long: `Browse recent entries; enter shows one.
+
+Space marks an entry and * marks every entry shown. d deletes the marked
+entries after one confirmation, e exports them as Markdown (HTML for a .html
+file), + and - add or remove a tag, and y copies them to the clipboard.
+Without marks, these act on the highlighted entry. etu search works the same.`,
+		Args: cobra.NoArgs,
+		RunE: listPosts,
 	}
 
 	searchCmd = &cobra.Command{
@@ -96,75 +132,81 @@ var (
 		Args:    cobra.NoArgs,
 		RunE:    searchPosts,
 	}
+)
 
-	randomCmd = &cobra.Command{
-		Use:     "random",
-		Aliases: []string{"r"},
-		Short:   "Show a random journal entry.",
-		Args:    cobra.NoArgs,
-		RunE:    randomPost,
+func createPost(cmd *cobra.Command, args []string) error {
+	quick, hasQuick, err := quickText(cmd, args)
+	if err != nil {
+		return err
 	}
-)
 
-func createPost(cmd *cobra.Command, _ []string) error {
 	// Check if stdin has data (piped input)
-	stat, err := os.Stdin.Stat()
+	piped, err := isPiped(cmd.InOrStdin())
 	if err != nil {
 		return err
 	}
 
-	var text string
-	var imagePathsInput string
-	var audioPathsInput string
+	var fields createFields
+	capture := &attachmentCapture{cmd: cmd, fields: &fields}
+	defer capture.cleanup()
+	for _, f := range []struct {
+		flag   string
+		action createAction
+	}{{"record", createRecord}, {"paste-image", createPasteImage}, {"screenshot", createScreenshot}} {
+		if on, _ := cmd.Flags().GetBool(f.flag); on {
+			if err := capture.run(f.action); err != nil {
+				return err
+			}
+		}
+	}
 
-	if (stat.Mode() & os.ModeCharDevice) == 0 {
+	if name, _ := cmd.Flags().GetString("template"); name != "" {
+		rendered, err := renderTemplate(cmd.Context(), name, journal)
+		if err != nil {
+			return err
+		}
+		fields.text = rendered
+	}
+
+	maxDim, _ := cmd.Flags().GetInt("image-max-dimension")
+	quality, _ := cmd.Flags().GetInt("image-quality")
+
+	if hasQuick {
+		fields.text = applyTemplate(fields.text, quick)
+	} else if piped {
 		// stdin is a pipe or redirected input
-		content, err := io.ReadAll(os.Stdin)
+		content, err := io.ReadAll(cmd.InOrStdin())
 		if err != nil {
 			return fmt.Errorf("failed to read from stdin: %w", err)
 		}
-		text = string(content)
+		fields.text = applyTemplate(fields.text, string(content))
 	} else {
 		// stdin is a terminal, use interactive TUI (supports drag & drop of images)
-		form := huh.NewForm(
-			huh.NewGroup(
-				huh.NewText().
-					Value(&text).
-					Placeholder("Write your journal entry here...").
-					Validate(func(value string) error {
-						if len(strings.TrimSpace(value)) == 0 {
-							return fmt.Errorf("journal entry cannot be empty")
-						}
-						return nil
-					}).
-					WithHeight(12).
-					WithWidth(100),
-				huh.NewText().
-					Value(&imagePathsInput).
-					Title("Images").
-					Description("Drag & drop image files here, or paste paths (one per line). Leave empty for no images.").
-					Placeholder("/path/to/image.jpg").
-					WithHeight(3).
-					WithWidth(100),
-				huh.NewText().
-					Value(&audioPathsInput).
-					Title("Audio").
-					Description("Drag & drop audio files here, or paste paths (one per line). Leave empty for no audio.").
-					Placeholder("/path/to/recording.mp3").
-					WithHeight(3).
-					WithWidth(100),
-			),
-		)
-
-		if err := form.Run(); err != nil {
-			return err
+		for {
+			action, err := runCreateForm(client.WithImageOptions(cmd.Context(), maxDim, quality), &fields)
+			if err != nil {
+				return err
+			}
+			if action == createSubmit {
+				break
+			}
+			if action == createTemplate {
+				if err := insertTemplate(cmd, &fields); err != nil {
+					fmt.Fprintln(cmd.ErrOrStderr(), "Couldn't insert template:", err)
+				}
+				continue
+			}
+			if err := capture.run(action); err != nil && !errors.Is(err, errRecordingDiscarded) {
+				fmt.Fprintln(cmd.ErrOrStderr(), "Couldn't attach:", err)
+			}
 		}
 
-		text = strings.TrimSpace(text)
-		if text == "" {
+		fields.text = strings.TrimSpace(fields.text)
+		if fields.text == "" {
 			return fmt.Errorf("journal entry cannot be empty")
 		}
 	}
+	text := fields.text
 
 	imagePaths, err := cmd.Flags().GetStringSlice("image")
 	if err != nil {
@@ -174,40 +216,120 @@ func createPost(cmd *cobra.Command, _ []string) error {
 	if err != nil {
 		audioPaths = nil
 	}
-	imagePaths = append(imagePaths, parsePaths(imagePathsInput)...)
-	audioPaths = append(audioPaths, parsePaths(audioPathsInput)...)
+	formImages, formAudio := fields.formAttachments()
+	if !piped && !hasQuick {
+		// The form's review step lets the user uncheck attachments.
+		formImages, formAudio = keepSelected(formImages, fields.keep), keepSelected(formAudio, fields.keep)
+	}
+	imagePaths = append(expandPaths(imagePaths, "image"), formImages...)
+	audioPaths = append(expandPaths(audioPaths, "audio"), formAudio...)
 
-	// Save entry with spinner
-	var saveErr error
-	err = spinner.New().
-		Title("Saving entry...").
-		Action(func() {
-			saveErr = cfg.SaveEntry(cmd.Context(), text, imagePaths, audioPaths)
-		}).
-		Run()
+	ctx, text, err := addEntryContext(cmd, text)
+	if err != nil {
+		return err
+	}
+	ctx = client.WithImageOptions(ctx, maxDim, quality)
+	if tags, _ := cmd.Flags().GetStringSlice("tag"); len(tags) > 0 {
+		ctx = client.WithTags(ctx, tags...)
+	}
 
+	var created *client.Post
+	save := func(ctx context.Context) error {
+		var err error
+		created, err = journal.SaveEntry(ctx, text, imagePaths, audioPaths)
+		return err
+	}
+	printID, _ := cmd.Flags().GetBool("print-id")
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	attachments := append(append([]string(nil), imagePaths...), audioPaths...)
+	switch {
+	case quiet || printID:
+		err = save(ctx)
+	case len(attachments) > 0:
+		err = saveWithProgress(ctx, attachments, save)
+	default:
+		// Save entry with spinner
+		var saveErr error
+		err = spinner.New().
+			Title("Saving entry...").
+			Action(func() {
+				saveErr = save(ctx)
+			}).
+			Run()
+		if err == nil {
+			err = saveErr
+		}
+	}
 	if err != nil {
 		return err
 	}
 
-	return saveErr
+	if created == nil {
+		return nil
+	}
+	if wait, _ := cmd.Flags().GetBool("wait"); wait {
+		timeout, _ := cmd.Flags().GetDuration("wait-timeout")
+		latest, err := waitWithSpinner(ctx, journal, created, timeout, quiet || printID)
+		created = latest
+		if errors.Is(err, errProcessingTimeout) {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Some attachments still have no text after %s; they may have none, or may still be processing. Check later with etu show.\n", timeout)
+		} else if err != nil {
+			return err
+		}
+	}
+	switch {
+	case printID:
+		fmt.Fprintln(cmd.OutOrStdout(), created.PageID)
+	case !quiet:
+		fmt.Fprint(cmd.OutOrStdout(), formatCreated(created))
+	}
+	return nil
+}
+
+// quickText builds entry text from positional args, -m/--message paragraphs
+// and "-" (stdin). ok is false when none were given.
+func quickText(cmd *cobra.Command, args []string) (text string, ok bool, err error) {
+	messages, _ := cmd.Flags().GetStringArray("message")
+	var parts []string
+	switch {
+	case slices.Contains(args, "-"):
+		if len(args) > 1 {
+			return "", false, fmt.Errorf(`"-" reads the entry from stdin and can't be combined with other arguments`)
+		}
+		content, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read from stdin: %w", err)
+		}
+		parts = append(parts, strings.TrimRight(string(content), "\n"))
+	case len(args) > 0:
+		parts = append(parts, strings.Join(args, " "))
+	}
+	parts = append(parts, messages...)
+	if len(parts) == 0 {
+		return "", false, nil
+	}
+	text = strings.Join(parts, "\n\n")
+	if strings.TrimSpace(text) == "" {
+		return "", false, fmt.Errorf("journal entry cannot be empty")
+	}
+	return text, true, nil
 }
 
 func timeSinceLastPost(cmd *cobra.Command, _ []string) error {
-	dur, err := cfg.TimeSinceLastPost(cmd.Context())
+	dur, err := journal.TimeSinceLastPost(cmd.Context())
 	if err != nil {
 		// Intentional: print a "???" sentinel so shell prompts don't break.
-		fmt.Print("???")
+		fmt.Fprint(cmd.OutOrStdout(), "???")
 		return nil //nolint:nilerr // sentinel output is the desired behavior here
 	}
 
-	fmt.Print(formatDuration(dur))
+	fmt.Fprint(cmd.OutOrStdout(), formatDuration(dur))
 	return nil
 }
 
 func deletePost(cmd *cobra.Command, _ []string) error {
 	// Show list of posts to select from
-	model := newPostListModel(cfg, 25, "Select entry to delete", true)
+	model := newPostListModel(cmd.Context(), journal, 25, "Select entry to delete", true)
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	finalModel, err := p.Run()
 	if err != nil {
@@ -226,7 +348,7 @@ func deletePost(cmd *cobra.Command, _ []string) error {
 	confirmForm := huh.NewForm(
 		huh.NewGroup(
 			huh.NewConfirm().
-				Title(fmt.Sprintf("Delete entry from %s?", selectedPost.CreatedAt.Format("2006-01-02 15:04"))).
+				Title(fmt.Sprintf("Delete entry from %s?", formatTime(selectedPost.CreatedAt))).
 				Description(selectedPost.Text).
 				Value(&confirm),
 		),
@@ -245,19 +367,23 @@ func deletePost(cmd *cobra.Command, _ []string) error {
 	err = spinner.New().
 		Title("Deleting entry...").
 		Action(func() {
-			deleteErr = cfg.DeletePost(cmd.Context(), selectedPost.PageID)
+			deleteErr = journal.DeletePost(cmd.Context(), selectedPost.PageID)
 		}).
 		Run()
 
 	if err != nil {
 		return err
 	}
+	if deleteErr != nil {
+		return deleteErr
+	}
 
-	return deleteErr
+	fmt.Fprintln(cmd.OutOrStdout(), "Deleted. Run etu undo to bring it back.")
+	return nil
 }
 
 func mostRecentPost(cmd *cobra.Command, _ []string) error {
-	posts, err := cfg.ListPosts(cmd.Context(), 1)
+	posts, err := journal.ListPosts(cmd.Context(), 1)
 	if err != nil {
 		return err
 	}
@@ -265,12 +391,12 @@ func mostRecentPost(cmd *cobra.Command, _ []string) error {
 		return fmt.Errorf("no posts found")
 	}
 
-	if !isInteractive() {
-		printPostPlain(cmd.Context(), cfg, posts[0])
+	if !isInteractive(cmd.OutOrStdout()) {
+		printPostPlain(cmd.Context(), cmd.OutOrStdout(), journal, posts[0])
 		return nil
 	}
 
-	model := newPostListModel(cfg, 1, "Most Recent Entry", true)
+	model := newPostListModel(cmd.Context(), journal, 1, "Most Recent Entry", true)
 	if _, err := tea.NewProgram(model, tea.WithAltScreen()).Run(); err != nil {
 		return err
 	}
@@ -278,12 +404,14 @@ func mostRecentPost(cmd *cobra.Command, _ []string) error {
 }
 
 func listPosts(cmd *cobra.Command, _ []string) error {
-	model := newPostListModel(cfg, 25, "Interstitial Notes", true)
+	model := newPostListModel(cmd.Context(), journal, 25, "Interstitial Notes", true)
+	model.enableBulk()
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	finalModel, err := p.Run()
 	if err != nil {
 		return err
 	}
+	printBulkStatus(cmd, finalModel.(postListModel))
 
 	// If a post was selected, display it with media
 	if finalModel.(postListModel).selected != nil {
@@ -292,21 +420,12 @@ func listPosts(cmd *cobra.Command, _ []string) error {
 	return nil
 }
 
-func randomPost(cmd *cobra.Command, _ []string) error {
-	posts, err := cfg.GetRandomPosts(cmd.Context(), 1)
-	if err != nil {
-		return err
+// printBulkStatus repeats how the last bulk action went, since the list's
+// screen is gone once it exits.
+func printBulkStatus(cmd *cobra.Command, m postListModel) {
+	if m.status != "" {
+		fmt.Fprintln(cmd.OutOrStdout(), m.status)
 	}
-	if len(posts) == 0 {
-		return fmt.Errorf("no posts found")
-	}
-
-	if !isInteractive() {
-		printPostPlain(cmd.Context(), cfg, posts[0])
-		return nil
-	}
-
-	return displayPost(cmd, posts[0])
 }
 
 // parsePaths splits newline-separated file paths, trims whitespace and quotes,
@@ -341,20 +460,38 @@ func formatDuration(dur time.Duration) string {
 	return fmt.Sprintf("%0.1fh", dur.Hours())
 }
 
-// isInteractive reports whether stdout is a terminal.
-func isInteractive() bool {
-	stat, err := os.Stdout.Stat()
+// isInteractive reports whether out is a terminal.
+func isInteractive(out io.Writer) bool {
+	f, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	stat, err := f.Stat()
 	return err == nil && (stat.Mode()&os.ModeCharDevice) != 0
 }
 
+// isPiped reports whether in is a pipe, file or other non-terminal input.
+// Readers that aren't files (e.g. in tests) count as piped.
+func isPiped(in io.Reader) (bool, error) {
+	f, ok := in.(*os.File)
+	if !ok {
+		return true, nil
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+	return (stat.Mode() & os.ModeCharDevice) == 0, nil
+}
+
 // printPostPlain outputs the full content of a post for non-interactive use.
-func printPostPlain(ctx context.Context, c *client.Config, post *client.Post) {
-	full, err := c.GetPostFullContent(ctx, post.PageID)
+func printPostPlain(ctx context.Context, w io.Writer, j client.Journal, post *client.Post) {
+	full, err := j.GetPostFullContent(ctx, post.PageID)
 	if err == nil && strings.TrimSpace(full) != "" {
-		fmt.Print(full)
+		fmt.Fprint(w, full)
 		return
 	}
-	fmt.Print(post.Text)
+	fmt.Fprint(w, post.Text)
 }
 
 func init() {
@@ -370,29 +507,94 @@ func init() {
 
 	createCmd.Flags().StringSliceP("image", "i", nil, "path to image file to attach (can be repeated)")
 	createCmd.Flags().StringSliceP("audio", "a", nil, "path to audio file to attach (can be repeated)")
+	createCmd.Flags().Int("image-max-dimension", 0, "downscale attached images so the longer side is at most this many pixels")
+	createCmd.Flags().Int("image-quality", 0, "JPEG quality (1-100) for downscaled images (default 85)")
+	createCmd.Flags().Bool("record", false, "record a voice note from the microphone and attach it")
+	createCmd.Flags().Duration("record-limit", 10*time.Minute, "stop recording automatically after this long; WAV recordings stop sooner to fit the upload limit")
+	createCmd.Flags().String("recorder", "", "recorder to use: pw-record, arecord or ffmpeg (default: first found on PATH)")
+	createCmd.Flags().StringArrayP("message", "m", nil, "entry text; repeat for more paragraphs")
+	createCmd.Flags().StringSlice("tag", nil, "tag to add to the entry (can be repeated)")
+	createCmd.Flags().BoolP("quiet", "q", false, "don't show progress or the saved entry summary")
+	createCmd.Flags().Bool("print-id", false, "print the new entry's ID (implies --quiet)")
+	createCmd.Flags().Bool("wait", false, "wait until every attachment has OCR or transcription text")
+	createCmd.Flags().Duration("wait-timeout", 2*time.Minute, "how long --wait waits for text before giving up")
+	createCmd.Flags().StringP("template", "t", "", "start the entry from a template (see etu template list)")
+	_ = createCmd.RegisterFlagCompletionFunc("template", completeTemplateNames)
+	createCmd.Flags().StringSlice("context", nil, "context to add: git, cwd, tmux, hostname (default from config)")
+	createCmd.Flags().Bool("no-context", false, "don't add context, even if configured")
+	createCmd.Flags().Bool("paste-image", false, "attach the image on the clipboard (wl-paste, xclip or pngpaste)")
+	createCmd.Flags().Bool("screenshot", false, "take a screenshot with screenshot_command and attach it")
+	rootCmd.PersistentFlags().String("timezone", "", "show times in this IANA timezone, e.g. Europe/Berlin (default local)")
+	rootCmd.PersistentFlags().String("date-format", "", "date format: iso, us, eu, long, relative or a Go layout (default iso)")
+	rootCmd.PersistentFlags().String("clock", "", "clock: 12h, 24h or auto (from the locale)")
+	calendarCmd.Flags().String("month", "", "month to show, as YYYY-MM (default this month)")
+	timelineCmd.Flags().IntP("limit", "n", timelinePageSize, "how many recent entries to load at first")
+	reviewCmd.Flags().Bool("on-this-day", false, "show entries from today's date in past years")
+	reviewCmd.Flags().BoolP("guided", "g", false, "step through the entries and write follow-up notes")
+	reviewCmd.Flags().Bool("previous", false, "review last week or month instead of the current one")
+	digestCmd.Flags().String("period", "week", "period to cover: day, week or month")
+	digestCmd.Flags().Bool("previous", false, "cover the previous day, week or month instead of the current one")
+	digestCmd.Flags().StringP("format", "f", "markdown", "report format: markdown or html")
+	digestCmd.Flags().StringP("output", "o", "", "write the report to this file instead of stdout")
+	digestCmd.Flags().String("template", "", "render with this Go template file instead of the default")
+	digestCmd.Flags().Bool("default-template", false, "print the built-in template for --format and exit")
+	focusCmd.Flags().Bool("today", false, "show today's focus sessions instead of starting one")
+	linksCmd.Flags().Int("depth", 1, "how many links out to follow")
+	linksCmd.Flags().Bool("refresh", false, "rebuild the link index from every entry first")
+	randomCmd.Flags().IntP("count", "n", 1, "how many entries to show")
+	randomCmd.Flags().StringSlice("tag", nil, "only pick entries with this tag (can be repeated)")
+	randomCmd.Flags().String("since", "", `only pick entries from this time on, e.g. "2025-01-01", "monday" or "30d"`)
+	randomCmd.Flags().String("until", "", `only pick entries up to this day or time`)
+	randomCmd.Flags().String("weight", "uniform", "favor entries: uniform, older, or unseen (shown least by etu random)")
+	randomCmd.Flags().BoolP("shuffle", "s", false, "browse random entries, pressing space for another")
+	todoCmd.Flags().StringSlice("tag", nil, "only scan entries with this tag (can be repeated)")
+	todoCmd.Flags().String("since", "", `only scan entries from this time on, e.g. "monday" or "2w"`)
+	todoCmd.Flags().String("until", "", "only scan entries up to this day or time")
+	todoCmd.Flags().BoolP("all", "a", false, "include items already done")
+	timesheetCmd.Flags().String("since", "monday", `count entries from this time on, e.g. "monday", "2w" or "2026-03-01"`)
+	timesheetCmd.Flags().String("until", "", "count entries up to this day or time")
+	timesheetCmd.Flags().String("by", "tag", "name activities by an entry's first tag or first word: tag or word")
+	timesheetCmd.Flags().Duration("idle", 2*time.Hour, "gaps longer than this count as time away")
+	timesheetCmd.Flags().StringP("format", "f", "table", "output format: table, csv or json")
+	undoCmd.Flags().Bool("list", false, "list the changes that can be undone")
+	undoCmd.Flags().BoolP("yes", "y", false, "undo without asking for confirmation")
+	undoCmd.Flags().Bool("force", false, "undo even if the entry has changed since")
 	statsCmd.Flags().Bool("global", false, "also show community-wide stats")
 
 	rootCmd.AddCommand(
+		calendarCmd,
 		createCmd,
 		deleteCmd,
+		digestCmd,
 		editCmd,
+		focusCmd,
+		historyCmd,
+		linksCmd,
 		listCmd,
 		mostRecentCmd,
 		randomCmd,
+		reviewCmd,
 		showCmd,
 		statsCmd,
 		tagsCmd,
+		templateCmd,
+		timelineCmd,
+		timesheetCmd,
 		timeSinceCmd,
+		todoCmd,
+		undoCmd,
 		searchCmd,
 	)
 }
 
 func main() {
-	// Load config (file + ETU_API_KEY / ETU_GRPC_TARGET env) and persist so we don't have to mess with env later.
+	// Load config (file + ETU_* env) and persist the API key and target so we don't have to mess with env later.
 	cfg = client.LoadConfig()
 	if _, err := client.SaveConfig(cfg.APIKey, cfg.GRPCTarget); err != nil {
 		log.Fatal(err)
 	}
+	history = client.NewHistory(client.NewLinkIndexer(cfg))
+	journal = history
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,363 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/icco/etu/client"
+	"github.com/spf13/cobra"
+)
+
+var randomCmd = &cobra.Command{
+	Use:     "random",
+	Aliases: []string{"r"},
+	Short:   "Show a random journal entry.",
+	Long: `Show a random journal entry, or -n of them.
+
+--tag, --since and --until limit the entries to pick from, and --weight
+favors older entries or ones etu random has shown less often. --shuffle
+opens a viewer where space shows another.`,
+	Example: `  etu random -n 3
+  etu random --tag travel --since 2025-01-01
+  etu random --weight unseen --shuffle`,
+	Args: cobra.NoArgs,
+	RunE: randomPost,
+}
+
+// randomWeights are the ways --weight can favor entries.
+var randomWeights = []string{"uniform", "older", "unseen"}
+
+// maxRandomExtra caps how many extra entries pick asks the backend for.
+const maxRandomExtra = 20
+
+// randFloat returns a number in [0, 1) for weighted picks; tests replace it.
+var randFloat = rand.Float64
+
+// randomFilter is what etu random picks from.
+type randomFilter struct {
+	tags     []string
+	from, to time.Time
+	weight   string
+}
+
+// local reports whether entries have to be picked client-side, since the
+// backend's random endpoint can't filter or weight.
+func (f randomFilter) local() bool {
+	return len(f.tags) > 0 || !f.from.IsZero() || !f.to.IsZero() || f.weight != "uniform"
+}
+
+// randomPicker draws random entries from the backend, or from the matching
+// entries when the filter needs it, loading them on first use.
+type randomPicker struct {
+	journal client.Journal
+	filter  randomFilter
+	now     time.Time
+	views   client.ViewCounts
+
+	pool   []*client.Post
+	loaded bool
+}
+
+func newRandomPicker(j client.Journal, f randomFilter, now time.Time, views client.ViewCounts) *randomPicker {
+	return &randomPicker{journal: j, filter: f, now: now, views: views}
+}
+
+// pick returns up to n random entries, avoiding those in skip unless
+// nothing else is left.
+func (p *randomPicker) pick(ctx context.Context, n int, skip map[string]bool) ([]*client.Post, error) {
+	if !p.filter.local() {
+		// Ask for a few extra to make up for ones already shown.
+		posts, err := p.journal.GetRandomPosts(ctx, n+min(len(skip), maxRandomExtra))
+		if err != nil {
+			return nil, err
+		}
+		fresh := slices.DeleteFunc(slices.Clone(posts), func(post *client.Post) bool { return skip[post.PageID] })
+		if len(fresh) == 0 {
+			fresh = posts
+		}
+		return fresh[:min(n, len(fresh))], nil
+	}
+	if !p.loaded {
+		posts, _, err := client.ListPostsBetween(ctx, p.journal, p.filter.from, p.filter.to)
+		if err != nil {
+			return nil, err
+		}
+		p.pool = slices.DeleteFunc(posts, func(post *client.Post) bool { return !hasAnyTag(post, p.filter.tags) })
+		p.loaded = true
+	}
+	candidates := slices.DeleteFunc(slices.Clone(p.pool), func(post *client.Post) bool { return skip[post.PageID] })
+	if len(candidates) == 0 {
+		candidates = p.pool
+	}
+	return weightedSample(candidates, n, p.weight), nil
+}
+
+// weight is how likely post is to be picked relative to the others.
+func (p *randomPicker) weight(post *client.Post) float64 {
+	switch p.filter.weight {
+	case "older":
+		return 1 + max(p.now.Sub(post.CreatedAt).Hours()/24, 0)
+	case "unseen":
+		return 1 / float64(1+p.views[post.PageID])
+	}
+	return 1
+}
+
+// weightedSample picks up to n posts without replacement, each with
+// probability proportional to its weight (Efraimidis–Spirakis).
+func weightedSample(posts []*client.Post, n int, weight func(*client.Post) float64) []*client.Post {
+	type keyed struct {
+		post *client.Post
+		key  float64
+	}
+	keys := make([]keyed, len(posts))
+	for i, p := range posts {
+		keys[i] = keyed{p, math.Pow(randFloat(), 1/weight(p))}
+	}
+	sort.SliceStable(keys, func(a, b int) bool { return keys[a].key > keys[b].key })
+	out := make([]*client.Post, 0, min(n, len(keys)))
+	for _, k := range keys[:min(n, len(keys))] {
+		out = append(out, k.post)
+	}
+	return out
+}
+
+// hasAnyTag reports whether post has one of tags, ignoring case; no tags
+// matches everything.
+func hasAnyTag(post *client.Post, tags []string) bool {
+	if len(tags) == 0 {
+		return true
+	}
+	for _, t := range post.Tags {
+		if slices.ContainsFunc(tags, func(want string) bool { return strings.EqualFold(t, want) }) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordViews counts posts as shown for --weight unseen.
+func recordViews(views client.ViewCounts, posts ...*client.Post) {
+	if views == nil {
+		return
+	}
+	for _, p := range posts {
+		views[p.PageID]++
+	}
+	if err := client.SaveViewCounts(views); err != nil {
+		log.Printf("etu: writing view counts: %v", err)
+	}
+}
+
+func randomPost(cmd *cobra.Command, _ []string) error {
+	n, _ := cmd.Flags().GetInt("count")
+	if n < 1 {
+		return fmt.Errorf("-n must be at least 1")
+	}
+	weight, _ := cmd.Flags().GetString("weight")
+	if !slices.Contains(randomWeights, weight) {
+		return fmt.Errorf("unknown weight %q: use %s", weight, strings.Join(randomWeights, ", "))
+	}
+	now := time.Now().In(displayTime.loc)
+	from, to, err := rangeFlags(cmd, now)
+	if err != nil {
+		return err
+	}
+	tags, _ := cmd.Flags().GetStringSlice("tag")
+	filter := randomFilter{tags: tags, from: from, to: to, weight: weight}
+
+	views, err := client.LoadViewCounts()
+	if err != nil {
+		log.Printf("etu: reading view counts: %v", err)
+		views = client.ViewCounts{}
+	}
+	picker := newRandomPicker(journal, filter, now, views)
+
+	if shuffle, _ := cmd.Flags().GetBool("shuffle"); shuffle {
+		if !isInteractive(cmd.OutOrStdout()) {
+			return fmt.Errorf("--shuffle needs a terminal")
+		}
+		final, err := tea.NewProgram(newShuffleModel(cmd.Context(), picker), tea.WithAltScreen()).Run()
+		if err != nil {
+			return err
+		}
+		m := final.(shuffleModel)
+		recordViews(views, m.history...)
+		if m.err != nil {
+			return m.err
+		}
+		if m.open == nil {
+			return nil
+		}
+		return displayPost(cmd, m.open)
+	}
+
+	posts, err := picker.pick(cmd.Context(), n, nil)
+	if err != nil {
+		return err
+	}
+	if len(posts) == 0 {
+		if filter.local() {
+			return fmt.Errorf("no posts match")
+		}
+		return fmt.Errorf("no posts found")
+	}
+	recordViews(views, posts...)
+
+	for i, p := range posts {
+		if !isInteractive(cmd.OutOrStdout()) {
+			if i > 0 {
+				fmt.Fprint(cmd.OutOrStdout(), "\n\n---\n\n")
+			}
+			printPostPlain(cmd.Context(), cmd.OutOrStdout(), journal, p)
+			continue
+		}
+		if err := displayPost(cmd, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type randomPickedMsg struct {
+	post *client.Post
+	err  error
+}
+
+// pickAnother draws one entry, avoiding those already shown.
+func pickAnother(ctx context.Context, picker *randomPicker, shown []*client.Post) tea.Cmd {
+	skip := make(map[string]bool, len(shown))
+	for _, p := range shown {
+		skip[p.PageID] = true
+	}
+	return func() tea.Msg {
+		posts, err := picker.pick(ctx, 1, skip)
+		if err == nil && len(posts) == 0 {
+			err = fmt.Errorf("no posts match")
+		}
+		if err != nil {
+			return randomPickedMsg{err: err}
+		}
+		return randomPickedMsg{post: posts[0]}
+	}
+}
+
+// shuffleModel shows one random entry at a time; space draws another and
+// the ones already shown can be stepped back through.
+type shuffleModel struct {
+	ctx     context.Context
+	picker  *randomPicker
+	history []*client.Post
+	index   int
+	loading bool
+	status  string
+	width   int
+
+	open     *client.Post
+	err      error
+	quitting bool
+}
+
+func newShuffleModel(ctx context.Context, picker *randomPicker) shuffleModel {
+	return shuffleModel{ctx: ctx, picker: picker, loading: true, width: 80}
+}
+
+func (m shuffleModel) Init() tea.Cmd {
+	return pickAnother(m.ctx, m.picker, nil)
+}
+
+func (m shuffleModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		return m, nil
+
+	case randomPickedMsg:
+		m.loading = false
+		if msg.err != nil {
+			if len(m.history) == 0 {
+				m.err = msg.err
+				m.quitting = true
+				return m, tea.Quit
+			}
+			m.status = "Couldn't get another: " + msg.err.Error()
+			return m, nil
+		}
+		m.history = append(m.history, msg.post)
+		m.index = len(m.history) - 1
+		return m, nil
+
+	case tea.KeyMsg:
+		m.status = ""
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			m.quitting = true
+			return m, tea.Quit
+		case " ", "right", "l", "n":
+			if m.index < len(m.history)-1 {
+				m.index++
+				return m, nil
+			}
+			if m.loading {
+				return m, nil
+			}
+			m.loading = true
+			return m, pickAnother(m.ctx, m.picker, m.history)
+		case "left", "h", "p":
+			m.index = max(m.index-1, 0)
+		case "enter":
+			if len(m.history) > 0 {
+				m.open = m.history[m.index]
+				m.quitting = true
+				return m, tea.Quit
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m shuffleModel) View() string {
+	if m.quitting {
+		return ""
+	}
+	var s strings.Builder
+	s.WriteString(timelineHeaderStyle.Render("Random entries"))
+	if len(m.history) == 0 {
+		s.WriteString("\n\nPicking an entry...\n")
+		return docStyle.Render(s.String())
+	}
+	p := m.history[m.index]
+	s.WriteString(timelineGapStyle.Render(fmt.Sprintf("  %d of %d shown", m.index+1, len(m.history))))
+	s.WriteString("\n\n")
+	s.WriteString(lipgloss.NewStyle().Bold(true).Render(formatTime(p.CreatedAt)))
+	if len(p.Tags) > 0 {
+		s.WriteString(timelineGapStyle.Render("  [" + strings.Join(p.Tags, ", ") + "]"))
+	}
+	s.WriteString("\n\n")
+	s.WriteString(lipgloss.NewStyle().Width(min(m.width-6, 100)).Render(strings.TrimSpace(p.Text)))
+	if len(p.Images) > 0 || len(p.Audios) > 0 {
+		s.WriteString("\n\n")
+		s.WriteString(timelineGapStyle.Render(fmt.Sprintf("%d %s, %d %s · enter to view",
+			len(p.Images), plural(len(p.Images), "image", "images"),
+			len(p.Audios), plural(len(p.Audios), "audio clip", "audio clips"))))
+	}
+	s.WriteString("\n\n")
+	if m.loading {
+		s.WriteString(timelineHelpStyle.Render("Picking another..."))
+	} else {
+		s.WriteString(timelineHelpStyle.Render("space another · ←/→ back/forward · enter open · q quit"))
+	}
+	if m.status != "" {
+		s.WriteString("\n" + m.status)
+	}
+	return docStyle.Render(s.String())
+}
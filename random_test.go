@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/icco/etu/client"
+	"github.com/icco/etu/client/fake"
+)
+
+func TestWeightedSample(t *testing.T) {
+	rolls := []float64{0.5, 0.5, 0.5}
+	old := randFloat
+	t.Cleanup(func() { randFloat = old })
+	randFloat = func() float64 {
+		r := rolls[0]
+		rolls = rolls[1:]
+		return r
+	}
+	posts := []*client.Post{{PageID: "a"}, {PageID: "b"}, {PageID: "c"}}
+	weights := map[string]float64{"a": 1, "b": 10, "c": 3}
+	got := weightedSample(posts, 2, func(p *client.Post) float64 { return weights[p.PageID] })
+	if len(got) != 2 || got[0].PageID != "b" || got[1].PageID != "c" {
+		t.Errorf("weightedSample = %v, want b then c for equal rolls", got)
+	}
+}
+
+func TestRandomPicker(t *testing.T) {
+	now := time.Date(2026, 3, 12, 15, 0, 0, 0, time.UTC)
+	j := fake.NewJournal(
+		&client.Post{PageID: "old", Tags: []string{"Travel"}, CreatedAt: now.AddDate(-2, 0, 0)},
+		&client.Post{PageID: "new", Tags: []string{"travel"}, CreatedAt: now.AddDate(0, 0, -1)},
+		&client.Post{PageID: "work", Tags: []string{"work"}, CreatedAt: now.AddDate(0, 0, -2)},
+	)
+	ctx := context.Background()
+
+	p := newRandomPicker(j, randomFilter{tags: []string{"travel"}, weight: "uniform"}, now, nil)
+	got, err := p.pick(ctx, 5, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].PageID == "work" || got[1].PageID == "work" {
+		t.Errorf("pick with --tag travel = %v, want old and new", got)
+	}
+	if got, _ := p.pick(ctx, 1, map[string]bool{"old": true}); len(got) != 1 || got[0].PageID != "new" {
+		t.Errorf("pick skipping old = %v, want new", got)
+	}
+	if got, _ := p.pick(ctx, 1, map[string]bool{"old": true, "new": true}); len(got) != 1 {
+		t.Errorf("pick with everything skipped = %v, want one again", got)
+	}
+
+	p = newRandomPicker(j, randomFilter{from: now.AddDate(0, 0, -3), weight: "uniform"}, now, nil)
+	if got, _ := p.pick(ctx, 5, nil); len(got) != 2 {
+		t.Errorf("pick with --since = %v, want the two recent entries", got)
+	}
+
+	p = newRandomPicker(j, randomFilter{weight: "older"}, now, nil)
+	if w := p.weight(&client.Post{CreatedAt: now.AddDate(0, 0, -9)}); w != 10 {
+		t.Errorf("older weight of a 9-day-old entry = %v, want 10", w)
+	}
+	p = newRandomPicker(j, randomFilter{weight: "unseen"}, now, client.ViewCounts{"old": 3})
+	if w := p.weight(&client.Post{PageID: "old"}); w != 0.25 {
+		t.Errorf("unseen weight after 3 views = %v, want 0.25", w)
+	}
+}
+
+func TestCommandRandomFiltered(t *testing.T) {
+	srv := startFakeBackend(t)
+	now := time.Now()
+	srv.AddNote("tagged one", now.Add(-time.Hour), "travel")
+	srv.AddNote("tagged two", now.Add(-2*time.Hour), "travel")
+	srv.AddNote("untagged", now.Add(-3*time.Hour))
+
+	out, err := runCLI(t, "", "random", "-n", "5", "--tag", "travel", "--weight", "older")
+	if err != nil {
+		t.Fatalf("random: %v", err)
+	}
+	if !strings.Contains(out, "tagged one") || !strings.Contains(out, "tagged two") || !strings.Contains(out, "\n---\n") || strings.Contains(out, "untagged") {
+		t.Errorf("output:\n%s", out)
+	}
+	views, err := client.LoadViewCounts()
+	if err != nil || len(views) != 2 {
+		t.Errorf("views = %v, %v; want the two shown entries counted", views, err)
+	}
+
+	if _, err := runCLI(t, "", "random", "--tag", "nope"); err == nil || !strings.Contains(err.Error(), "no posts match") {
+		t.Errorf("random --tag nope: err = %v, want no posts match", err)
+	}
+	if _, err := runCLI(t, "", "random", "--weight", "heavy"); err == nil {
+		t.Error("random --weight heavy: want error")
+	}
+	if _, err := runCLI(t, "", "random", "--since", "today", "--until", "yesterday"); err == nil {
+		t.Error("random with --since after --until: want error")
+	}
+}
+
+func TestShuffleModel(t *testing.T) {
+	now := time.Now()
+	j := fake.NewJournal(
+		&client.Post{PageID: "a", Text: "first", CreatedAt: now.Add(-time.Hour)},
+		&client.Post{PageID: "b", Text: "second", CreatedAt: now},
+	)
+	m := newShuffleModel(context.Background(), newRandomPicker(j, randomFilter{weight: "uniform"}, now, nil))
+	updated, _ := m.Update(m.Init()())
+	m = updated.(shuffleModel)
+	if len(m.history) != 1 || !strings.Contains(m.View(), m.history[0].Text) {
+		t.Fatalf("after the first pick: history %v, view:\n%s", m.history, m.View())
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	if cmd == nil {
+		t.Fatal("space should pick another")
+	}
+	updated, _ = updated.Update(cmd())
+	m = updated.(shuffleModel)
+	if len(m.history) != 2 || m.history[0].PageID == m.history[1].PageID || m.index != 1 {
+		t.Errorf("after space: history %v, index %d; want two different entries", m.history, m.index)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(shuffleModel)
+	if m.open == nil || m.open.PageID != m.history[0].PageID {
+		t.Errorf("enter after left opened %v, want the first entry shown", m.open)
+	}
+}
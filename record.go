@@ -0,0 +1,437 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/icco/etu/client"
+)
+
+// Recordings are captured as 16 kHz mono 16-bit PCM: plenty for speech and
+// what transcription backends expect.
+const (
+	recordSampleRate    = 16000
+	recordChannels      = 1
+	recordBytesPerFrame = 2 * recordChannels
+	// recordChunk is how much audio is read between level meter updates (100ms).
+	recordChunk = recordSampleRate * recordBytesPerFrame / 10
+	// recordFloorDB is the quietest level shown on the meter.
+	recordFloorDB = -60.0
+	// wavHeaderSize is the length of the header writeWAV puts before the PCM.
+	wavHeaderSize = 44
+)
+
+var errRecordingDiscarded = errors.New("recording discarded")
+
+// audioSource streams raw signed 16-bit little-endian PCM at recordSampleRate.
+// The stream ends with io.EOF once ctx is cancelled; Close releases the source.
+type audioSource interface {
+	Name() string
+	Start(ctx context.Context) (io.ReadCloser, error)
+}
+
+// Seams for tests: where recorders come from and how binaries are found.
+var (
+	newAudioSource = detectAudioSource
+	lookPath       = exec.LookPath
+)
+
+// recorderCommands lists supported recorders in order of preference, each
+// writing PCM to stdout.
+func recorderCommands() [][]string {
+	rate := fmt.Sprint(recordSampleRate)
+	channels := fmt.Sprint(recordChannels)
+	cmds := [][]string{
+		{"pw-record", "--format", "s16", "--rate", rate, "--channels", channels, "-"},
+		{"arecord", "-q", "-t", "raw", "-f", "S16_LE", "-r", rate, "-c", channels, "-"},
+	}
+	ffmpeg := []string{"ffmpeg", "-hide_banner", "-loglevel", "error"}
+	switch runtime.GOOS {
+	case "darwin":
+		ffmpeg = append(ffmpeg, "-f", "avfoundation", "-i", ":default")
+	default:
+		ffmpeg = append(ffmpeg, "-f", "pulse", "-i", "default")
+	}
+	ffmpeg = append(ffmpeg, "-ac", channels, "-ar", rate, "-f", "s16le", "-")
+	return append(cmds, ffmpeg)
+}
+
+// detectAudioSource returns the named recorder, or the first one found on
+// PATH when name is empty.
+func detectAudioSource(name string) (audioSource, error) {
+	var names []string
+	for _, args := range recorderCommands() {
+		names = append(names, args[0])
+		if name != "" && args[0] != name {
+			continue
+		}
+		if path, err := lookPath(args[0]); err == nil {
+			return execSource{path: path, args: args}, nil
+		}
+		if name != "" {
+			return nil, fmt.Errorf("recorder %s not found on PATH", name)
+		}
+	}
+	if name != "" {
+		return nil, fmt.Errorf("unknown recorder %q (supported: %s)", name, strings.Join(names, ", "))
+	}
+	return nil, fmt.Errorf("no audio recorder found; install one of %s", strings.Join(names, ", "))
+}
+
+// execSource records by running an external program.
+type execSource struct {
+	path string
+	args []string
+}
+
+func (s execSource) Name() string { return s.args[0] }
+
+func (s execSource) Start(ctx context.Context) (io.ReadCloser, error) {
+	cmd := exec.CommandContext(ctx, s.path, s.args[1:]...) //nolint:gosec // G204: fixed recorder arguments
+	// Interrupt rather than kill so the recorder flushes its buffers.
+	cmd.Cancel = func() error { return cmd.Process.Signal(os.Interrupt) }
+	cmd.WaitDelay = 2 * time.Second
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start %s: %w", s.Name(), err)
+	}
+	return &execStream{ReadCloser: out, ctx: ctx, cmd: cmd, stderr: &stderr}, nil
+}
+
+type execStream struct {
+	io.ReadCloser
+	ctx    context.Context
+	cmd    *exec.Cmd
+	stderr *bytes.Buffer
+}
+
+// Close waits for the recorder to exit. Exiting because it was stopped is not an error.
+func (s *execStream) Close() error {
+	err := s.cmd.Wait()
+	if err == nil || s.ctx.Err() != nil {
+		return nil
+	}
+	if msg := strings.TrimSpace(s.stderr.String()); msg != "" {
+		return fmt.Errorf("%s: %w: %s", filepath.Base(s.cmd.Path), err, msg)
+	}
+	return fmt.Errorf("%s: %w", filepath.Base(s.cmd.Path), err)
+}
+
+// pcmDuration returns how much audio n bytes of PCM hold.
+func pcmDuration(n int) time.Duration {
+	return time.Duration(n/recordBytesPerFrame) * time.Second / recordSampleRate
+}
+
+// pcmLevel maps the RMS loudness of a PCM chunk onto 0..1 for the meter.
+func pcmLevel(chunk []byte) float64 {
+	n := len(chunk) / 2
+	if n == 0 {
+		return 0
+	}
+	var sum float64
+	for i := range n {
+		s := float64(int16(binary.LittleEndian.Uint16(chunk[2*i:]))) / math.MaxInt16
+		sum += s * s
+	}
+	rms := math.Sqrt(sum / float64(n))
+	if rms == 0 {
+		return 0
+	}
+	db := 20 * math.Log10(rms)
+	return min(max((db-recordFloorDB)/-recordFloorDB, 0), 1)
+}
+
+// captureAudio reads from src until ctx is cancelled, the stream ends or
+// limit is reached, calling onLevel after each chunk. It returns the raw PCM.
+func captureAudio(ctx context.Context, src audioSource, limit time.Duration, onLevel func(level float64, elapsed time.Duration)) ([]byte, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := src.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pcm bytes.Buffer
+	chunk := make([]byte, recordChunk)
+	for {
+		n, readErr := io.ReadFull(stream, chunk)
+		n -= n % recordBytesPerFrame
+		pcm.Write(chunk[:n])
+		if n > 0 && onLevel != nil {
+			onLevel(pcmLevel(chunk[:n]), pcmDuration(pcm.Len()))
+		}
+		if limit > 0 && pcmDuration(pcm.Len()) >= limit {
+			cancel()
+		}
+		if readErr != nil {
+			if !errors.Is(readErr, io.EOF) && !errors.Is(readErr, io.ErrUnexpectedEOF) && ctx.Err() == nil {
+				_ = stream.Close()
+				return nil, fmt.Errorf("read from %s: %w", src.Name(), readErr)
+			}
+			break
+		}
+	}
+	if err := stream.Close(); err != nil {
+		return nil, err
+	}
+	if pcm.Len() == 0 {
+		return nil, fmt.Errorf("no audio captured from %s; check the microphone", src.Name())
+	}
+	return pcm.Bytes(), nil
+}
+
+// writeWAV writes pcm as a canonical 16-bit PCM WAV file.
+func writeWAV(w io.Writer, pcm []byte) error {
+	header := struct {
+		RIFF          [4]byte
+		ChunkSize     uint32
+		WAVE          [4]byte
+		Fmt           [4]byte
+		FmtSize       uint32
+		Format        uint16
+		Channels      uint16
+		SampleRate    uint32
+		ByteRate      uint32
+		BlockAlign    uint16
+		BitsPerSample uint16
+		Data          [4]byte
+		DataSize      uint32
+	}{
+		RIFF:          [4]byte{'R', 'I', 'F', 'F'},
+		ChunkSize:     uint32(36 + len(pcm)), //nolint:gosec // G115: recordings are capped well below 4 GiB
+		WAVE:          [4]byte{'W', 'A', 'V', 'E'},
+		Fmt:           [4]byte{'f', 'm', 't', ' '},
+		FmtSize:       16,
+		Format:        1,
+		Channels:      recordChannels,
+		SampleRate:    recordSampleRate,
+		ByteRate:      recordSampleRate * recordBytesPerFrame,
+		BlockAlign:    recordBytesPerFrame,
+		BitsPerSample: 16,
+		Data:          [4]byte{'d', 'a', 't', 'a'},
+		DataSize:      uint32(len(pcm)), //nolint:gosec // G115: recordings are capped well below 4 GiB
+	}
+	if err := binary.Write(w, binary.LittleEndian, header); err != nil {
+		return err
+	}
+	_, err := w.Write(pcm)
+	return err
+}
+
+// canEncodeOpus reports whether ffmpeg is installed with the libopus encoder
+// encodeRecording needs to compress recordings.
+func canEncodeOpus(ctx context.Context) bool {
+	if _, err := lookPath("ffmpeg"); err != nil {
+		return false
+	}
+	out, err := runCommand(ctx, "ffmpeg", "-hide_banner", "-encoders")
+	return err == nil && bytes.Contains(out, []byte("libopus"))
+}
+
+// wavLimit returns how long a WAV recording can run and still fit in
+// maxBytes.
+func wavLimit(maxBytes int64) time.Duration {
+	return pcmDuration(int(max(maxBytes-wavHeaderSize, 0)))
+}
+
+// encodeRecording saves pcm in dir, as Opus when ffmpeg is available (roughly
+// a tenth of the size) and as WAV otherwise. It returns the file's path.
+func encodeRecording(ctx context.Context, pcm []byte, dir string) (string, error) {
+	name := "voice-note-" + time.Now().Format("20060102-150405")
+	wavPath := filepath.Join(dir, name+".wav")
+	var buf bytes.Buffer
+	if err := writeWAV(&buf, pcm); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(wavPath, buf.Bytes(), 0600); err != nil {
+		return "", fmt.Errorf("save recording: %w", err)
+	}
+
+	ffmpeg, err := lookPath("ffmpeg")
+	if err != nil {
+		return wavPath, nil
+	}
+	oggPath := filepath.Join(dir, name+".ogg")
+	cmd := exec.CommandContext(ctx, ffmpeg, "-hide_banner", "-loglevel", "error", "-y", //nolint:gosec // G204: fixed encoder arguments
+		"-i", wavPath, "-c:a", "libopus", "-b:a", "24k", "-application", "voip", oggPath)
+	if err := cmd.Run(); err != nil {
+		// Opus support is optional in ffmpeg builds; WAV still works.
+		_ = os.Remove(oggPath)
+		return wavPath, nil
+	}
+	_ = os.Remove(wavPath)
+	return oggPath, nil
+}
+
+type recordLevelMsg struct {
+	level   float64
+	elapsed time.Duration
+}
+
+type recordDoneMsg struct {
+	pcm []byte
+	err error
+}
+
+// recordModel shows a timer and level meter while audio is captured.
+type recordModel struct {
+	source    string
+	limit     time.Duration
+	note      string // why the recording will stop early, if it will
+	stop      context.CancelFunc
+	bar       progress.Model
+	level     float64
+	elapsed   time.Duration
+	stopping  bool
+	discarded bool
+	pcm       []byte
+	err       error
+	done      bool
+}
+
+func newRecordModel(source string, limit time.Duration, note string, stop context.CancelFunc) recordModel {
+	return recordModel{
+		source: source,
+		limit:  limit,
+		note:   note,
+		stop:   stop,
+		bar:    progress.New(progress.WithGradient("#5A56E0", "#EE6FF8"), progress.WithWidth(30), progress.WithoutPercentage()),
+	}
+}
+
+func (m recordModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m recordModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "enter", " ", "s":
+			m.stopping = true
+			m.stop()
+		case "esc", "q", "ctrl+c":
+			m.stopping = true
+			m.discarded = true
+			m.stop()
+		}
+		return m, nil
+	case recordLevelMsg:
+		m.level = msg.level
+		m.elapsed = msg.elapsed
+		return m, nil
+	case recordDoneMsg:
+		m.pcm = msg.pcm
+		m.err = msg.err
+		m.done = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m recordModel) View() string {
+	if m.done {
+		return ""
+	}
+	recStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true)
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+
+	status := recStyle.Render("● REC")
+	if m.stopping {
+		status = labelStyle.Render("■ Stopping...")
+	}
+	timer := formatClock(m.elapsed)
+	if m.limit > 0 {
+		timer += " / " + formatClock(m.limit)
+	}
+	view := fmt.Sprintf("%s  %s  %s  %s\n", status, timer, m.bar.ViewAs(m.level), labelStyle.Render("via "+m.source))
+	if m.note != "" {
+		view += lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render(m.note) + "\n"
+	}
+	return view + labelStyle.Render("enter: stop and attach • esc: discard") + "\n"
+}
+
+// formatClock renders d as m:ss.
+func formatClock(d time.Duration) string {
+	s := int(d.Round(time.Second) / time.Second)
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+// recordVoiceNote captures a voice note and saves it under dir, returning its
+// path. With a terminal it shows a meter and stops on enter; otherwise it
+// records until interrupted or limit is reached. Without Opus, the recording
+// also stops before its WAV would outgrow maxBytes.
+func recordVoiceNote(ctx context.Context, recorder string, limit time.Duration, maxBytes int64, dir string) (string, error) {
+	src, err := newAudioSource(recorder)
+	if err != nil {
+		return "", err
+	}
+
+	var note string
+	if maxBytes > 0 && !canEncodeOpus(ctx) {
+		if wavMax := wavLimit(maxBytes); limit <= 0 || wavMax < limit {
+			limit = wavMax
+			note = fmt.Sprintf("No Opus encoder in ffmpeg, so this is saved as WAV and stops at %s to fit the %s upload limit.", formatClock(limit), client.FormatBytes(maxBytes))
+		}
+	}
+
+	var pcm []byte
+	if isInteractive(os.Stderr) {
+		pcm, err = recordWithMeter(ctx, src, limit, note)
+	} else {
+		if note != "" {
+			fmt.Fprintln(os.Stderr, note)
+		}
+		fmt.Fprintf(os.Stderr, "Recording via %s; press Ctrl+C to stop.\n", src.Name())
+		sigCtx, stop := signal.NotifyContext(ctx, os.Interrupt)
+		pcm, err = captureAudio(sigCtx, src, limit, nil)
+		stop()
+	}
+	if err != nil {
+		return "", err
+	}
+	return encodeRecording(ctx, pcm, dir)
+}
+
+func recordWithMeter(ctx context.Context, src audioSource, limit time.Duration, note string) ([]byte, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Read keys from the terminal so recording works even when stdin is piped.
+	p := tea.NewProgram(newRecordModel(src.Name(), limit, note, cancel), tea.WithOutput(os.Stderr), tea.WithInputTTY())
+	go func() {
+		pcm, err := captureAudio(ctx, src, limit, func(level float64, elapsed time.Duration) {
+			p.Send(recordLevelMsg{level: level, elapsed: elapsed})
+		})
+		p.Send(recordDoneMsg{pcm: pcm, err: err})
+	}()
+	final, err := p.Run()
+	if err != nil {
+		return nil, err
+	}
+	m := final.(recordModel)
+	if m.discarded {
+		return nil, errRecordingDiscarded
+	}
+	return m.pcm, m.err
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// stubSource plays a fixed-length sine wave instead of using a microphone.
+type stubSource struct {
+	duration  time.Duration
+	amplitude float64
+}
+
+func (s stubSource) Name() string { return "stub" }
+
+func (s stubSource) Start(ctx context.Context) (io.ReadCloser, error) {
+	samples := int(s.duration.Seconds() * recordSampleRate)
+	pcm := make([]byte, samples*recordBytesPerFrame)
+	for i := range samples {
+		v := s.amplitude * math.Sin(2*math.Pi*440*float64(i)/recordSampleRate)
+		binary.LittleEndian.PutUint16(pcm[2*i:], uint16(int16(v*math.MaxInt16)))
+	}
+	return io.NopCloser(&ctxReader{ctx: ctx, r: bytes.NewReader(pcm)}), nil
+}
+
+// ctxReader ends the stream early once ctx is cancelled, like a stopped recorder.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *ctxReader) Read(p []byte) (int, error) {
+	if c.ctx.Err() != nil {
+		return 0, io.EOF
+	}
+	return c.r.Read(p)
+}
+
+// useStubRecorder makes recordings come from src and hides ffmpeg.
+func useStubRecorder(t *testing.T, src audioSource) {
+	t.Helper()
+	origSource, origLook := newAudioSource, lookPath
+	t.Cleanup(func() { newAudioSource, lookPath = origSource, origLook })
+	newAudioSource = func(string) (audioSource, error) { return src, nil }
+	lookPath = func(string) (string, error) { return "", exec.ErrNotFound }
+}
+
+func TestCaptureAudio(t *testing.T) {
+	var levels []float64
+	var last time.Duration
+	pcm, err := captureAudio(context.Background(), stubSource{duration: time.Second, amplitude: 0.5}, 0,
+		func(level float64, elapsed time.Duration) {
+			levels = append(levels, level)
+			last = elapsed
+		})
+	if err != nil {
+		t.Fatalf("captureAudio: %v", err)
+	}
+	if want := recordSampleRate * recordBytesPerFrame; len(pcm) != want {
+		t.Errorf("captured %d bytes, want %d", len(pcm), want)
+	}
+	if len(levels) != 10 {
+		t.Errorf("got %d level updates, want 10", len(levels))
+	}
+	if last != time.Second {
+		t.Errorf("final elapsed = %v, want 1s", last)
+	}
+	// A half-scale sine is about -9 dBFS, so the meter should sit near 0.85.
+	if l := levels[0]; l < 0.8 || l > 0.9 {
+		t.Errorf("level = %.2f, want about 0.85", l)
+	}
+}
+
+func TestCaptureAudioLimit(t *testing.T) {
+	pcm, err := captureAudio(context.Background(), stubSource{duration: 5 * time.Second, amplitude: 0.1}, 300*time.Millisecond, nil)
+	if err != nil {
+		t.Fatalf("captureAudio: %v", err)
+	}
+	if got := pcmDuration(len(pcm)); got != 300*time.Millisecond {
+		t.Errorf("recorded %v, want 300ms", got)
+	}
+}
+
+func TestCaptureAudioSilentSource(t *testing.T) {
+	if _, err := captureAudio(context.Background(), stubSource{}, 0, nil); err == nil {
+		t.Error("expected error when nothing was captured")
+	}
+}
+
+func TestPCMLevel(t *testing.T) {
+	silence := make([]byte, 320)
+	if got := pcmLevel(silence); got != 0 {
+		t.Errorf("silence level = %v, want 0", got)
+	}
+	full := make([]byte, 320)
+	for i := 0; i < len(full); i += 2 {
+		binary.LittleEndian.PutUint16(full[i:], uint16(math.MaxInt16))
+	}
+	if got := pcmLevel(full); got != 1 {
+		t.Errorf("full-scale level = %v, want 1", got)
+	}
+}
+
+func TestEncodeRecordingWAV(t *testing.T) {
+	useStubRecorder(t, stubSource{})
+	pcm := make([]byte, recordSampleRate*recordBytesPerFrame)
+
+	path, err := encodeRecording(context.Background(), pcm, t.TempDir())
+	if err != nil {
+		t.Fatalf("encodeRecording: %v", err)
+	}
+	if filepath.Ext(path) != ".wav" {
+		t.Errorf("path = %s, want a .wav without ffmpeg", path)
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // test output
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 44+len(pcm) {
+		t.Errorf("file is %d bytes, want %d", len(data), 44+len(pcm))
+	}
+	if got := http.DetectContentType(data); got != "audio/wave" {
+		t.Errorf("content type = %q, want audio/wave", got)
+	}
+	if rate := binary.LittleEndian.Uint32(data[24:]); rate != recordSampleRate {
+		t.Errorf("sample rate = %d, want %d", rate, recordSampleRate)
+	}
+}
+
+func TestRecordVoiceNoteCapsWAV(t *testing.T) {
+	useStubRecorder(t, stubSource{duration: 3 * time.Second, amplitude: 0.3})
+	second := int64(recordSampleRate * recordBytesPerFrame)
+
+	path, err := recordVoiceNote(context.Background(), "", time.Minute, wavHeaderSize+second, t.TempDir())
+	if err != nil {
+		t.Fatalf("recordVoiceNote: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != wavHeaderSize+second {
+		t.Errorf("WAV is %d bytes, want it stopped at the %d byte limit", info.Size(), wavHeaderSize+second)
+	}
+}
+
+func TestCanEncodeOpus(t *testing.T) {
+	encoders := " A....D aac      AAC (Advanced Audio Coding)\n"
+	fakeTools(t, []string{"ffmpeg"}, func(string, []string) ([]byte, error) { return []byte(encoders), nil })
+	if canEncodeOpus(context.Background()) {
+		t.Error("ffmpeg without libopus reported as able to encode Opus")
+	}
+	encoders += " A....D libopus  libopus Opus\n"
+	if !canEncodeOpus(context.Background()) {
+		t.Error("ffmpeg with libopus reported as unable to encode Opus")
+	}
+}
+
+func TestDetectAudioSource(t *testing.T) {
+	orig := lookPath
+	t.Cleanup(func() { lookPath = orig })
+	installed := map[string]bool{}
+	lookPath = func(name string) (string, error) {
+		if installed[name] {
+			return "/usr/bin/" + name, nil
+		}
+		return "", exec.ErrNotFound
+	}
+
+	if _, err := detectAudioSource(""); err == nil || !strings.Contains(err.Error(), "arecord") {
+		t.Errorf("err = %v, want a hint listing recorders", err)
+	}
+
+	installed["arecord"] = true
+	installed["ffmpeg"] = true
+	src, err := detectAudioSource("")
+	if err != nil || src.Name() != "arecord" {
+		t.Errorf("auto-detected %v (%v), want arecord", src, err)
+	}
+	src, err = detectAudioSource("ffmpeg")
+	if err != nil || src.Name() != "ffmpeg" {
+		t.Errorf("explicit ffmpeg gave %v (%v)", src, err)
+	}
+	if _, err := detectAudioSource("pw-record"); err == nil {
+		t.Error("expected error for a recorder that isn't installed")
+	}
+	if _, err := detectAudioSource("sox"); err == nil || !strings.Contains(err.Error(), "unknown recorder") {
+		t.Errorf("err = %v, want unknown recorder", err)
+	}
+}
+
+func TestRecordModelKeys(t *testing.T) {
+	tests := []struct {
+		key         tea.KeyMsg
+		wantDiscard bool
+	}{
+		{tea.KeyMsg{Type: tea.KeyEnter}, false},
+		{tea.KeyMsg{Type: tea.KeyEsc}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key.String(), func(t *testing.T) {
+			var stopped bool
+			m := newRecordModel("stub", time.Minute, "", func() { stopped = true })
+			updated, _ := m.Update(recordLevelMsg{level: 0.5, elapsed: 65 * time.Second})
+			if view := updated.View(); !strings.Contains(view, "1:05 / 1:00") {
+				t.Errorf("view missing timer:\n%s", view)
+			}
+			updated, _ = updated.Update(tt.key)
+			got := updated.(recordModel)
+			if !stopped || !got.stopping {
+				t.Error("key should stop the recorder")
+			}
+			if got.discarded != tt.wantDiscard {
+				t.Errorf("discarded = %v, want %v", got.discarded, tt.wantDiscard)
+			}
+		})
+	}
+}
+
+func TestRecordModelShowsNote(t *testing.T) {
+	m := newRecordModel("stub", 2*time.Minute, "stops at 2:00 to fit", func() {})
+	if view := m.View(); !strings.Contains(view, "stops at 2:00 to fit") {
+		t.Errorf("view missing note:\n%s", view)
+	}
+}
+
+func TestCommandCreateRecord(t *testing.T) {
+	srv := startFakeBackend(t)
+	useStubRecorder(t, stubSource{duration: 500 * time.Millisecond, amplitude: 0.3})
+
+	if _, err := runCLI(t, "voice memo", "create", "--record"); err != nil {
+		t.Fatalf("create --record: %v", err)
+	}
+	notes := srv.Notes()
+	if len(notes) != 1 || len(notes[0].GetAudios()) != 1 {
+		t.Fatalf("notes = %v, want one note with a recording", notes)
+	}
+}
+
+func TestCommandCreateRecordNoRecorder(t *testing.T) {
+	srv := startFakeBackend(t)
+	orig := newAudioSource
+	t.Cleanup(func() { newAudioSource = orig })
+	newAudioSource = func(string) (audioSource, error) { return nil, errors.New("no audio recorder found") }
+
+	if _, err := runCLI(t, "voice memo", "create", "--record"); err == nil {
+		t.Fatal("expected error without a recorder")
+	}
+	if n := len(srv.Notes()); n != 0 {
+		t.Errorf("got %d notes, want 0", n)
+	}
+}
@@ -0,0 +1,392 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/icco/etu/client"
+	"github.com/spf13/cobra"
+)
+
+var reviewCmd = &cobra.Command{
+	Use:   "review [week|month]",
+	Short: "Review this week, this month, or this day in past years.",
+	Long: `Summarize a week or month of entries: how many, on which days, words
+written and tags used. --on-this-day shows entries from today's date in past
+years instead. --guided steps through the entries one by one and lets you
+write follow-up notes that link back to them.`,
+	Example: `  etu review week
+  etu review month --previous
+  etu review --on-this-day --guided`,
+	Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+	ValidArgs: []string{"week", "month"},
+	RunE:      runReview,
+}
+
+// reviewPeriod returns the bounds of the week (from Monday) or month
+// containing now, or the one before it if previous is set.
+func reviewPeriod(period string, now time.Time, previous bool) (from, to time.Time, label string, err error) {
+	today := startOfDay(now, now.Location())
+	switch period {
+	case "day":
+		from = today
+		if previous {
+			from = from.AddDate(0, 0, -1)
+		}
+		to = from.AddDate(0, 0, 1)
+		label = "Today"
+		if previous {
+			label = "Yesterday"
+		}
+	case "week":
+		from = today.AddDate(0, 0, -weekdayColumn(today))
+		if previous {
+			from = from.AddDate(0, 0, -7)
+		}
+		to = from.AddDate(0, 0, 7)
+		label = "This week"
+		if previous {
+			label = "Last week"
+		}
+	case "month":
+		from = startOfMonth(today)
+		if previous {
+			from = from.AddDate(0, -1, 0)
+		}
+		to = from.AddDate(0, 1, 0)
+		label = "This month"
+		if previous {
+			label = "Last month"
+		}
+	default:
+		return from, to, "", fmt.Errorf("unknown period %q: use day, week or month", period)
+	}
+	return from, to, label, nil
+}
+
+// onThisDay keeps the posts written on now's month and day in earlier years.
+func onThisDay(posts []*client.Post, now time.Time) []*client.Post {
+	var out []*client.Post
+	for _, p := range posts {
+		t := p.CreatedAt.In(now.Location())
+		if t.Month() == now.Month() && t.Day() == now.Day() && t.Year() < now.Year() {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// reviewSummary totals a period's entries.
+type reviewSummary struct {
+	entries int
+	words   int
+	days    int // days with at least one entry
+	tags    []client.Tag
+}
+
+// summarize counts entries, words, active days and tags, with tags sorted
+// by use and then name.
+func summarize(posts []*client.Post, loc *time.Location) reviewSummary {
+	s := reviewSummary{entries: len(posts)}
+	tagCounts := map[string]int32{}
+	for _, p := range posts {
+		s.words += len(strings.Fields(p.Text))
+		for _, t := range p.Tags {
+			tagCounts[t]++
+		}
+	}
+	s.days = len(client.CountByDay(posts, loc))
+	for name, n := range tagCounts {
+		s.tags = append(s.tags, client.Tag{Name: name, Count: n})
+	}
+	sort.Slice(s.tags, func(a, b int) bool {
+		if s.tags[a].Count != s.tags[b].Count {
+			return s.tags[a].Count > s.tags[b].Count
+		}
+		return s.tags[a].Name < s.tags[b].Name
+	})
+	return s
+}
+
+// firstLine is the first non-empty line of text.
+func firstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// printReview writes a period summary followed by its entries by day.
+func printReview(w io.Writer, label string, from, to time.Time, posts []*client.Post) {
+	s := summarize(posts, from.Location())
+	periodDays := daysBetween(from, to)
+	fmt.Fprintf(w, "%s (%s – %s)\n", label, from.Format("Mon Jan 2"), to.AddDate(0, 0, -1).Format("Mon Jan 2 2006"))
+	fmt.Fprintf(w, "%d %s · %d %s · written on %d of %d days\n",
+		s.entries, plural(s.entries, "entry", "entries"), s.words, plural(s.words, "word", "words"), s.days, periodDays)
+	if len(s.tags) > 0 {
+		fmt.Fprintln(w, "\nTags")
+		for _, t := range s.tags {
+			fmt.Fprintf(w, "  %-20s %d\n", t.Name, t.Count)
+		}
+	}
+	for _, day := range groupByDay(posts, from.Location()) {
+		fmt.Fprintf(w, "\n%s (%d)\n", day.date.Format("Mon Jan 2"), len(day.posts))
+		for _, p := range day.posts {
+			fmt.Fprintf(w, "%8s  %s\n", p.CreatedAt.In(from.Location()).Format(displayTime.clock), truncate(firstLine(p.Text), 90))
+		}
+	}
+}
+
+// printOnThisDay writes entries from today's date in past years, newest year first.
+func printOnThisDay(w io.Writer, posts []*client.Post, now time.Time) {
+	fmt.Fprintf(w, "On this day, %s\n", now.Format("January 2"))
+	if len(posts) == 0 {
+		fmt.Fprintln(w, "\nNo entries from this date in past years.")
+		return
+	}
+	year := 0
+	for _, p := range posts {
+		t := p.CreatedAt.In(now.Location())
+		if t.Year() != year {
+			year = t.Year()
+			ago := now.Year() - year
+			fmt.Fprintf(w, "\n%d · %d %s ago\n", year, ago, plural(ago, "year", "years"))
+		}
+		fmt.Fprintf(w, "%8s  %s\n", t.Format(displayTime.clock), truncate(firstLine(p.Text), 90))
+	}
+}
+
+// followUpText is the text of a follow-up note, linking to the original.
+func followUpText(original *client.Post, note string) string {
+	return fmt.Sprintf("Follow-up to [[%s]] from %s:\n\n%s", original.PageID, formatTime(original.CreatedAt), strings.TrimSpace(note))
+}
+
+type followUpSavedMsg struct {
+	original string
+	post     *client.Post
+	err      error
+}
+
+func saveFollowUp(ctx context.Context, j client.Journal, original *client.Post, note string) tea.Cmd {
+	return func() tea.Msg {
+		post, err := j.SaveEntry(ctx, followUpText(original, note), nil, nil)
+		return followUpSavedMsg{original: original.PageID, post: post, err: err}
+	}
+}
+
+// reviewModel steps through entries one at a time. f opens a note editor
+// whose text is saved as a new entry linking back to the one shown.
+type reviewModel struct {
+	ctx     context.Context
+	journal client.Journal
+	title   string
+	posts   []*client.Post
+	index   int
+
+	writing   bool
+	saving    bool
+	editor    textarea.Model
+	followUps map[string]string // original ID to its latest follow-up's ID
+	saved     int
+	status    string
+	width     int
+	quitting  bool
+}
+
+func newReviewModel(ctx context.Context, j client.Journal, title string, posts []*client.Post) reviewModel {
+	ta := textarea.New()
+	ta.Placeholder = "What do you think about this now?"
+	ta.ShowLineNumbers = false
+	ta.SetWidth(76)
+	ta.SetHeight(6)
+	return reviewModel{
+		ctx:       ctx,
+		journal:   j,
+		title:     title,
+		posts:     posts,
+		editor:    ta,
+		followUps: map[string]string{},
+		width:     80,
+	}
+}
+
+func (m reviewModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m reviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.editor.SetWidth(min(max(msg.Width-8, 20), 100))
+		return m, nil
+
+	case followUpSavedMsg:
+		m.saving = false
+		if msg.err != nil {
+			// Keep the editor open so the note isn't lost.
+			m.status = "Saving failed: " + msg.err.Error()
+			return m, nil
+		}
+		m.writing = false
+		m.editor.Reset()
+		m.editor.Blur()
+		m.followUps[msg.original] = msg.post.PageID
+		m.saved++
+		m.status = "Saved follow-up " + msg.post.PageID + "."
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.writing {
+			return m.updateEditor(msg)
+		}
+		m.status = ""
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			m.quitting = true
+			return m, tea.Quit
+		case "right", "l", "n", " ":
+			if m.index < len(m.posts)-1 {
+				m.index++
+			} else {
+				m.status = "That was the last entry; q to finish."
+			}
+		case "left", "h", "p":
+			m.index = max(m.index-1, 0)
+		case "f", "enter":
+			if len(m.posts) > 0 {
+				m.writing = true
+				return m, m.editor.Focus()
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m reviewModel) updateEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.saving {
+		return m, nil
+	}
+	switch msg.String() {
+	case "ctrl+c":
+		m.quitting = true
+		return m, tea.Quit
+	case "esc":
+		m.writing = false
+		m.editor.Blur()
+		return m, nil
+	case "ctrl+s":
+		if strings.TrimSpace(m.editor.Value()) == "" {
+			m.status = "Write something first, or esc to cancel."
+			return m, nil
+		}
+		m.saving = true
+		m.status = "Saving..."
+		return m, saveFollowUp(m.ctx, m.journal, m.posts[m.index], m.editor.Value())
+	}
+	var cmd tea.Cmd
+	m.editor, cmd = m.editor.Update(msg)
+	return m, cmd
+}
+
+func (m reviewModel) View() string {
+	if m.quitting {
+		return ""
+	}
+	var s strings.Builder
+	s.WriteString(timelineHeaderStyle.Render(m.title))
+	if len(m.posts) == 0 {
+		s.WriteString("\n\nNothing to review.\n")
+		return docStyle.Render(s.String())
+	}
+	p := m.posts[m.index]
+	s.WriteString(timelineGapStyle.Render(fmt.Sprintf("  entry %d of %d", m.index+1, len(m.posts))))
+	s.WriteString("\n\n")
+	s.WriteString(lipgloss.NewStyle().Bold(true).Render(formatTime(p.CreatedAt)))
+	if len(p.Tags) > 0 {
+		s.WriteString(timelineGapStyle.Render("  [" + strings.Join(p.Tags, ", ") + "]"))
+	}
+	if id, ok := m.followUps[p.PageID]; ok {
+		s.WriteString(timelineLongStyle.Render("  followed up in " + id))
+	}
+	s.WriteString("\n\n")
+	s.WriteString(lipgloss.NewStyle().Width(min(m.width-6, 100)).Render(strings.TrimSpace(p.Text)))
+	s.WriteString("\n\n")
+	if m.writing {
+		s.WriteString(m.editor.View())
+		s.WriteString("\n")
+		s.WriteString(timelineHelpStyle.Render("ctrl+s save follow-up · esc cancel"))
+	} else {
+		s.WriteString(timelineHelpStyle.Render("←/→ previous/next · f write a follow-up · q quit"))
+	}
+	if m.status != "" {
+		s.WriteString("\n" + m.status)
+	}
+	return docStyle.Render(s.String())
+}
+
+func runReview(cmd *cobra.Command, args []string) error {
+	onDay, _ := cmd.Flags().GetBool("on-this-day")
+	guided, _ := cmd.Flags().GetBool("guided")
+	previous, _ := cmd.Flags().GetBool("previous")
+	if onDay && len(args) > 0 {
+		return fmt.Errorf("use either --on-this-day or a period, not both")
+	}
+	now := time.Now().In(displayTime.loc)
+
+	var posts []*client.Post
+	var title string
+	if onDay {
+		// Past years can only be reached by listing everything.
+		all, _, err := client.ListPostsBetween(cmd.Context(), journal, time.Time{}, time.Time{})
+		if err != nil {
+			return err
+		}
+		posts = onThisDay(all, now)
+		title = "On this day, " + now.Format("January 2")
+		if !guided {
+			printOnThisDay(cmd.OutOrStdout(), posts, now)
+			return nil
+		}
+	} else {
+		period := "week"
+		if len(args) > 0 {
+			period = args[0]
+		}
+		from, to, label, err := reviewPeriod(period, now, previous)
+		if err != nil {
+			return err
+		}
+		posts, _, err = client.ListPostsBetween(cmd.Context(), journal, from, to)
+		if err != nil {
+			return err
+		}
+		title = label
+		if !guided {
+			printReview(cmd.OutOrStdout(), label, from, to, posts)
+			return nil
+		}
+	}
+
+	if len(posts) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "Nothing to review.")
+		return nil
+	}
+	final, err := tea.NewProgram(newReviewModel(cmd.Context(), journal, title, posts), tea.WithAltScreen()).Run()
+	if err != nil {
+		return err
+	}
+	if n := final.(reviewModel).saved; n > 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "Saved %d follow-up %s.\n", n, plural(n, "note", "notes"))
+	}
+	return nil
+}
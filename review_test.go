@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/icco/etu/client"
+	"github.com/icco/etu/client/fake"
+)
+
+func TestReviewPeriod(t *testing.T) {
+	// A Thursday.
+	now := time.Date(2026, 3, 12, 15, 0, 0, 0, time.UTC)
+	day := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 0, 0, 0, 0, time.UTC) }
+	for _, tc := range []struct {
+		period   string
+		previous bool
+		from, to time.Time
+		label    string
+	}{
+		{"day", false, day(3, 12), day(3, 13), "Today"},
+		{"day", true, day(3, 11), day(3, 12), "Yesterday"},
+		{"week", false, day(3, 9), day(3, 16), "This week"},
+		{"week", true, day(3, 2), day(3, 9), "Last week"},
+		{"month", false, day(3, 1), day(4, 1), "This month"},
+		{"month", true, day(2, 1), day(3, 1), "Last month"},
+	} {
+		from, to, label, err := reviewPeriod(tc.period, now, tc.previous)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !from.Equal(tc.from) || !to.Equal(tc.to) || label != tc.label {
+			t.Errorf("reviewPeriod(%s, %v) = %v, %v, %q; want %v, %v, %q", tc.period, tc.previous, from, to, label, tc.from, tc.to, tc.label)
+		}
+	}
+	if _, _, _, err := reviewPeriod("year", now, false); err == nil {
+		t.Error("reviewPeriod(year): want error")
+	}
+}
+
+func TestOnThisDayAndSummarize(t *testing.T) {
+	now := time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC)
+	posts := []*client.Post{
+		{Text: "today", CreatedAt: now},
+		{Text: "a year ago", Tags: []string{"work"}, CreatedAt: now.AddDate(-1, 0, 0)},
+		{Text: "the day before", CreatedAt: now.AddDate(-1, 0, -1)},
+		{Text: "two years ago", Tags: []string{"work", "travel"}, CreatedAt: now.AddDate(-2, 0, 0).Add(5 * time.Hour)},
+	}
+	got := onThisDay(posts, now)
+	if len(got) != 2 || got[0].Text != "a year ago" || got[1].Text != "two years ago" {
+		t.Errorf("onThisDay = %v, want the entries from Mar 14 2025 and 2024", got)
+	}
+
+	s := summarize(posts, time.UTC)
+	if s.entries != 4 || s.words != 10 || s.days != 4 {
+		t.Errorf("summarize = %+v, want 4 entries, 10 words, 4 days", s)
+	}
+	if len(s.tags) != 2 || s.tags[0].Name != "work" || s.tags[0].Count != 2 {
+		t.Errorf("tags = %v, want work first", s.tags)
+	}
+}
+
+func TestCommandReviewWeek(t *testing.T) {
+	srv := startFakeBackend(t)
+	useDisplayTime(t, displayTime)
+	now := time.Now()
+	srv.AddNote("shipped the release\nmore detail", now.Add(-time.Minute), "release")
+	srv.AddNote("ancient", now.AddDate(0, 0, -40))
+
+	out, err := runCLI(t, "", "review", "week", "--clock", "24h")
+	if err != nil {
+		t.Fatalf("review: %v", err)
+	}
+	for _, want := range []string{"This week (", "1 entry · 5 words · written on 1 of 7 days", "  release", "shipped the release"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "ancient") || strings.Contains(out, "more detail") {
+		t.Errorf("output has old entries or later lines:\n%s", out)
+	}
+
+	if _, err := runCLI(t, "", "review", "year"); err == nil {
+		t.Error("review year: want error")
+	}
+}
+
+func TestCommandReviewOnThisDay(t *testing.T) {
+	srv := startFakeBackend(t)
+	useDisplayTime(t, displayTime)
+	now := time.Now()
+	srv.AddNote("last year's entry", now.AddDate(-1, 0, 0))
+	srv.AddNote("yesterday", now.AddDate(0, 0, -1))
+
+	out, err := runCLI(t, "", "review", "--on-this-day")
+	if err != nil {
+		t.Fatalf("review: %v", err)
+	}
+	if !strings.Contains(out, "1 year ago") || !strings.Contains(out, "last year's entry") || strings.Contains(out, "yesterday") {
+		t.Errorf("output:\n%s", out)
+	}
+}
+
+func TestReviewModelFollowUp(t *testing.T) {
+	useDisplayTime(t, timeFormat{loc: time.UTC, layout: "2006-01-02 15:04", clock: "15:04", now: time.Now})
+	original := &client.Post{PageID: "orig", Text: "thinking about moving", CreatedAt: time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)}
+	j := fake.NewJournal(original)
+	m := newReviewModel(context.Background(), j, "On this day", []*client.Post{original})
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("we did move")})
+	updated, cmd := updated.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if cmd == nil {
+		t.Fatal("ctrl+s should save")
+	}
+	updated, _ = updated.Update(cmd())
+	got := updated.(reviewModel)
+	if got.writing || got.saved != 1 || got.followUps["orig"] == "" {
+		t.Fatalf("after saving: writing=%v saved=%d followUps=%v", got.writing, got.saved, got.followUps)
+	}
+
+	posts := j.Posts()
+	if len(posts) != 2 {
+		t.Fatalf("journal has %d posts, want 2", len(posts))
+	}
+	want := "Follow-up to [[orig]] from 2025-03-14 09:00:\n\nwe did move"
+	if posts[0].Text != want {
+		t.Errorf("follow-up text = %q, want %q", posts[0].Text, want)
+	}
+}
@@ -32,15 +32,17 @@ func searchPosts(cmd *cobra.Command, args []string) error {
 	}
 
 	// Run the list model in search mode with the query
-	model := newPostListModel(cfg, 50, "Search Results", false)
+	model := newPostListModel(cmd.Context(), journal, 50, "Search Results", false)
 	model.query = query
 	model.loading = true
+	model.enableBulk()
 
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	finalModel, err := p.Run()
 	if err != nil {
 		return err
 	}
+	printBulkStatus(cmd, finalModel.(postListModel))
 
 	// If a post was selected, display it with media
 	if finalModel.(postListModel).selected != nil {
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"os"
 	"strings"
@@ -26,7 +27,7 @@ var showCmd = &cobra.Command{
 
 func showPost(cmd *cobra.Command, _ []string) error {
 	// Show list of posts to select from
-	model := newPostListModel(cfg, 25, "Select entry to view", true)
+	model := newPostListModel(cmd.Context(), journal, 25, "Select entry to view", true)
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	finalModel, err := p.Run()
 	if err != nil {
@@ -45,11 +46,13 @@ func showPost(cmd *cobra.Command, _ []string) error {
 func displayPost(cmd *cobra.Command, post *client.Post) error {
 	// Fetch full content
 	var fullText string
-	var fetchErr error
+	var fetchErr, indexErr error
+	var index *client.LinkIndex
 	err := spinner.New().
 		Title("Loading full content...").
 		Action(func() {
-			fullText, fetchErr = cfg.GetPostFullContent(cmd.Context(), post.PageID)
+			fullText, fetchErr = journal.GetPostFullContent(cmd.Context(), post.PageID)
+			index, indexErr = loadLinkIndex(cmd.Context(), journal, false)
 		}).
 		Run()
 
@@ -59,20 +62,31 @@ func displayPost(cmd *cobra.Command, post *client.Post) error {
 	if fetchErr != nil {
 		fullText = post.Text
 	}
+	if indexErr != nil {
+		log.Printf("etu: loading link index: %v", indexErr)
+	}
+	if index != nil {
+		index.Add(&client.Post{PageID: post.PageID, CreatedAt: post.CreatedAt, Text: fullText})
+	}
+	loc := displayTime.loc
 
 	// Display header
 	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("170"))
 	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
 
 	fmt.Println()
-	fmt.Println(headerStyle.Render("Date: ") + post.CreatedAt.Format("2006-01-02 15:04"))
+	fmt.Println(headerStyle.Render("Date: ") + formatTime(post.CreatedAt))
 
 	if len(post.Tags) > 0 {
 		fmt.Println(headerStyle.Render("Tags: ") + strings.Join(post.Tags, ", "))
 	}
 
 	fmt.Println()
-	fmt.Println(fullText)
+	if index != nil {
+		fmt.Println(linkText(index, fullText, loc))
+	} else {
+		fmt.Println(fullText)
+	}
 
 	// Display images
 	if len(post.Images) > 0 {
@@ -100,8 +114,24 @@ func displayPost(cmd *cobra.Command, post *client.Post) error {
 		}
 	}
 
+	if index == nil {
+		fmt.Println()
+		return nil
+	}
+	linked := writeLinks(os.Stdout, index, post.PageID, fullText, loc)
 	fmt.Println()
-	return nil
+	if len(linked) == 0 || !isInteractive(os.Stdin) {
+		return nil
+	}
+	id, err := followLink(linked)
+	if err != nil || id == "" {
+		return err
+	}
+	next, err := journal.GetPost(cmd.Context(), id)
+	if err != nil {
+		return err
+	}
+	return displayPost(cmd, next)
 }
 
 func truncate(s string, maxLen int) string {
@@ -16,7 +16,7 @@ var statsCmd = &cobra.Command{
 }
 
 func showStats(cmd *cobra.Command, _ []string) error {
-	personal, err := cfg.GetStats(cmd.Context(), false)
+	personal, err := journal.GetStats(cmd.Context(), false)
 	if err != nil {
 		return err
 	}
@@ -28,14 +28,14 @@ func showStats(cmd *cobra.Command, _ []string) error {
 
 	var community *client.Stats
 	if global {
-		stats, err := cfg.GetStats(cmd.Context(), true)
+		stats, err := journal.GetStats(cmd.Context(), true)
 		if err != nil {
 			return err
 		}
 		community = &stats
 	}
 
-	fmt.Print(formatStats(personal, community))
+	fmt.Fprint(cmd.OutOrStdout(), formatStats(personal, community))
 	return nil
 }
 
@@ -18,12 +18,12 @@ var tagsCmd = &cobra.Command{
 }
 
 func listTags(cmd *cobra.Command, _ []string) error {
-	tags, err := cfg.ListTags(cmd.Context())
+	tags, err := journal.ListTags(cmd.Context())
 	if err != nil {
 		return err
 	}
 
-	fmt.Print(formatTags(tags))
+	fmt.Fprint(cmd.OutOrStdout(), formatTags(tags))
 	return nil
 }
 
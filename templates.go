@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/icco/etu/client"
+	"github.com/spf13/cobra"
+)
+
+// templateExt is the file extension of entry templates.
+const templateExt = ".tmpl"
+
+// starterTemplate seeds new templates with the available placeholders.
+const starterTemplate = `{{/* Placeholders: .Date .Time .Now .LastEntry .GitBranch .Cwd */ -}}
+{{.Date}} {{.Time}}
+
+`
+
+var validTemplateName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+var (
+	templateCmd = &cobra.Command{
+		Use:   "template",
+		Short: "Manage entry templates used by create --template.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	templateListCmd = &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List entry templates.",
+		Args:    cobra.NoArgs,
+		RunE:    listTemplatesCmd,
+	}
+
+	templateNewCmd = &cobra.Command{
+		Use:   "new <name>",
+		Short: "Create an entry template and open it in $EDITOR.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  newTemplate,
+	}
+
+	templateEditCmd = &cobra.Command{
+		Use:               "edit <name>",
+		Short:             "Open an entry template in $EDITOR.",
+		Args:              cobra.ExactArgs(1),
+		RunE:              editTemplate,
+		ValidArgsFunction: completeTemplateNames,
+	}
+)
+
+// openEditor opens path in the user's editor; a seam for tests.
+var openEditor = func(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	// The editor may carry arguments, e.g. "code --wait".
+	fields := strings.Fields(editor)
+	cmd := exec.Command(fields[0], append(fields[1:], path)...) //nolint:gosec // G204: the user's own $EDITOR
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
+
+// templatePath returns where the template called name is stored.
+func templatePath(name string) (string, error) {
+	if !validTemplateName.MatchString(name) {
+		return "", fmt.Errorf("invalid template name %q: use letters, digits, - and _", name)
+	}
+	dir, err := client.TemplatesDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+templateExt), nil
+}
+
+// listTemplates returns the names of all templates, sorted.
+func listTemplates() ([]string, error) {
+	dir, err := client.TemplatesDir()
+	if err != nil {
+		return nil, err
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+templateExt))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		names = append(names, strings.TrimSuffix(filepath.Base(m), templateExt))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// templateData is what templates can reference. Values that need the backend
+// or another process are methods, so they are only computed when used.
+type templateData struct {
+	ctx     context.Context
+	journal client.Journal
+	now     time.Time
+}
+
+// Now is the current time, for custom layouts: {{.Now.Format "Mon Jan 2"}}.
+func (d templateData) Now() time.Time { return d.now }
+
+// Date is today's date as YYYY-MM-DD.
+func (d templateData) Date() string { return d.now.Format("2006-01-02") }
+
+// Time is the current time as HH:MM.
+func (d templateData) Time() string { return d.now.Format("15:04") }
+
+// LastEntry is the text of the most recent entry, or "" if there is none.
+func (d templateData) LastEntry() (string, error) {
+	posts, err := d.journal.ListPosts(d.ctx, 1)
+	if err != nil {
+		return "", fmt.Errorf("last entry: %w", err)
+	}
+	if len(posts) == 0 {
+		return "", nil
+	}
+	return strings.TrimSpace(posts[0].Text), nil
+}
+
+// GitBranch is the branch checked out in the working directory, or "" outside a repo.
+func (d templateData) GitBranch() string {
+	out, err := runCommand(d.ctx, "git", "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// Cwd is the working directory.
+func (d templateData) Cwd() string {
+	wd, _ := os.Getwd()
+	return wd
+}
+
+// renderTemplate executes the template called name.
+func renderTemplate(ctx context.Context, name string, j client.Journal) (string, error) {
+	path, err := templatePath(name)
+	if err != nil {
+		return "", err
+	}
+	src, err := os.ReadFile(path) //nolint:gosec // G304: path is under the templates dir
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("template %q not found; create it with `etu template new %s`", name, name)
+		}
+		return "", err
+	}
+	tmpl, err := template.New(name).Parse(string(src))
+	if err != nil {
+		return "", fmt.Errorf("parse template %s: %w", name, err)
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, templateData{ctx: ctx, journal: j, now: time.Now()}); err != nil {
+		return "", fmt.Errorf("render template %s: %w", name, err)
+	}
+	return out.String(), nil
+}
+
+// pickTemplate lets the user choose a template and returns its name, or ""
+// if there are none.
+func pickTemplate() (string, error) {
+	names, err := listTemplates()
+	if err != nil || len(names) == 0 {
+		return "", err
+	}
+	var name string
+	err = huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Template").
+				Options(huh.NewOptions(names...)...).
+				Value(&name),
+		),
+	).Run()
+	return name, err
+}
+
+// insertTemplate asks for a template and adds it to the entry being written.
+func insertTemplate(cmd *cobra.Command, fields *createFields) error {
+	name, err := pickTemplate()
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		return fmt.Errorf("no templates yet; create one with `etu template new <name>`")
+	}
+	rendered, err := renderTemplate(cmd.Context(), name, journal)
+	if err != nil {
+		return err
+	}
+	fields.text = applyTemplate(fields.text, rendered)
+	return nil
+}
+
+// applyTemplate puts rendered template text into an entry: it replaces an
+// empty entry and otherwise goes after it, separated by a blank line.
+func applyTemplate(text, rendered string) string {
+	if strings.TrimSpace(text) == "" {
+		return rendered
+	}
+	return strings.TrimRight(text, "\n") + "\n\n" + rendered
+}
+
+func listTemplatesCmd(cmd *cobra.Command, _ []string) error {
+	names, err := listTemplates()
+	if err != nil {
+		return err
+	}
+	for _, n := range names {
+		fmt.Fprintln(cmd.OutOrStdout(), n)
+	}
+	return nil
+}
+
+func newTemplate(_ *cobra.Command, args []string) error {
+	path, err := templatePath(args[0])
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("template %q already exists; use `etu template edit %s`", args[0], args[0])
+	}
+	if err := os.WriteFile(path, []byte(starterTemplate), 0600); err != nil {
+		return fmt.Errorf("create template: %w", err)
+	}
+	return openEditor(path)
+}
+
+func editTemplate(_ *cobra.Command, args []string) error {
+	path, err := templatePath(args[0])
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("template %q not found; create it with `etu template new %s`", args[0], args[0])
+	}
+	return openEditor(path)
+}
+
+func completeTemplateNames(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names, _ := listTemplates()
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	templateCmd.AddCommand(templateListCmd, templateNewCmd, templateEditCmd)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/icco/etu/client"
+	"github.com/icco/etu/client/fake"
+)
+
+// writeTemplate stores a template under a temporary config dir.
+func writeTemplate(t *testing.T, name, body string) {
+	t.Helper()
+	path, err := templatePath(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func useTempConfigDir(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+}
+
+func TestRenderTemplate(t *testing.T) {
+	useTempConfigDir(t)
+	fakeTools(t, nil, func(name string, args []string) ([]byte, error) {
+		if name == "git" && args[0] == "rev-parse" {
+			return []byte("feature/x\n"), nil
+		}
+		return nil, errors.New("unexpected command")
+	})
+	j := fake.NewJournal(&client.Post{Text: "  shipped the fix  ", CreatedAt: time.Now()})
+	wd, _ := os.Getwd()
+
+	writeTemplate(t, "standup", "{{.Date}}|{{.Time}}|{{.LastEntry}}|{{.GitBranch}}|{{.Cwd}}|{{.Now.Year}}")
+	got, err := renderTemplate(context.Background(), "standup", j)
+	if err != nil {
+		t.Fatalf("renderTemplate: %v", err)
+	}
+	parts := strings.Split(got, "|")
+	if len(parts) != 6 {
+		t.Fatalf("rendered %q", got)
+	}
+	if _, err := time.Parse("2006-01-02", parts[0]); err != nil {
+		t.Errorf("date = %q", parts[0])
+	}
+	if _, err := time.Parse("15:04", parts[1]); err != nil {
+		t.Errorf("time = %q", parts[1])
+	}
+	if parts[2] != "shipped the fix" || parts[3] != "feature/x" || parts[4] != wd {
+		t.Errorf("rendered %q", got)
+	}
+}
+
+func TestRenderTemplateErrors(t *testing.T) {
+	useTempConfigDir(t)
+	j := fake.NewJournal()
+
+	if _, err := renderTemplate(context.Background(), "missing", j); err == nil || !strings.Contains(err.Error(), "etu template new missing") {
+		t.Errorf("err = %v, want hint to create it", err)
+	}
+	if _, err := renderTemplate(context.Background(), "../escape", j); err == nil || !strings.Contains(err.Error(), "invalid template name") {
+		t.Errorf("err = %v, want invalid name", err)
+	}
+	writeTemplate(t, "broken", "{{.Nope}}")
+	if _, err := renderTemplate(context.Background(), "broken", j); err == nil {
+		t.Error("expected error for unknown placeholder")
+	}
+}
+
+func TestApplyTemplate(t *testing.T) {
+	tests := []struct {
+		text, rendered, want string
+	}{
+		{"", "## Standup\n", "## Standup\n"},
+		{"  \n", "## Standup\n", "## Standup\n"},
+		{"draft\n\n", "## Standup\n", "draft\n\n## Standup\n"},
+	}
+	for _, tt := range tests {
+		if got := applyTemplate(tt.text, tt.rendered); got != tt.want {
+			t.Errorf("applyTemplate(%q, %q) = %q, want %q", tt.text, tt.rendered, got, tt.want)
+		}
+	}
+}
+
+func TestCommandTemplateNewListEdit(t *testing.T) {
+	useTempConfigDir(t)
+	cfg = &client.Config{} // template commands don't need an API key
+	var opened []string
+	orig := openEditor
+	t.Cleanup(func() { openEditor = orig })
+	openEditor = func(path string) error {
+		opened = append(opened, path)
+		return nil
+	}
+
+	if _, err := runCLI(t, "", "template", "new", "standup"); err != nil {
+		t.Fatalf("template new: %v", err)
+	}
+	if len(opened) != 1 || filepath.Base(opened[0]) != "standup.tmpl" {
+		t.Fatalf("opened %v, want standup.tmpl", opened)
+	}
+	data, err := os.ReadFile(opened[0]) //nolint:gosec // test output
+	if err != nil || !strings.Contains(string(data), "{{.Date}}") {
+		t.Errorf("starter template = %q, %v", data, err)
+	}
+	if _, err := runCLI(t, "", "template", "new", "standup"); err == nil {
+		t.Error("expected error creating an existing template")
+	}
+
+	writeTemplate(t, "retro", "retro")
+	out, err := runCLI(t, "", "template", "list")
+	if err != nil {
+		t.Fatalf("template list: %v", err)
+	}
+	if out != "retro\nstandup\n" {
+		t.Errorf("list = %q", out)
+	}
+
+	if _, err := runCLI(t, "", "template", "edit", "retro"); err != nil {
+		t.Fatalf("template edit: %v", err)
+	}
+	if _, err := runCLI(t, "", "template", "edit", "nope"); err == nil {
+		t.Error("expected error editing a missing template")
+	}
+}
+
+func TestCommandCreateFromTemplate(t *testing.T) {
+	srv := startFakeBackend(t)
+	writeTemplate(t, "switch", "Switching to:\n")
+
+	if _, err := runCLI(t, "the report", "create", "--template", "switch"); err != nil {
+		t.Fatalf("create --template: %v", err)
+	}
+	notes := srv.Notes()
+	if len(notes) != 1 || notes[0].GetContent() != "Switching to:\n\nthe report" {
+		t.Fatalf("notes = %v", notes)
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// dateLayoutPresets are the named date formats; the clock is added after.
+var dateLayoutPresets = map[string]string{
+	"iso":  "2006-01-02",
+	"us":   "01/02/2006",
+	"eu":   "02.01.2006",
+	"long": "Mon Jan 2 2006",
+}
+
+// twelveHourLocales are locales that conventionally use a 12-hour clock.
+var twelveHourLocales = []string{"en_US", "en_CA", "en_AU", "en_NZ", "en_PH", "en_IN", "hi_IN", "ko_KR", "ar_"}
+
+// timeFormat renders entry times for display.
+type timeFormat struct {
+	loc      *time.Location
+	layout   string
+	clock    string // time-of-day layout, used by relative dates
+	relative bool
+	now      func() time.Time
+}
+
+// displayTime is how entry times are shown, set from config and flags
+// before each command runs.
+var displayTime = timeFormat{loc: time.Local, layout: "2006-01-02 15:04", clock: "15:04", now: time.Now}
+
+// newTimeFormat builds a timeFormat from a timezone name (empty or "local"
+// for the system zone), a date format and a clock ("12h", "24h" or "auto").
+func newTimeFormat(zone, dateFormat, clock string) (timeFormat, error) {
+	f := timeFormat{loc: time.Local, now: time.Now}
+	if zone != "" && !strings.EqualFold(zone, "local") {
+		loc, err := time.LoadLocation(zone)
+		if err != nil {
+			return f, fmt.Errorf("unknown timezone %q: use an IANA name like Europe/Berlin", zone)
+		}
+		f.loc = loc
+	}
+
+	switch strings.ToLower(clock) {
+	case "", "auto":
+		f.clock = "15:04"
+		if localeUses12h() {
+			f.clock = "3:04 PM"
+		}
+	case "24h":
+		f.clock = "15:04"
+	case "12h":
+		f.clock = "3:04 PM"
+	default:
+		return f, fmt.Errorf("unknown clock %q: use 12h, 24h or auto", clock)
+	}
+
+	switch name := strings.ToLower(dateFormat); {
+	case name == "":
+		f.layout = dateLayoutPresets["iso"] + " " + f.clock
+	case name == "relative":
+		f.relative = true
+	case dateLayoutPresets[name] != "":
+		f.layout = dateLayoutPresets[name] + " " + f.clock
+	default:
+		// A custom Go layout; formatting any other time has to change it.
+		probe := time.Date(2001, 11, 23, 8, 9, 7, 0, time.UTC)
+		if probe.Format(dateFormat) == dateFormat {
+			return f, fmt.Errorf("unknown date format %q: use iso, us, eu, long, relative or a Go layout like \"Jan 2 15:04\"", dateFormat)
+		}
+		f.layout = dateFormat
+	}
+	return f, nil
+}
+
+// localeUses12h reports whether the user's time locale prefers a 12-hour clock.
+func localeUses12h() bool {
+	locale := ""
+	for _, env := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		if locale = os.Getenv(env); locale != "" {
+			break
+		}
+	}
+	for _, l := range twelveHourLocales {
+		if strings.HasPrefix(locale, l) {
+			return true
+		}
+	}
+	return false
+}
+
+// Format renders t in the configured zone and layout.
+func (f timeFormat) Format(t time.Time) string {
+	t = t.In(f.loc)
+	if !f.relative {
+		return t.Format(f.layout)
+	}
+	now := f.now().In(f.loc)
+	d, days := now.Sub(t), daysBetween(t, now)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case days == 0:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case days == 1:
+		return "yesterday " + t.Format(f.clock)
+	case days < 7:
+		return fmt.Sprintf("%dd ago", days)
+	case t.Year() == now.Year():
+		return t.Format("Jan 2")
+	default:
+		return t.Format("Jan 2 2006")
+	}
+}
+
+// daysBetween counts calendar days from a to b, by their wall clock dates.
+func daysBetween(a, b time.Time) int {
+	da := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	db := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da).Hours() / 24)
+}
+
+// formatTime renders an entry time for display.
+func formatTime(t time.Time) string {
+	return displayTime.Format(t)
+}
+
+// applyDisplayFlags sets displayTime from the config, overridden by the
+// --timezone, --date-format and --clock flags.
+func applyDisplayFlags(cmd *cobra.Command) error {
+	var zone, dateFormat, clock string
+	if cfg != nil {
+		zone, dateFormat, clock = cfg.Timezone, cfg.DateFormat, cfg.Clock
+	}
+	if f := cmd.Flags().Lookup("timezone"); f != nil && f.Changed {
+		zone = f.Value.String()
+	}
+	if f := cmd.Flags().Lookup("date-format"); f != nil && f.Changed {
+		dateFormat = f.Value.String()
+	}
+	if f := cmd.Flags().Lookup("clock"); f != nil && f.Changed {
+		clock = f.Value.String()
+	}
+	format, err := newTimeFormat(zone, dateFormat, clock)
+	if err != nil {
+		return err
+	}
+	displayTime = format
+	return nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// useDisplayTime sets displayTime for one test.
+func useDisplayTime(t *testing.T, f timeFormat) {
+	t.Helper()
+	old := displayTime
+	displayTime = f
+	t.Cleanup(func() { displayTime = old })
+}
+
+func TestNewTimeFormat(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_TIME", "")
+	t.Setenv("LANG", "de_DE.UTF-8")
+	at := time.Date(2026, 3, 14, 21, 5, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		zone, dateFormat, clock string
+		want, wantErr           string
+	}{
+		{zone: "UTC", want: "2026-03-14 21:05"},
+		{zone: "UTC", clock: "12h", want: "2026-03-14 9:05 PM"},
+		{zone: "UTC", dateFormat: "us", want: "03/14/2026 21:05"},
+		{zone: "UTC", dateFormat: "EU", clock: "24h", want: "14.03.2026 21:05"},
+		{zone: "UTC", dateFormat: "long", want: "Sat Mar 14 2026 21:05"},
+		{zone: "UTC", dateFormat: "Jan 2 15:04", want: "Mar 14 21:05"},
+		{zone: "Asia/Tokyo", want: "2026-03-15 06:05"},
+		{zone: "America/New_York", clock: "12h", want: "2026-03-14 5:05 PM"},
+		{zone: "Mars/Olympus", wantErr: "unknown timezone"},
+		{clock: "13h", wantErr: "unknown clock"},
+		{dateFormat: "fancy", wantErr: "unknown date format"},
+	} {
+		t.Run(tc.zone+"/"+tc.dateFormat+"/"+tc.clock, func(t *testing.T) {
+			f, err := newTimeFormat(tc.zone, tc.dateFormat, tc.clock)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := f.Format(at); got != tc.want {
+				t.Errorf("Format = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTimeFormatAutoClock(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_TIME", "en_US.UTF-8")
+	t.Setenv("LANG", "de_DE.UTF-8")
+	f, err := newTimeFormat("UTC", "", "auto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := f.Format(time.Date(2026, 3, 14, 9, 5, 0, 0, time.UTC)); got != "2026-03-14 9:05 AM" {
+		t.Errorf("Format = %q, want a 12h clock for en_US", got)
+	}
+}
+
+func TestTimeFormatRelative(t *testing.T) {
+	f, err := newTimeFormat("UTC", "relative", "24h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	f.now = func() time.Time { return now }
+	for _, tc := range []struct {
+		at   time.Time
+		want string
+	}{
+		{now.Add(30 * time.Second), "just now"},
+		{now.Add(-20 * time.Second), "just now"},
+		{now.Add(-20 * time.Minute), "20m ago"},
+		{now.Add(-3 * time.Hour), "3h ago"},
+		{time.Date(2026, 3, 13, 17, 30, 0, 0, time.UTC), "yesterday 17:30"},
+		{time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC), "4d ago"},
+		{time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC), "Jan 2"},
+		{time.Date(2025, 12, 30, 9, 0, 0, 0, time.UTC), "Dec 30 2025"},
+	} {
+		if got := f.Format(tc.at); got != tc.want {
+			t.Errorf("Format(%v) = %q, want %q", tc.at, got, tc.want)
+		}
+	}
+}
+
+func TestCommandDateFormatFlags(t *testing.T) {
+	startFakeBackend(t)
+	useDisplayTime(t, displayTime)
+
+	out, err := runCLI(t, "", "create", "--date-format", "relative", "hello")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if !strings.Contains(out, "(just now)") {
+		t.Errorf("output = %q, want a relative time", out)
+	}
+	if _, err := runCLI(t, "", "create", "--timezone", "Nowhere/Special", "hello"); err == nil {
+		t.Error("create with an unknown timezone: want error")
+	}
+}
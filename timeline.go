@@ -0,0 +1,519 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/icco/etu/client"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// timelinePageSize is how many more entries each "load more" fetches.
+	timelinePageSize = 100
+	// timelineLongGap is the gap after which it is highlighted.
+	timelineLongGap = 2 * time.Hour
+)
+
+var (
+	timelineHeaderStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("170"))
+	timelineGapStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	timelineLongStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	timelineCursorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("170"))
+	timelineHelpStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+)
+
+var timelineCmd = &cobra.Command{
+	Use:     "timeline",
+	Aliases: []string{"tl"},
+	Short:   "Browse journal entries grouped by day, with the gaps between them.",
+	Args:    cobra.NoArgs,
+	RunE:    showTimeline,
+}
+
+// timelineDay is one day's entries, newest first.
+type timelineDay struct {
+	date  time.Time // midnight in the display timezone
+	posts []*client.Post
+}
+
+// groupByDay splits newest-first posts into days in loc.
+func groupByDay(posts []*client.Post, loc *time.Location) []timelineDay {
+	var days []timelineDay
+	for _, p := range posts {
+		t := p.CreatedAt.In(loc)
+		date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		if n := len(days); n > 0 && days[n-1].date.Equal(date) {
+			days[n-1].posts = append(days[n-1].posts, p)
+			continue
+		}
+		days = append(days, timelineDay{date: date, posts: []*client.Post{p}})
+	}
+	return days
+}
+
+// gapBefore is the time between post i of day and the entry before it on
+// the same day; false for a day's first entry.
+func gapBefore(day timelineDay, i int) (time.Duration, bool) {
+	if i+1 >= len(day.posts) {
+		return 0, false
+	}
+	return day.posts[i].CreatedAt.Sub(day.posts[i+1].CreatedAt), true
+}
+
+// formatGap renders a gap compactly, e.g. "25m", "1h05m" or "2d3h".
+func formatGap(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "<1m"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
+
+// dayLabel names a day, using "Today" and "Yesterday" where they apply.
+func dayLabel(date, now time.Time) string {
+	label := date.Format("Monday, Jan 2 2006")
+	switch daysBetween(date, now.In(date.Location())) {
+	case 0:
+		return "Today · " + label
+	case 1:
+		return "Yesterday · " + label
+	}
+	return label
+}
+
+// entryLine renders one timeline entry: its time, the gap before it and its text.
+func entryLine(day timelineDay, i, width int) string {
+	p := day.posts[i]
+	gap := strings.Repeat(" ", 7)
+	if d, ok := gapBefore(day, i); ok {
+		style := timelineGapStyle
+		if d >= timelineLongGap {
+			style = timelineLongStyle
+		}
+		gap = style.Render(fmt.Sprintf("%-7s", "+"+formatGap(d)))
+	}
+	clock := p.CreatedAt.In(displayTime.loc).Format(displayTime.clock)
+	return fmt.Sprintf("%8s  %s %s", clock, gap, truncate(p.Text, max(width-22, 20)))
+}
+
+// parseJumpDate reads a date to jump to: "today", "yesterday", "2026-03-14",
+// "03-14" or "Mar 14". Dates without a year are the most recent such day.
+func parseJumpDate(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	y, m, d := now.Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+	switch s {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return t, nil
+	}
+	if s == "" {
+		return time.Time{}, fmt.Errorf("enter a date: today, yesterday, 2026-03-14 or Mar 14")
+	}
+	// Month names only parse title-cased.
+	title := strings.ToUpper(s[:1]) + s[1:]
+	for _, layout := range []string{"01-02", "Jan 2", "January 2"} {
+		t, err := time.ParseInLocation(layout, title, now.Location())
+		if err != nil {
+			continue
+		}
+		t = time.Date(y, t.Month(), t.Day(), 0, 0, 0, 0, now.Location())
+		if t.After(today) {
+			t = t.AddDate(-1, 0, 0)
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("can't read %q: use today, yesterday, 2026-03-14 or Mar 14", s)
+}
+
+// timelineRow is a line of the timeline: a day header (post < 0) or an entry.
+type timelineRow struct {
+	day, post int
+}
+
+type timelineLoadedMsg struct {
+	posts []*client.Post
+	err   error
+}
+
+func loadTimeline(ctx context.Context, j client.Journal, count int) tea.Cmd {
+	return func() tea.Msg {
+		posts, err := j.ListPosts(ctx, count)
+		return timelineLoadedMsg{posts: posts, err: err}
+	}
+}
+
+// timelineModel shows entries grouped under collapsible day headers.
+type timelineModel struct {
+	ctx     context.Context
+	journal client.Journal
+	count   int
+	now     func() time.Time
+
+	loading bool
+	err     error
+	more    bool // the last load filled count, so older entries may exist
+	days    []timelineDay
+	rows    []timelineRow
+
+	collapsed map[time.Time]bool
+	cursor    int
+	offset    int
+	width     int
+	height    int
+
+	jumping bool
+	input   textinput.Model
+	pending *time.Time // jump target waiting on older entries
+	status  string
+
+	selected *client.Post
+	quitting bool
+}
+
+func newTimelineModel(ctx context.Context, j client.Journal, count int) timelineModel {
+	in := textinput.New()
+	in.Prompt = "Jump to: "
+	in.Placeholder = "2026-03-14, Mar 14, yesterday"
+	return timelineModel{
+		ctx:       ctx,
+		journal:   j,
+		count:     count,
+		now:       time.Now,
+		loading:   true,
+		collapsed: map[time.Time]bool{},
+		width:     80,
+		height:    24,
+		input:     in,
+	}
+}
+
+func (m timelineModel) Init() tea.Cmd {
+	return loadTimeline(m.ctx, m.journal, m.count)
+}
+
+// buildRows lays out the visible rows from days and the collapsed set.
+func (m *timelineModel) buildRows() {
+	m.rows = nil
+	for d, day := range m.days {
+		m.rows = append(m.rows, timelineRow{day: d, post: -1})
+		if m.collapsed[day.date] {
+			continue
+		}
+		for i := range day.posts {
+			m.rows = append(m.rows, timelineRow{day: d, post: i})
+		}
+	}
+	m.cursor = min(m.cursor, max(len(m.rows)-1, 0))
+	m.scroll()
+}
+
+// visibleRows is how many rows fit between the title and the help line.
+func (m timelineModel) visibleRows() int {
+	return max(m.height-5, 3)
+}
+
+// scroll keeps the cursor on screen.
+func (m *timelineModel) scroll() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if n := m.visibleRows(); m.cursor >= m.offset+n {
+		m.offset = m.cursor - n + 1
+	}
+}
+
+// headerRow returns the row index of day d's header.
+func (m timelineModel) headerRow(d int) int {
+	for i, r := range m.rows {
+		if r.day == d && r.post < 0 {
+			return i
+		}
+	}
+	return 0
+}
+
+// setCollapsed collapses or expands day d, keeping the cursor on its header.
+func (m *timelineModel) setCollapsed(d int, collapsed bool) {
+	m.collapsed[m.days[d].date] = collapsed
+	m.buildRows()
+	m.cursor = m.headerRow(d)
+	m.scroll()
+}
+
+// jump moves to the newest day on or before target, loading older entries
+// first if target is past the oldest one loaded.
+func (m *timelineModel) jump(target time.Time) tea.Cmd {
+	if len(m.days) == 0 {
+		m.status = "No entries."
+		return nil
+	}
+	if oldest := m.days[len(m.days)-1].date; target.Before(oldest) && m.more {
+		m.pending = &target
+		m.loading = true
+		m.count += timelinePageSize
+		return loadTimeline(m.ctx, m.journal, m.count)
+	}
+	m.pending = nil
+	d := len(m.days) - 1
+	for i, day := range m.days {
+		if !day.date.After(target) {
+			d = i
+			break
+		}
+	}
+	m.status = ""
+	if !m.days[d].date.Equal(target) {
+		m.status = "No entries on " + target.Format("Jan 2 2006") + "; showing the nearest earlier day."
+		if target.Before(m.days[d].date) {
+			m.status = "No entries on or before " + target.Format("Jan 2 2006") + "."
+		}
+	}
+	m.setCollapsed(d, false)
+	m.offset = m.cursor
+	return nil
+}
+
+func (m timelineModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case timelineLoadedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.more = len(msg.posts) >= m.count
+		m.days = groupByDay(msg.posts, displayTime.loc)
+		m.buildRows()
+		if m.pending != nil {
+			return m, m.jump(*m.pending)
+		}
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.scroll()
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.jumping {
+			return m.updateJump(msg)
+		}
+		return m.updateKeys(msg)
+	}
+	return m, nil
+}
+
+func (m timelineModel) updateJump(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.jumping = false
+		m.input.Blur()
+		return m, nil
+	case tea.KeyEnter:
+		target, err := parseJumpDate(m.input.Value(), m.now().In(displayTime.loc))
+		if err != nil {
+			m.status = err.Error()
+			return m, nil
+		}
+		m.jumping = false
+		m.input.Blur()
+		return m, m.jump(target)
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m timelineModel) updateKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc", "ctrl+c":
+		m.quitting = true
+		return m, tea.Quit
+	}
+	if m.loading || len(m.rows) == 0 {
+		return m, nil
+	}
+	row := m.rows[m.cursor]
+	switch msg.String() {
+	case "up", "k":
+		m.cursor = max(m.cursor-1, 0)
+	case "down", "j":
+		m.cursor = min(m.cursor+1, len(m.rows)-1)
+	case "pgup":
+		m.cursor = max(m.cursor-m.visibleRows(), 0)
+	case "pgdown":
+		m.cursor = min(m.cursor+m.visibleRows(), len(m.rows)-1)
+	case "home":
+		m.cursor = 0
+	case "end":
+		m.cursor = len(m.rows) - 1
+	case "enter":
+		if row.post < 0 {
+			m.setCollapsed(row.day, !m.collapsed[m.days[row.day].date])
+			return m, nil
+		}
+		m.selected = m.days[row.day].posts[row.post]
+		m.quitting = true
+		return m, tea.Quit
+	case " ", "tab":
+		m.setCollapsed(row.day, !m.collapsed[m.days[row.day].date])
+	case "left", "h":
+		m.setCollapsed(row.day, true)
+	case "right", "l":
+		m.setCollapsed(row.day, false)
+	case "C":
+		for _, day := range m.days {
+			m.collapsed[day.date] = true
+		}
+		m.buildRows()
+		m.cursor = m.headerRow(row.day)
+	case "E":
+		clear(m.collapsed)
+		m.buildRows()
+		m.cursor = m.headerRow(row.day)
+	case "g", "/":
+		m.jumping = true
+		m.status = ""
+		m.input.SetValue("")
+		return m, m.input.Focus()
+	case "m":
+		if m.more {
+			m.loading = true
+			m.count += timelinePageSize
+			return m, loadTimeline(m.ctx, m.journal, m.count)
+		}
+		m.status = "All entries loaded."
+	}
+	m.scroll()
+	return m, nil
+}
+
+func (m timelineModel) View() string {
+	if m.quitting {
+		return ""
+	}
+	var s strings.Builder
+	s.WriteString(timelineHeaderStyle.Render("Timeline"))
+	s.WriteString("\n\n")
+	switch {
+	case m.err != nil:
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("Error: " + m.err.Error()))
+		s.WriteString("\n")
+		return docStyle.Render(s.String())
+	case m.loading && len(m.rows) == 0:
+		s.WriteString("Loading journal entries...\n")
+		return docStyle.Render(s.String())
+	case len(m.rows) == 0:
+		s.WriteString("No entries found.\n")
+		return docStyle.Render(s.String())
+	}
+
+	now := m.now()
+	end := min(m.offset+m.visibleRows(), len(m.rows))
+	for i := m.offset; i < end; i++ {
+		r := m.rows[i]
+		day := m.days[r.day]
+		var line string
+		if r.post < 0 {
+			arrow := "▾"
+			if m.collapsed[day.date] {
+				arrow = "▸"
+			}
+			line = timelineHeaderStyle.Render(fmt.Sprintf("%s %s", arrow, dayLabel(day.date, now))) +
+				timelineGapStyle.Render(fmt.Sprintf(" · %d %s", len(day.posts), plural(len(day.posts), "entry", "entries")))
+		} else {
+			line = entryLine(day, r.post, m.width)
+		}
+		if i == m.cursor {
+			line = timelineCursorStyle.Render("> ") + line
+		} else {
+			line = "  " + line
+		}
+		s.WriteString(line)
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	switch {
+	case m.jumping:
+		s.WriteString(m.input.View())
+	case m.loading:
+		s.WriteString("Loading older entries...")
+	case m.status != "":
+		s.WriteString(m.status)
+	default:
+		help := "↑/↓ move · enter open/toggle · ←/→ collapse/expand · C/E all · g jump · q quit"
+		if m.more {
+			help += " · m more"
+		}
+		s.WriteString(timelineHelpStyle.Render(help))
+	}
+	return docStyle.Render(s.String())
+}
+
+// plural picks the singular or plural form for n.
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+// printTimeline writes the timeline as plain text, for pipes and scripts.
+func printTimeline(w io.Writer, posts []*client.Post, now time.Time) {
+	for i, day := range groupByDay(posts, displayTime.loc) {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s (%d %s)\n", dayLabel(day.date, now), len(day.posts), plural(len(day.posts), "entry", "entries"))
+		for j, p := range day.posts {
+			gap := ""
+			if d, ok := gapBefore(day, j); ok {
+				gap = "+" + formatGap(d)
+			}
+			clock := p.CreatedAt.In(displayTime.loc).Format(displayTime.clock)
+			fmt.Fprintf(w, "%8s  %-7s %s\n", clock, gap, truncate(strings.TrimSpace(p.Text), 100))
+		}
+	}
+}
+
+func showTimeline(cmd *cobra.Command, _ []string) error {
+	count, _ := cmd.Flags().GetInt("limit")
+	if count <= 0 {
+		return fmt.Errorf("--limit must be positive")
+	}
+	if !isInteractive(cmd.OutOrStdout()) {
+		posts, err := journal.ListPosts(cmd.Context(), count)
+		if err != nil {
+			return err
+		}
+		printTimeline(cmd.OutOrStdout(), posts, time.Now())
+		return nil
+	}
+
+	final, err := tea.NewProgram(newTimelineModel(cmd.Context(), journal, count), tea.WithAltScreen()).Run()
+	if err != nil {
+		return err
+	}
+	if selected := final.(timelineModel).selected; selected != nil {
+		return displayPost(cmd, selected)
+	}
+	return nil
+}
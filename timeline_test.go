@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/icco/etu/client"
+	"github.com/icco/etu/client/fake"
+)
+
+func TestGroupByDay(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	at := func(day, hour, minute int) time.Time { return time.Date(2026, 3, day, hour, minute, 0, 0, loc) }
+	posts := []*client.Post{
+		{Text: "c", CreatedAt: at(14, 9, 45)},
+		{Text: "b", CreatedAt: at(14, 9, 20)},
+		{Text: "a", CreatedAt: at(13, 23, 30)},
+	}
+	// In UTC the 23:30 entry falls on the 14th too.
+	if days := groupByDay(posts, time.UTC); len(days) != 1 {
+		t.Errorf("UTC: got %d days, want 1", len(days))
+	}
+	days := groupByDay(posts, loc)
+	if len(days) != 2 || len(days[0].posts) != 2 || days[1].posts[0].Text != "a" {
+		t.Fatalf("days = %+v, want the 14th with two entries then the 13th", days)
+	}
+	if gap, ok := gapBefore(days[0], 0); !ok || gap != 25*time.Minute {
+		t.Errorf("gapBefore = %v, %v; want 25m", gap, ok)
+	}
+	if _, ok := gapBefore(days[0], 1); ok {
+		t.Error("first entry of a day should have no gap")
+	}
+}
+
+func TestFormatGap(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		30 * time.Second:              "<1m",
+		25 * time.Minute:              "25m",
+		65 * time.Minute:              "1h05m",
+		27*time.Hour + 10*time.Minute: "1d3h",
+	} {
+		if got := formatGap(d); got != want {
+			t.Errorf("formatGap(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestParseJumpDate(t *testing.T) {
+	now := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	for in, want := range map[string]time.Time{
+		"today":      day(2026, 3, 14),
+		"Yesterday":  day(2026, 3, 13),
+		"2025-12-25": day(2025, 12, 25),
+		"03-01":      day(2026, 3, 1),
+		"mar 2":      day(2026, 3, 2),
+		"December 1": day(2025, 12, 1),
+	} {
+		got, err := parseJumpDate(in, now)
+		if err != nil {
+			t.Errorf("parseJumpDate(%q): %v", in, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("parseJumpDate(%q) = %v, want %v", in, got, want)
+		}
+	}
+	for _, in := range []string{"", "soon", "2026-13-01"} {
+		if _, err := parseJumpDate(in, now); err == nil {
+			t.Errorf("parseJumpDate(%q): want error", in)
+		}
+	}
+}
+
+// timelineJournal has two entries a day for the four days up to now.
+func timelineJournal(now time.Time) *fake.Journal {
+	var posts []*client.Post
+	for d := range 4 {
+		day := now.AddDate(0, 0, -d)
+		for _, h := range []int{9, 11} {
+			at := time.Date(day.Year(), day.Month(), day.Day(), h, 0, 0, 0, time.Local)
+			posts = append(posts, &client.Post{Text: at.Format("Jan 2 15:04"), CreatedAt: at})
+		}
+	}
+	return fake.NewJournal(posts...)
+}
+
+func loadedTimeline(t *testing.T, j client.Journal, count int) timelineModel {
+	t.Helper()
+	useDisplayTime(t, timeFormat{loc: time.Local, layout: "2006-01-02 15:04", clock: "15:04", now: time.Now})
+	m := newTimelineModel(context.Background(), j, count)
+	updated, _ := m.Update(loadTimeline(context.Background(), j, count)())
+	return updated.(timelineModel)
+}
+
+// press sends keys to m, completing any load they start.
+func press(t *testing.T, m timelineModel, keys ...string) (timelineModel, tea.Cmd) {
+	t.Helper()
+	var cmd tea.Cmd
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		var updated tea.Model
+		updated, cmd = m.Update(msg)
+		m = updated.(timelineModel)
+		for m.loading && cmd != nil {
+			updated, cmd = m.Update(cmd())
+			m = updated.(timelineModel)
+		}
+	}
+	return m, cmd
+}
+
+func TestTimelineModelCollapseAndSelect(t *testing.T) {
+	now := time.Now()
+	m := loadedTimeline(t, timelineJournal(now), 100)
+	if len(m.days) != 4 || len(m.rows) != 12 {
+		t.Fatalf("got %d days and %d rows, want 4 and 12", len(m.days), len(m.rows))
+	}
+
+	m, _ = press(t, m, "enter") // collapse today
+	if len(m.rows) != 10 || !m.collapsed[m.days[0].date] {
+		t.Errorf("after collapsing: %d rows, want 10", len(m.rows))
+	}
+	m, _ = press(t, m, "E", "C")
+	if len(m.rows) != 4 {
+		t.Errorf("after collapsing all: %d rows, want 4", len(m.rows))
+	}
+	m, _ = press(t, m, "E", "down", "enter")
+	if m.selected == nil || m.selected != m.days[0].posts[0] {
+		t.Errorf("selected %v, want today's newest entry", m.selected)
+	}
+	if !strings.Contains(m.days[0].posts[0].Text, "11:00") {
+		t.Errorf("newest entry = %q, want the 11:00 one", m.days[0].posts[0].Text)
+	}
+}
+
+func TestTimelineModelJumpLoadsOlder(t *testing.T) {
+	now := time.Now()
+	m := loadedTimeline(t, timelineJournal(now), 2)
+	if !m.more || len(m.days) != 1 {
+		t.Fatalf("more = %v, days = %d; want a partial first page", m.more, len(m.days))
+	}
+
+	target := now.AddDate(0, 0, -3).Format("2006-01-02")
+	m, _ = press(t, m, append([]string{"g"}, strings.Split(target, "")...)...)
+	m, _ = press(t, m, "enter")
+	if m.jumping || m.loading {
+		t.Fatalf("jumping = %v, loading = %v after enter", m.jumping, m.loading)
+	}
+	row := m.rows[m.cursor]
+	if got := m.days[row.day].date.Format("2006-01-02"); row.post >= 0 || got != target {
+		t.Errorf("cursor on %+v (%s), want the header of %s", row, got, target)
+	}
+	if m.status != "" {
+		t.Errorf("status = %q", m.status)
+	}
+}
+
+func TestCommandTimelinePlain(t *testing.T) {
+	srv := startFakeBackend(t)
+	useDisplayTime(t, displayTime)
+	day := time.Now().AddDate(0, 0, -2)
+	at := func(h, m int) time.Time { return time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, time.Local) }
+	srv.AddNote("standup", at(9, 0))
+	srv.AddNote("deep work", at(9, 40))
+
+	out, err := runCLI(t, "", "timeline", "--clock", "24h")
+	if err != nil {
+		t.Fatalf("timeline: %v", err)
+	}
+	for _, want := range []string{day.Format("Monday, Jan 2 2006") + " (2 entries)", "09:40  +40m    deep work", "09:00          standup"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// clockLayouts are the times of day parseTime accepts.
+var clockLayouts = []string{"15:04", "3:04pm", "3pm"}
+
+// dateLayouts are the absolute dates and times parseTime accepts, read in
+// the local timezone unless they carry an offset.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04 -0700",
+	"2006-01-02 15:04 MST",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// parseTime reads a time relative to now: "now", a duration back such as
+// "-20m" or "1h30m ago", a time of day such as "09:30" or "5pm" with an
+// optional "today" or "yesterday", or a date such as "2026-03-14 09:30".
+// s must be lowercase. Times without an offset are in now's timezone.
+func parseTime(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, fmt.Errorf("empty time")
+	}
+	if s == "now" {
+		return now, nil
+	}
+	if ago, ok := strings.CutSuffix(s, " ago"); ok {
+		s = "-" + strings.TrimSpace(ago)
+	}
+	if strings.HasPrefix(s, "-") {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("use a duration like -20m or 1h30m ago")
+		}
+		return now.Add(d), nil
+	}
+
+	day := now
+	if rest, ok := strings.CutPrefix(s, "yesterday"); ok {
+		day, s = now.AddDate(0, 0, -1), strings.TrimSpace(rest)
+	} else if rest, ok := strings.CutPrefix(s, "today"); ok {
+		s = strings.TrimSpace(rest)
+	}
+	if s == "" {
+		return time.Time{}, fmt.Errorf("add a time of day, e.g. yesterday 17:00")
+	}
+	for _, layout := range clockLayouts {
+		if c, err := time.Parse(layout, s); err == nil {
+			return time.Date(day.Year(), day.Month(), day.Day(), c.Hour(), c.Minute(), 0, 0, now.Location()), nil
+		}
+	}
+	if day.Equal(now) {
+		for _, layout := range dateLayouts {
+			if t, err := time.ParseInLocation(layout, strings.ToUpper(s), now.Location()); err == nil {
+				return t, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf(`use "-20m", "09:30", "yesterday 17:00" or "2006-01-02 15:04"`)
+}
+
+// parseBound reads a --since or --until value: a weekday such as "monday"
+// for its most recent occurrence, a count of days or weeks back such as
+// "30d" or "2w", a day as etu timeline's jump accepts, or an exact time as
+// parseTime accepts. day reports whether only a day was given.
+func parseBound(value string, now time.Time) (t time.Time, day bool, err error) {
+	s := strings.ToLower(strings.TrimSpace(value))
+	today := startOfDay(now, now.Location())
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		name := strings.ToLower(wd.String())
+		if s == name || s == name[:3] {
+			back := (int(today.Weekday()) - int(wd) + 7) % 7
+			return today.AddDate(0, 0, -back), true, nil
+		}
+	}
+	if n, unit := strings.TrimRight(s, "dw"), strings.TrimLeft(s, "0123456789"); n != "" && (unit == "d" || unit == "w") {
+		if days, err := strconv.Atoi(n); err == nil {
+			if unit == "w" {
+				days *= 7
+			}
+			return today.AddDate(0, 0, -days), true, nil
+		}
+	}
+	if t, err := parseJumpDate(s, now); err == nil {
+		return t, true, nil
+	}
+	if t, err := parseTime(s, now); err == nil {
+		return t, false, nil
+	}
+	return time.Time{}, false, fmt.Errorf(`use "monday", "30d", "2026-03-14", "Mar 14" or "yesterday 17:00"`)
+}
+
+// rangeFlags parses --since and --until into [from, to); zero bounds are
+// open. A day given to --until includes that whole day.
+func rangeFlags(cmd *cobra.Command, now time.Time) (from, to time.Time, err error) {
+	if v, _ := cmd.Flags().GetString("since"); v != "" {
+		if from, _, err = parseBound(v, now); err != nil {
+			return from, to, fmt.Errorf("invalid --since %q: %w", v, err)
+		}
+	}
+	if v, _ := cmd.Flags().GetString("until"); v != "" {
+		var day bool
+		if to, day, err = parseBound(v, now); err != nil {
+			return from, to, fmt.Errorf("invalid --until %q: %w", v, err)
+		}
+		if day {
+			to = to.AddDate(0, 0, 1)
+		}
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return from, to, fmt.Errorf("--since must be before --until")
+	}
+	return from, to, nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	zone := time.FixedZone("PDT", -7*60*60)
+	now := time.Date(2026, 3, 14, 10, 0, 0, 0, zone)
+	for _, tc := range []struct {
+		in      string
+		want    time.Time
+		wantErr string
+	}{
+		{in: "now", want: now},
+		{in: "-20m", want: now.Add(-20 * time.Minute)},
+		{in: "1h30m ago", want: now.Add(-90 * time.Minute)},
+		{in: "09:30", want: time.Date(2026, 3, 14, 9, 30, 0, 0, zone)},
+		{in: "today 9:05", want: time.Date(2026, 3, 14, 9, 5, 0, 0, zone)},
+		{in: "Yesterday 17:00", want: time.Date(2026, 3, 13, 17, 0, 0, 0, zone)},
+		{in: "yesterday 5pm", want: time.Date(2026, 3, 13, 17, 0, 0, 0, zone)},
+		{in: "2026-03-01 08:15", want: time.Date(2026, 3, 1, 8, 15, 0, 0, zone)},
+		{in: "2026-03-01", want: time.Date(2026, 3, 1, 0, 0, 0, 0, zone)},
+		{in: "2026-03-14T16:00:00Z", want: time.Date(2026, 3, 14, 16, 0, 0, 0, time.UTC)},
+		{in: "2026-03-14 09:00 +0100", want: time.Date(2026, 3, 14, 8, 0, 0, 0, time.UTC)},
+		{in: "10:00:30", wantErr: "use"},
+		{in: "", wantErr: "empty"},
+		{in: "yesterday", wantErr: "time of day"},
+		{in: "-soon", wantErr: "duration"},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := parseTime(strings.ToLower(tc.in), now)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("parseTime(%q) error = %v, want %q", tc.in, err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTime(%q): %v", tc.in, err)
+			}
+			if !got.Equal(tc.want) {
+				t.Errorf("parseTime(%q) = %v, want %v", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseBound(t *testing.T) {
+	// A Thursday.
+	now := time.Date(2026, 3, 12, 15, 0, 0, 0, time.UTC)
+	day := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 0, 0, 0, 0, time.UTC) }
+	for _, tc := range []struct {
+		in      string
+		want    time.Time
+		wantDay bool
+	}{
+		{"monday", day(3, 9), true},
+		{"Thu", day(3, 12), true},
+		{"friday", day(3, 6), true},
+		{"30d", day(2, 10), true},
+		{"2w", day(2, 26), true},
+		{"2026-01-05", day(1, 5), true},
+		{"Mar 1", day(3, 1), true},
+		{"yesterday 17:00", time.Date(2026, 3, 11, 17, 0, 0, 0, time.UTC), false},
+	} {
+		got, isDay, err := parseBound(tc.in, now)
+		if err != nil {
+			t.Errorf("parseBound(%q): %v", tc.in, err)
+			continue
+		}
+		if !got.Equal(tc.want) || isDay != tc.wantDay {
+			t.Errorf("parseBound(%q) = %v, %v; want %v, %v", tc.in, got, isDay, tc.want, tc.wantDay)
+		}
+	}
+	for _, bad := range []string{"", "someday", "3x"} {
+		if _, _, err := parseBound(bad, now); err == nil {
+			t.Errorf("parseBound(%q): want error", bad)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/icco/etu/client"
+	"github.com/spf13/cobra"
+)
+
+var timesheetCmd = &cobra.Command{
+	Use:   "timesheet",
+	Short: "Estimate time spent per day and activity from the gaps between entries.",
+	Long: `Treat each entry as the start of an activity that lasts until the next
+entry, and total the time per day and per activity. An activity is the
+entry's first tag, or its first word with --by word.
+
+Gaps longer than --idle count as time away rather than time on the
+activity. The latest entry runs until now if that is within --idle.`,
+	Example: `  etu timesheet
+  etu timesheet --since monday --by word
+  etu timesheet --since 2026-03-01 --until 2026-03-31 --format csv > march.csv`,
+	Args: cobra.NoArgs,
+	RunE: runTimesheet,
+}
+
+// timesheetFormats are the --format values etu timesheet accepts.
+var timesheetFormats = []string{"table", "csv", "json"}
+
+// activitySpan is the time from an entry until the next one.
+type activitySpan struct {
+	start    time.Time
+	activity string
+	dur      time.Duration
+}
+
+// activityName is what --by makes of post: its first tag, or the first
+// word of its text.
+func activityName(post *client.Post, by string) string {
+	if by == "word" {
+		for _, w := range strings.Fields(firstLine(post.Text)) {
+			w = strings.ToLower(strings.TrimFunc(w, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }))
+			if w != "" {
+				return w
+			}
+		}
+		return "(empty)"
+	}
+	if len(post.Tags) == 0 {
+		return "untagged"
+	}
+	return post.Tags[0]
+}
+
+// activitySpans turns newest-first posts into spans, oldest first. Each
+// lasts until the next entry, or until end for the latest, and spans longer
+// than idle count as nothing.
+func activitySpans(posts []*client.Post, by string, idle time.Duration, end time.Time) []activitySpan {
+	spans := make([]activitySpan, 0, len(posts))
+	for i := len(posts) - 1; i >= 0; i-- {
+		next := end
+		if i > 0 {
+			next = posts[i-1].CreatedAt
+		}
+		dur := next.Sub(posts[i].CreatedAt)
+		if dur > idle || dur < 0 {
+			dur = 0
+		}
+		spans = append(spans, activitySpan{start: posts[i].CreatedAt, activity: activityName(posts[i], by), dur: dur})
+	}
+	return spans
+}
+
+// activityTime is time spent on an activity.
+type activityTime struct {
+	Activity string `json:"activity"`
+	Seconds  int64  `json:"seconds"`
+	Duration string `json:"duration"`
+}
+
+// timesheetDay is a day's time per activity, most time first.
+type timesheetDay struct {
+	Date       string         `json:"date"`
+	Activities []activityTime `json:"activities"`
+	Seconds    int64          `json:"seconds"`
+	Duration   string         `json:"duration"`
+}
+
+// timesheet is time per day and per activity over a range.
+type timesheet struct {
+	Days     []timesheetDay `json:"days"`
+	Totals   []activityTime `json:"totals"`
+	Seconds  int64          `json:"seconds"`
+	Duration string         `json:"duration"`
+}
+
+// activityTimes sorts per-activity durations, most time first.
+func activityTimes(durs map[string]time.Duration) ([]activityTime, time.Duration) {
+	out := []activityTime{}
+	var total time.Duration
+	for name, d := range durs {
+		out = append(out, activityTime{Activity: name, Seconds: int64(d.Seconds()), Duration: formatDuration(d)})
+		total += d
+	}
+	sort.Slice(out, func(a, b int) bool {
+		if out[a].Seconds != out[b].Seconds {
+			return out[a].Seconds > out[b].Seconds
+		}
+		return out[a].Activity < out[b].Activity
+	})
+	return out, total
+}
+
+// newTimesheet totals spans per day in loc and per activity.
+func newTimesheet(spans []activitySpan, loc *time.Location) timesheet {
+	var days []string
+	byDay := map[string]map[string]time.Duration{}
+	totals := map[string]time.Duration{}
+	for _, s := range spans {
+		day := s.start.In(loc).Format("2006-01-02")
+		if byDay[day] == nil {
+			byDay[day] = map[string]time.Duration{}
+			days = append(days, day)
+		}
+		byDay[day][s.activity] += s.dur
+		totals[s.activity] += s.dur
+	}
+	ts := timesheet{Days: []timesheetDay{}}
+	for _, day := range days {
+		acts, total := activityTimes(byDay[day])
+		ts.Days = append(ts.Days, timesheetDay{Date: day, Activities: acts, Seconds: int64(total.Seconds()), Duration: formatDuration(total)})
+	}
+	var total time.Duration
+	ts.Totals, total = activityTimes(totals)
+	ts.Seconds, ts.Duration = int64(total.Seconds()), formatDuration(total)
+	return ts
+}
+
+// printTimesheet writes ts as a table: each day's activities, then totals.
+func printTimesheet(w io.Writer, ts timesheet) error {
+	width := len("total")
+	for _, a := range ts.Totals {
+		width = max(width, len(a.Activity))
+	}
+	row := func(name, dur string) { fmt.Fprintf(w, "  %-*s  %6s\n", width, name, dur) }
+	for _, day := range ts.Days {
+		date, err := time.Parse("2006-01-02", day.Date)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, date.Format("Mon Jan 2 2006"))
+		for _, a := range day.Activities {
+			row(a.Activity, a.Duration)
+		}
+		row("total", day.Duration)
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w, "Totals")
+	for _, a := range ts.Totals {
+		row(a.Activity, a.Duration)
+	}
+	row("total", ts.Duration)
+	return nil
+}
+
+// writeTimesheetCSV writes one row per day and activity.
+func writeTimesheetCSV(w io.Writer, ts timesheet) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"date", "activity", "seconds", "duration"}); err != nil {
+		return err
+	}
+	for _, day := range ts.Days {
+		for _, a := range day.Activities {
+			if err := cw.Write([]string{day.Date, a.Activity, strconv.FormatInt(a.Seconds, 10), a.Duration}); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func runTimesheet(cmd *cobra.Command, _ []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	if !slices.Contains(timesheetFormats, format) {
+		return fmt.Errorf("unknown format %q: use %s", format, strings.Join(timesheetFormats, ", "))
+	}
+	by, _ := cmd.Flags().GetString("by")
+	if by != "tag" && by != "word" {
+		return fmt.Errorf("unknown --by %q: use tag or word", by)
+	}
+	idle, _ := cmd.Flags().GetDuration("idle")
+	if idle <= 0 {
+		return fmt.Errorf("--idle must be positive")
+	}
+	now := time.Now().In(displayTime.loc)
+	from, to, err := rangeFlags(cmd, now)
+	if err != nil {
+		return err
+	}
+
+	// List up to now, not just to --until, so the last span in range ends at
+	// the next entry even when that entry is past the range.
+	posts, _, err := client.ListPostsBetween(cmd.Context(), journal, from, time.Time{})
+	if err != nil {
+		return err
+	}
+	spans := activitySpans(posts, by, idle, now)
+	if !to.IsZero() {
+		n := 0
+		for n < len(spans) && spans[n].start.Before(to) {
+			n++
+		}
+		spans = spans[:n]
+	}
+	ts := newTimesheet(spans, displayTime.loc)
+
+	w := cmd.OutOrStdout()
+	switch format {
+	case "csv":
+		return writeTimesheetCSV(w, ts)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(ts)
+	}
+	if len(ts.Days) == 0 {
+		fmt.Fprintln(w, "No entries in that range.")
+		return nil
+	}
+	return printTimesheet(w, ts)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/icco/etu/client"
+)
+
+func TestActivitySpansAndTimesheet(t *testing.T) {
+	at := func(d, h, m int) time.Time { return time.Date(2026, 3, d, h, m, 0, 0, time.UTC) }
+	// Newest first, as the backend lists them.
+	posts := []*client.Post{
+		{Text: "Review: PRs", CreatedAt: at(10, 9, 30)},
+		{Text: "home stuff", Tags: []string{"home"}, CreatedAt: at(9, 18, 0)},
+		{Text: "lunch", CreatedAt: at(9, 12, 0)},
+		{Text: "Writing the report", Tags: []string{"work", "writing"}, CreatedAt: at(9, 9, 0)},
+	}
+
+	spans := activitySpans(posts, "tag", 2*time.Hour, at(10, 10, 0))
+	var got []string
+	for _, s := range spans {
+		got = append(got, s.activity+" "+s.dur.String())
+	}
+	// 09:00→12:00 and 12:00→18:00 are over the idle cutoff; the latest entry runs until end.
+	want := "work 0s|untagged 0s|home 0s|untagged 30m0s"
+	if strings.Join(got, "|") != want {
+		t.Errorf("spans = %v, want %v", got, want)
+	}
+
+	spans = activitySpans(posts, "word", 8*time.Hour, at(10, 10, 0))
+	ts := newTimesheet(spans, time.UTC)
+	if len(ts.Days) != 2 || ts.Days[0].Date != "2026-03-09" || ts.Days[1].Date != "2026-03-10" {
+		t.Fatalf("days = %+v", ts.Days)
+	}
+	day := ts.Days[0]
+	if len(day.Activities) != 3 || day.Activities[0].Activity != "lunch" || day.Activities[0].Duration != "6.0h" ||
+		day.Activities[1].Activity != "writing" || day.Activities[2].Seconds != 0 || day.Duration != "9.0h" {
+		t.Errorf("Mar 9 = %+v, want lunch 6h, writing 3h, then home with none", day)
+	}
+	if ts.Days[1].Activities[0].Activity != "review" || ts.Duration != "9.5h" || ts.Seconds != int64((9*time.Hour+30*time.Minute).Seconds()) {
+		t.Errorf("timesheet = %+v", ts)
+	}
+}
+
+func TestCommandTimesheetUntilEndsAtNextEntry(t *testing.T) {
+	srv := startFakeBackend(t)
+	useDisplayTime(t, timeFormat{loc: time.UTC, layout: "2006-01-02 15:04", clock: "15:04", now: time.Now})
+	srv.AddNote("late fix", time.Date(2026, 3, 9, 23, 30, 0, 0, time.UTC), "work")
+	srv.AddNote("after midnight", time.Date(2026, 3, 10, 0, 15, 0, 0, time.UTC), "break")
+
+	out, err := runCLI(t, "", "timesheet", "--since", "2026-03-09", "--until", "2026-03-09", "--format", "csv")
+	if err != nil {
+		t.Fatalf("timesheet: %v", err)
+	}
+	// The span runs to the next entry, not to the end of --until.
+	if want := "date,activity,seconds,duration\n2026-03-09,work,2700,0.8h\n"; out != want {
+		t.Errorf("csv =\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestCommandTimesheet(t *testing.T) {
+	srv := startFakeBackend(t)
+	useDisplayTime(t, timeFormat{loc: time.UTC, layout: "2006-01-02 15:04", clock: "15:04", now: time.Now})
+	at := func(h, m int) time.Time { return time.Date(2026, 3, 9, h, m, 0, 0, time.UTC) }
+	srv.AddNote("standup", at(9, 0), "work")
+	srv.AddNote("deep work", at(9, 30), "work")
+	srv.AddNote("coffee", at(11, 0), "break")
+	srv.AddNote("next day", at(34, 0), "work")
+
+	args := []string{"timesheet", "--since", "2026-03-09", "--until", "2026-03-09"}
+	out, err := runCLI(t, "", args...)
+	if err != nil {
+		t.Fatalf("timesheet: %v", err)
+	}
+	want := "Mon Mar 9 2026\n  work     2.0h\n  break    0.0h\n  total    2.0h\n\nTotals\n  work     2.0h\n  break    0.0h\n  total    2.0h\n"
+	if out != want {
+		t.Errorf("table =\n%s\nwant:\n%s", out, want)
+	}
+
+	out, err = runCLI(t, "", append(args, "--format", "csv")...)
+	if err != nil {
+		t.Fatalf("timesheet csv: %v", err)
+	}
+	if want := "date,activity,seconds,duration\n2026-03-09,work,7200,2.0h\n2026-03-09,break,0,0.0h\n"; out != want {
+		t.Errorf("csv =\n%s\nwant:\n%s", out, want)
+	}
+
+	out, err = runCLI(t, "", append(args, "--format", "json", "--idle", "30m")...)
+	if err != nil {
+		t.Fatalf("timesheet json: %v", err)
+	}
+	var ts timesheet
+	if err := json.Unmarshal([]byte(out), &ts); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if ts.Seconds != 1800 || len(ts.Days) != 1 || ts.Totals[0].Activity != "work" {
+		t.Errorf("json with --idle 30m = %+v, want 30 minutes of work", ts)
+	}
+
+	if _, err := runCLI(t, "", "timesheet", "--format", "xml"); err == nil {
+		t.Error("timesheet --format xml: want error")
+	}
+	if _, err := runCLI(t, "", "timesheet", "--by", "mood"); err == nil {
+		t.Error("timesheet --by mood: want error")
+	}
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/icco/etu/client"
+	"github.com/spf13/cobra"
+)
+
+var todoCmd = &cobra.Command{
+	Use:   "todo",
+	Short: "List open action items from your entries and tick them off.",
+	Long: `List the action items in your entries: Markdown checkboxes ("- [ ] call
+the bank") and lines starting with "TODO:". Each shows the entry it came
+from. In a terminal, x or space ticks an item off by rewriting its entry:
+"- [ ]" becomes "- [x]" and "TODO:" becomes "DONE:".`,
+	Example: `  etu todo
+  etu todo --since 2w --tag work
+  etu todo --all | grep -c DONE`,
+	Args: cobra.NoArgs,
+	RunE: runTodo,
+}
+
+var (
+	// checkboxPattern matches a Markdown task list item.
+	checkboxPattern = regexp.MustCompile(`^(\s*[-*+]\s+)\[([ xX])\]\s+(.*)$`)
+	// todoPattern matches a TODO: or DONE: line, optionally as a list item.
+	todoPattern = regexp.MustCompile(`^(\s*(?:[-*+]\s+)?)(TODO|DONE):\s*(.*)$`)
+
+	todoDoneStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Strikethrough(true)
+)
+
+// todoItem is an action item on one line of an entry.
+type todoItem struct {
+	post *client.Post
+	line int // index of the line in post.Text
+	raw  string
+	text string
+	done bool
+}
+
+// parseTodo reads line as an action item.
+func parseTodo(line string) (text string, done, ok bool) {
+	if m := checkboxPattern.FindStringSubmatch(line); m != nil {
+		return strings.TrimSpace(m[3]), m[2] != " ", true
+	}
+	if m := todoPattern.FindStringSubmatch(line); m != nil {
+		return strings.TrimSpace(m[3]), m[2] == "DONE", true
+	}
+	return "", false, false
+}
+
+// toggleTodo returns line with its item ticked off, or reopened if done.
+func toggleTodo(line string) string {
+	if m := checkboxPattern.FindStringSubmatch(line); m != nil {
+		mark := "x"
+		if m[2] != " " {
+			mark = " "
+		}
+		return m[1] + "[" + mark + "] " + m[3]
+	}
+	if m := todoPattern.FindStringSubmatch(line); m != nil {
+		word := "DONE"
+		if m[2] == "DONE" {
+			word = "TODO"
+		}
+		return m[1] + word + ": " + m[3]
+	}
+	return line
+}
+
+// findTodos returns the action items in posts, in the order given.
+func findTodos(posts []*client.Post) []todoItem {
+	var items []todoItem
+	for _, p := range posts {
+		for i, line := range strings.Split(p.Text, "\n") {
+			if text, done, ok := parseTodo(line); ok && text != "" {
+				items = append(items, todoItem{post: p, line: i, raw: line, text: text, done: done})
+			}
+		}
+	}
+	return items
+}
+
+// setTodoLine rewrites the item's line in the entry's current text. It
+// returns an error if the line has since been changed or removed.
+func setTodoLine(text string, item todoItem, line string) (string, error) {
+	lines := strings.Split(text, "\n")
+	i := item.line
+	if i >= len(lines) || lines[i] != item.raw {
+		i = -1
+		for j, l := range lines {
+			if l == item.raw {
+				i = j
+				break
+			}
+		}
+	}
+	if i < 0 {
+		return "", fmt.Errorf("entry %s has changed since it was loaded", item.post.PageID)
+	}
+	lines[i] = line
+	return strings.Join(lines, "\n"), nil
+}
+
+type todoToggledMsg struct {
+	index int
+	post  *client.Post
+	line  string
+	err   error
+}
+
+// saveToggle ticks off or reopens item in its entry via UpdatePost.
+func saveToggle(ctx context.Context, j client.Journal, index int, item todoItem) tea.Cmd {
+	return func() tea.Msg {
+		current, err := j.GetPost(ctx, item.post.PageID)
+		if err != nil {
+			return todoToggledMsg{index: index, err: err}
+		}
+		line := toggleTodo(item.raw)
+		text, err := setTodoLine(current.Text, item, line)
+		if err != nil {
+			return todoToggledMsg{index: index, err: err}
+		}
+		updated, err := j.UpdatePost(ctx, item.post.PageID, text)
+		return todoToggledMsg{index: index, post: updated, line: line, err: err}
+	}
+}
+
+// todoModel lists action items; x or space ticks the one under the cursor
+// off, and ticked items stay listed until etu todo is run again.
+type todoModel struct {
+	ctx     context.Context
+	journal client.Journal
+	items   []todoItem
+	cursor  int
+	saving  bool
+	status  string
+	open    *client.Post
+	height  int
+
+	ticked, reopened int
+}
+
+func newTodoModel(ctx context.Context, j client.Journal, items []todoItem) todoModel {
+	return todoModel{ctx: ctx, journal: j, items: items, height: 24}
+}
+
+func (m todoModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m todoModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		return m, nil
+
+	case todoToggledMsg:
+		m.saving = false
+		if msg.err != nil {
+			m.status = "Saving failed: " + msg.err.Error()
+			return m, nil
+		}
+		item := &m.items[msg.index]
+		item.raw = msg.line
+		item.done = !item.done
+		if item.done {
+			m.ticked++
+			m.status = "Ticked off in " + item.post.PageID + "."
+		} else {
+			m.reopened++
+			m.status = "Reopened in " + item.post.PageID + "."
+		}
+		// Later toggles in the same entry start from the saved text.
+		for i := range m.items {
+			if m.items[i].post.PageID == msg.post.PageID {
+				m.items[i].post = msg.post
+			}
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "up", "k":
+			m.cursor = max(m.cursor-1, 0)
+		case "down", "j":
+			m.cursor = min(m.cursor+1, max(len(m.items)-1, 0))
+		case "home", "g":
+			m.cursor = 0
+		case "end", "G":
+			m.cursor = max(len(m.items)-1, 0)
+		case "x", " ":
+			if len(m.items) == 0 || m.saving {
+				return m, nil
+			}
+			m.saving = true
+			m.status = "Saving..."
+			return m, saveToggle(m.ctx, m.journal, m.cursor, m.items[m.cursor])
+		case "enter", "o":
+			if len(m.items) > 0 {
+				m.open = m.items[m.cursor].post
+				return m, tea.Quit
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m todoModel) View() string {
+	if m.open != nil {
+		return ""
+	}
+	var s strings.Builder
+	s.WriteString(timelineHeaderStyle.Render(fmt.Sprintf("Action items (%d open)", openTodos(m.items))))
+	s.WriteString("\n\n")
+	if len(m.items) == 0 {
+		s.WriteString("Nothing to do.\n\n")
+		s.WriteString(timelineHelpStyle.Render("q quit"))
+		return docStyle.Render(s.String())
+	}
+	// Keep the cursor in view, leaving room for the header and help.
+	rows := max(m.height-8, 1)
+	start := min(max(m.cursor-rows/2, 0), max(len(m.items)-rows, 0))
+	for i := start; i < min(start+rows, len(m.items)); i++ {
+		item := m.items[i]
+		box := "[ ] "
+		text := item.text
+		if item.done {
+			box = "[x] "
+			text = todoDoneStyle.Render(text)
+		}
+		line := box + text + timelineGapStyle.Render("  "+formatTime(item.post.CreatedAt))
+		if i == m.cursor {
+			s.WriteString(timelineCursorStyle.Render("> ") + line)
+		} else {
+			s.WriteString("  " + line)
+		}
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+	s.WriteString(timelineHelpStyle.Render("x/space tick off or reopen · enter open entry · q quit"))
+	if m.status != "" {
+		s.WriteString("\n" + m.status)
+	}
+	return docStyle.Render(s.String())
+}
+
+// openTodos counts the items not yet done.
+func openTodos(items []todoItem) int {
+	n := 0
+	for _, item := range items {
+		if !item.done {
+			n++
+		}
+	}
+	return n
+}
+
+// printTodos writes one item per line with its entry, for pipes and scripts.
+func printTodos(w io.Writer, items []todoItem) {
+	for _, item := range items {
+		box := "[ ]"
+		if item.done {
+			box = "[x]"
+		}
+		fmt.Fprintf(w, "%s %s  (%s, %s)\n", box, item.text, item.post.PageID, formatTime(item.post.CreatedAt))
+	}
+}
+
+func runTodo(cmd *cobra.Command, _ []string) error {
+	from, to, err := rangeFlags(cmd, time.Now().In(displayTime.loc))
+	if err != nil {
+		return err
+	}
+	tags, _ := cmd.Flags().GetStringSlice("tag")
+	all, _ := cmd.Flags().GetBool("all")
+
+	posts, _, err := client.ListPostsBetween(cmd.Context(), journal, from, to)
+	if err != nil {
+		return err
+	}
+	var matching []*client.Post
+	for _, p := range posts {
+		if hasAnyTag(p, tags) {
+			matching = append(matching, p)
+		}
+	}
+	var items []todoItem
+	for _, item := range findTodos(matching) {
+		if all || !item.done {
+			items = append(items, item)
+		}
+	}
+
+	if !isInteractive(cmd.OutOrStdout()) {
+		printTodos(cmd.OutOrStdout(), items)
+		return nil
+	}
+	final, err := tea.NewProgram(newTodoModel(cmd.Context(), journal, items), tea.WithAltScreen()).Run()
+	if err != nil {
+		return err
+	}
+	m := final.(todoModel)
+	if m.ticked > 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "Ticked off %d %s.\n", m.ticked, plural(m.ticked, "item", "items"))
+	}
+	if m.reopened > 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "Reopened %d %s.\n", m.reopened, plural(m.reopened, "item", "items"))
+	}
+	if m.open != nil {
+		return displayPost(cmd, m.open)
+	}
+	return nil
+}
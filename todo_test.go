@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/icco/etu/client"
+	"github.com/icco/etu/client/fake"
+)
+
+func TestParseAndToggleTodo(t *testing.T) {
+	for _, tc := range []struct {
+		line    string
+		text    string
+		done    bool
+		ok      bool
+		toggled string
+	}{
+		{"- [ ] call the bank", "call the bank", false, true, "- [x] call the bank"},
+		{"  * [x] renew passport", "renew passport", true, true, "  * [ ] renew passport"},
+		{"+ [X] done too", "done too", true, true, "+ [ ] done too"},
+		{"TODO: book flights", "book flights", false, true, "DONE: book flights"},
+		{"- DONE: filed taxes", "filed taxes", true, true, "- TODO: filed taxes"},
+		{"todo: lowercase isn't an item", "", false, false, "todo: lowercase isn't an item"},
+		{"[ ] no list marker", "", false, false, "[ ] no list marker"},
+		{"- plain list item", "", false, false, "- plain list item"},
+	} {
+		text, done, ok := parseTodo(tc.line)
+		if text != tc.text || done != tc.done || ok != tc.ok {
+			t.Errorf("parseTodo(%q) = %q, %v, %v; want %q, %v, %v", tc.line, text, done, ok, tc.text, tc.done, tc.ok)
+		}
+		if got := toggleTodo(tc.line); got != tc.toggled {
+			t.Errorf("toggleTodo(%q) = %q, want %q", tc.line, got, tc.toggled)
+		}
+	}
+}
+
+func TestSetTodoLine(t *testing.T) {
+	post := &client.Post{PageID: "p", Text: "notes\n- [ ] first\n- [ ] second"}
+	items := findTodos([]*client.Post{post})
+	if len(items) != 2 || items[1].line != 2 {
+		t.Fatalf("findTodos = %+v", items)
+	}
+
+	got, err := setTodoLine(post.Text, items[1], "- [x] second")
+	if err != nil || got != "notes\n- [ ] first\n- [x] second" {
+		t.Errorf("setTodoLine = %q, %v", got, err)
+	}
+	// The line moved since the entry was loaded.
+	got, err = setTodoLine("added\nnotes\n- [ ] first\n- [ ] second", items[1], "- [x] second")
+	if err != nil || got != "added\nnotes\n- [ ] first\n- [x] second" {
+		t.Errorf("setTodoLine after a line was added = %q, %v", got, err)
+	}
+	if _, err := setTodoLine("notes\n- [ ] first", items[1], "- [x] second"); err == nil {
+		t.Error("setTodoLine with the line gone: want error")
+	}
+}
+
+func TestTodoModelTicksOff(t *testing.T) {
+	post := &client.Post{PageID: "p", Text: "- [ ] first\nTODO: second", Tags: []string{"work"}, CreatedAt: time.Now()}
+	j := fake.NewJournal(post)
+	m := newTodoModel(context.Background(), j, findTodos(j.Posts()))
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	updated, cmd := updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if cmd == nil {
+		t.Fatal("x should save")
+	}
+	updated, _ = updated.Update(cmd())
+	updated, cmd = updated.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if cmd != nil {
+		t.Fatal("moving shouldn't save")
+	}
+	updated, cmd = updated.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	updated, _ = updated.Update(cmd())
+	got := updated.(todoModel)
+
+	if got.ticked != 2 || openTodos(got.items) != 0 || got.status != "Ticked off in p." {
+		t.Errorf("ticked %d, %d open, status %q", got.ticked, openTodos(got.items), got.status)
+	}
+	if text := j.Posts()[0].Text; text != "- [x] first\nDONE: second" {
+		t.Errorf("entry text = %q", text)
+	}
+	if !strings.Contains(got.View(), "Action items (0 open)") {
+		t.Errorf("view:\n%s", got.View())
+	}
+}
+
+func TestCommandTodo(t *testing.T) {
+	srv := startFakeBackend(t)
+	useDisplayTime(t, timeFormat{loc: time.UTC, layout: "2006-01-02 15:04", clock: "15:04", now: time.Now})
+	at := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	id := srv.AddNote("standup\n- [ ] send notes\n- [x] book room\nTODO: fix CI", at, "work")
+	srv.AddNote("- [ ] buy milk", at.Add(time.Hour), "home")
+
+	out, err := runCLI(t, "", "todo", "--tag", "work")
+	if err != nil {
+		t.Fatalf("todo: %v", err)
+	}
+	want := "[ ] send notes  (" + id + ", 2026-03-10 09:00)\n[ ] fix CI  (" + id + ", 2026-03-10 09:00)\n"
+	if out != want {
+		t.Errorf("todo --tag work =\n%s\nwant:\n%s", out, want)
+	}
+
+	out, err = runCLI(t, "", "todo", "--all")
+	if err != nil {
+		t.Fatalf("todo --all: %v", err)
+	}
+	if !strings.Contains(out, "[x] book room") || !strings.Contains(out, "[ ] buy milk") {
+		t.Errorf("todo --all:\n%s", out)
+	}
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/icco/etu/client"
+	"github.com/spf13/cobra"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo [revision]",
+	Short: "Undo the last edit, tag change or delete.",
+	Long: `Undo the most recent edit, tag change or delete made with etu, or the
+numbered revision given. An edit gets its earlier content back and a tag
+change its earlier tags. A deleted entry is recreated with its text and
+tags under a new ID. It is dated now, since the backend can't set
+timestamps, and its attachments can't be restored.
+
+If the entry was changed again after the revision, undoing it would throw
+that change away, so etu asks first, or with --yes or no terminal refuses
+unless --force is given.
+
+Changes are kept in history.jsonl in the config directory. --list shows
+what can be undone.`,
+	Example: `  etu undo
+  etu undo --list
+  etu undo 12`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runUndo,
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history <id>",
+	Short: "Show an entry's earlier versions with diffs.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runHistory,
+}
+
+var (
+	diffAddStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("34"))
+	diffRemoveStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("160"))
+)
+
+// revisionLine describes a revision on one line.
+func revisionLine(r client.Revision) string {
+	what := r.Op
+	if r.Op == client.OpUndo {
+		what = fmt.Sprintf("undo #%d", r.Undoes)
+	}
+	return fmt.Sprintf("#%-4d %-8s %s  %s  %s", r.ID, what, r.PostID, formatTime(r.Time), truncate(firstLine(r.Content), 50))
+}
+
+// undoSummary says what an undo did.
+func undoSummary(target, undo client.Revision) string {
+	switch target.Op {
+	case client.OpEdit:
+		return fmt.Sprintf("Restored %s to its content before the edit at %s.\n", target.PostID, formatTime(target.Time))
+	case client.OpTags:
+		return fmt.Sprintf("Restored the tags of %s: %s.\n", target.PostID, tagList(target.Tags))
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Recreated deleted entry %s as %s.\n", target.PostID, undo.NewID)
+	if !undo.CreatedAt.Equal(target.CreatedAt) {
+		fmt.Fprintf(&b, "It was written %s, but the backend can't set timestamps, so it is dated now.\n", formatTime(target.CreatedAt))
+	}
+	if n := len(target.Attachments); n > 0 {
+		fmt.Fprintf(&b, "Its %d %s weren't restored:\n", n, plural(n, "attachment", "attachments"))
+		for _, url := range target.Attachments {
+			fmt.Fprintf(&b, "  %s\n", url)
+		}
+	}
+	return b.String()
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	revs, err := history.Revisions()
+	if err != nil {
+		return err
+	}
+	undoable := client.Undoable(revs)
+	w := cmd.OutOrStdout()
+	if list, _ := cmd.Flags().GetBool("list"); list {
+		if len(undoable) == 0 {
+			fmt.Fprintln(w, "Nothing to undo.")
+		}
+		for _, r := range undoable {
+			fmt.Fprintln(w, revisionLine(r))
+		}
+		return nil
+	}
+
+	id := 0
+	if len(args) == 1 {
+		if id, err = strconv.Atoi(strings.TrimPrefix(args[0], "#")); err != nil {
+			return fmt.Errorf("revision must be a number like 12, not %q", args[0])
+		}
+	}
+	i := slices.IndexFunc(undoable, func(r client.Revision) bool { return id == 0 || r.ID == id })
+	if i < 0 {
+		if id == 0 {
+			return client.ErrNothingToUndo
+		}
+		return fmt.Errorf("revision %d doesn't exist or is already undone", id)
+	}
+	target := undoable[i]
+
+	if yes, _ := cmd.Flags().GetBool("yes"); !yes && isInteractive(w) {
+		confirm := true
+		err := huh.NewForm(
+			huh.NewGroup(
+				huh.NewConfirm().
+					Title(fmt.Sprintf("Undo %s of %s from %s?", target.Op, target.PostID, formatTime(target.Time))).
+					Description(truncate(target.Content, 200)).
+					Value(&confirm),
+			),
+		).Run()
+		if err != nil || !confirm {
+			return err
+		}
+	}
+
+	force, _ := cmd.Flags().GetBool("force")
+	undo, err := history.Undo(cmd.Context(), target.ID, force)
+	if errors.Is(err, client.ErrChangedSince) {
+		if yes, _ := cmd.Flags().GetBool("yes"); yes || !isInteractive(w) {
+			return fmt.Errorf("%w; use --force to undo it anyway", err)
+		}
+		overwrite := false
+		err = huh.NewForm(
+			huh.NewGroup(
+				huh.NewConfirm().
+					Title(fmt.Sprintf("%s has changed since %s. Undo anyway?", target.PostID, formatTime(target.Time))).
+					Description("The later changes will be lost; etu history shows them.").
+					Value(&overwrite),
+			),
+		).Run()
+		if err != nil || !overwrite {
+			return err
+		}
+		undo, err = history.Undo(cmd.Context(), target.ID, true)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(w, undoSummary(target, undo))
+	return nil
+}
+
+// diffOp is one line of a line diff: ' ' kept, '-' removed or '+' added.
+type diffOp struct {
+	op   byte
+	line string
+}
+
+// lineDiff compares a and b line by line using their longest common
+// subsequence; entries are short enough for the quadratic table.
+func lineDiff(a, b string) []diffOp {
+	x, y := strings.Split(a, "\n"), strings.Split(b, "\n")
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			ops = append(ops, diffOp{' ', x[i]})
+			i, j = i+1, j+1
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', x[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', y[j]})
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		ops = append(ops, diffOp{'-', x[i]})
+	}
+	for ; j < len(y); j++ {
+		ops = append(ops, diffOp{'+', y[j]})
+	}
+	return ops
+}
+
+// writeDiff writes a line diff, indented.
+func writeDiff(w io.Writer, ops []diffOp) {
+	for _, d := range ops {
+		line := string(d.op) + " " + d.line
+		switch d.op {
+		case '-':
+			line = diffRemoveStyle.Render(line)
+		case '+':
+			line = diffAddStyle.Render(line)
+		}
+		fmt.Fprintf(w, "  %s\n", line)
+	}
+}
+
+// entryRevisions returns the revisions of id, following deletes that were
+// undone by recreating the entry under a new ID, and the ID it has now.
+func entryRevisions(revs []client.Revision, id string) ([]client.Revision, string) {
+	ids := map[string]bool{id: true}
+	for grew := true; grew; {
+		grew = false
+		for _, r := range revs {
+			if r.NewID != "" && ids[r.PostID] != ids[r.NewID] {
+				ids[r.PostID], ids[r.NewID] = true, true
+				grew = true
+			}
+		}
+	}
+	var out []client.Revision
+	latest := id
+	for _, r := range revs {
+		if ids[r.PostID] {
+			out = append(out, r)
+			if r.NewID != "" {
+				latest = r.NewID
+			}
+		}
+	}
+	return out, latest
+}
+
+// printHistory writes each revision and what changed after it, ending at
+// current, the entry as it is now, or nil if it is gone.
+func printHistory(w io.Writer, revs []client.Revision, current *client.Post) {
+	for i, r := range revs {
+		fmt.Fprintln(w, revisionLine(r))
+		switch {
+		case r.Op == client.OpDelete:
+			lines := strings.Count(r.Content, "\n") + 1
+			fmt.Fprintf(w, "  deleted (%d %s)\n", lines, plural(lines, "line", "lines"))
+		case r.NewID != "":
+			fmt.Fprintf(w, "  recreated as %s\n", r.NewID)
+		default:
+			var next client.Revision
+			if i+1 < len(revs) {
+				next = revs[i+1]
+			} else if current != nil {
+				next = client.Revision{Content: current.Text, Tags: current.Tags}
+			} else {
+				break
+			}
+			if r.Content != next.Content {
+				writeDiff(w, lineDiff(r.Content, next.Content))
+			}
+			if !slices.Equal(r.Tags, next.Tags) {
+				fmt.Fprintf(w, "  tags: %s -> %s\n", tagList(r.Tags), tagList(next.Tags))
+			}
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// tagList renders tags for history, "none" if there are none.
+func tagList(tags []string) string {
+	if len(tags) == 0 {
+		return "none"
+	}
+	return strings.Join(tags, ", ")
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	revs, err := history.Revisions()
+	if err != nil {
+		return err
+	}
+	revs, latest := entryRevisions(revs, args[0])
+	if len(revs) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "No local history for %s; only edits, tag changes and deletes made with etu are recorded.\n", args[0])
+		return nil
+	}
+	current, err := journal.GetPost(cmd.Context(), latest)
+	if err != nil && !client.IsNotFound(err) {
+		return err
+	}
+	printHistory(cmd.OutOrStdout(), revs, current)
+	return nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLineDiff(t *testing.T) {
+	got := lineDiff("a\nb\nc", "a\nc\nd")
+	want := []diffOp{{' ', "a"}, {'-', "b"}, {' ', "c"}, {'+', "d"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("lineDiff = %q, want %q", got, want)
+	}
+}
+
+func TestCommandUndoEdit(t *testing.T) {
+	srv := startFakeBackend(t)
+	id := srv.AddNote("plan\n- [ ] write", time.Now().Add(-time.Hour))
+	if _, err := journal.UpdatePost(context.Background(), id, "plan\n- [x] write\nshipped"); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCLI(t, "", "history", id)
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	for _, want := range []string{"#1    edit", "  - - [ ] write", "  + - [x] write", "  + shipped", "    plan"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("history missing %q:\n%s", want, out)
+		}
+	}
+
+	out, err = runCLI(t, "", "undo")
+	if err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+	if !strings.Contains(out, "Restored "+id) {
+		t.Errorf("undo = %q", out)
+	}
+	if got := srv.Notes()[0].GetContent(); got != "plan\n- [ ] write" {
+		t.Errorf("content after undo = %q", got)
+	}
+	if out, err := runCLI(t, "", "undo", "--list"); err != nil || out != "Nothing to undo.\n" {
+		t.Errorf("undo --list = %q, %v", out, err)
+	}
+}
+
+func TestCommandUndoChangedSince(t *testing.T) {
+	srv := startFakeBackend(t)
+	id := srv.AddNote("draft", time.Now().Add(-time.Hour))
+	if _, err := journal.UpdatePost(context.Background(), id, "final"); err != nil {
+		t.Fatal(err)
+	}
+	// An edit made without etu leaves no revision.
+	if _, err := cfg.UpdatePost(context.Background(), id, "final, with a typo fixed"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := runCLI(t, "", "undo"); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("undo = %v, want a changed-since error suggesting --force", err)
+	}
+	if got := srv.Notes()[0].GetContent(); got != "final, with a typo fixed" {
+		t.Fatalf("content after refused undo = %q", got)
+	}
+	if _, err := runCLI(t, "", "undo", "--force"); err != nil {
+		t.Fatalf("undo --force: %v", err)
+	}
+	if got := srv.Notes()[0].GetContent(); got != "draft" {
+		t.Errorf("content after undo --force = %q, want draft", got)
+	}
+}
+
+func TestCommandUndoDelete(t *testing.T) {
+	srv := startFakeBackend(t)
+	id := srv.AddNote("keep this", time.Now().Add(-24*time.Hour), "idea")
+	if err := journal.DeletePost(context.Background(), id); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCLI(t, "", "undo", "--list")
+	if err != nil || !strings.Contains(out, "delete") || !strings.Contains(out, id) {
+		t.Fatalf("undo --list = %q, %v", out, err)
+	}
+	out, err = runCLI(t, "", "undo", "1")
+	if err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+	notes := srv.Notes()
+	if len(notes) != 1 || notes[0].GetContent() != "keep this" || !reflect.DeepEqual(notes[0].GetTags(), []string{"idea"}) {
+		t.Fatalf("notes after undo = %v", notes)
+	}
+	newID := notes[0].GetId()
+	if !strings.Contains(out, "Recreated deleted entry "+id+" as "+newID) || !strings.Contains(out, "dated now") {
+		t.Errorf("undo = %q", out)
+	}
+
+	out, err = runCLI(t, "", "history", newID)
+	if err != nil || !strings.Contains(out, "deleted (1 line)") || !strings.Contains(out, "recreated as "+newID) {
+		t.Errorf("history of the recreated entry = %q, %v", out, err)
+	}
+	if _, err := runCLI(t, "", "undo"); err == nil {
+		t.Error("undo with nothing left: want error")
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/icco/etu/client"
+)
+
+type uploadProgressMsg client.UploadProgress
+
+type uploadDoneMsg struct {
+	err error
+}
+
+// uploadModel shows a spinner while an entry is saved, plus one bar per
+// attachment for reading and preparing it. The request itself has no bar.
+type uploadModel struct {
+	spinner spinner.Model
+	bar     progress.Model
+	paths   []string
+	state   map[string]client.UploadProgress
+	err     error
+	done    bool
+}
+
+func newUploadModel(paths []string) uploadModel {
+	sp := spinner.New()
+	sp.Spinner = spinner.Dot
+	sp.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("170"))
+	return uploadModel{
+		spinner: sp,
+		bar:     progress.New(progress.WithDefaultGradient(), progress.WithWidth(30)),
+		paths:   paths,
+		state:   map[string]client.UploadProgress{},
+	}
+}
+
+func (m uploadModel) Init() tea.Cmd {
+	return m.spinner.Tick
+}
+
+func (m uploadModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case uploadProgressMsg:
+		prev := m.state[msg.Path]
+		if msg.Note == "" {
+			msg.Note = prev.Note
+		}
+		m.state[msg.Path] = client.UploadProgress(msg)
+		return m, nil
+	case uploadDoneMsg:
+		m.err = msg.err
+		m.done = true
+		return m, tea.Quit
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// prepared reports whether every attachment has been fully read.
+func (m uploadModel) prepared() bool {
+	for _, p := range m.paths {
+		st, ok := m.state[p]
+		if !ok || st.Done < st.Total {
+			return false
+		}
+	}
+	return true
+}
+
+func (m uploadModel) View() string {
+	if m.done {
+		return ""
+	}
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	var b strings.Builder
+	title := "Reading attachments..."
+	if m.prepared() {
+		title = "Saving entry..."
+	}
+	b.WriteString(m.spinner.View() + " " + title + "\n")
+	for _, p := range m.paths {
+		st := m.state[p]
+		pct := 0.0
+		if st.Total > 0 {
+			pct = float64(st.Done) / float64(st.Total)
+		}
+		state := "reading"
+		if st.Total > 0 && st.Done >= st.Total {
+			state = "ready"
+		}
+		line := fmt.Sprintf("  %-24s %s %9s  %-7s", truncate(filepath.Base(p), 24), m.bar.ViewAs(pct), client.FormatBytes(st.Total), state)
+		if st.Note != "" {
+			line += labelStyle.Render(" " + st.Note)
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// saveWithProgress runs save with a bar on stderr for reading each
+// attachment, then a spinner while the entry is sent. When stderr is not a
+// terminal the save runs without any UI.
+func saveWithProgress(ctx context.Context, paths []string, save func(context.Context) error) error {
+	if !isInteractive(os.Stderr) {
+		return save(ctx)
+	}
+	p := tea.NewProgram(newUploadModel(paths), tea.WithOutput(os.Stderr), tea.WithInput(nil))
+	go func() {
+		err := save(client.WithUploadProgress(ctx, func(u client.UploadProgress) {
+			p.Send(uploadProgressMsg(u))
+		}))
+		p.Send(uploadDoneMsg{err: err})
+	}()
+	final, err := p.Run()
+	if err != nil {
+		return err
+	}
+	return final.(uploadModel).err
+}
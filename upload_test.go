@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/icco/etu/client"
+)
+
+func TestUploadModelProgress(t *testing.T) {
+	m := newUploadModel([]string{"/tmp/a.png", "/tmp/b.mp3"})
+	if m.prepared() {
+		t.Fatal("prepared before any progress")
+	}
+
+	updated, _ := m.Update(uploadProgressMsg(client.UploadProgress{Path: "/tmp/a.png", Done: 10, Total: 10}))
+	updated, _ = updated.Update(uploadProgressMsg(client.UploadProgress{Path: "/tmp/a.png", Done: 4, Total: 4, Note: "resized to 10x10"}))
+	updated, _ = updated.Update(uploadProgressMsg(client.UploadProgress{Path: "/tmp/b.mp3", Done: 5, Total: 20}))
+	um := updated.(uploadModel)
+	if um.prepared() {
+		t.Error("prepared while b.mp3 is still being read")
+	}
+	view := um.View()
+	if !strings.Contains(view, "a.png") || !strings.Contains(view, "resized to 10x10") || !strings.Contains(view, "reading") {
+		t.Errorf("view missing file or note:\n%s", view)
+	}
+
+	updated, _ = um.Update(uploadProgressMsg(client.UploadProgress{Path: "/tmp/b.mp3", Done: 20, Total: 20}))
+	um = updated.(uploadModel)
+	if !um.prepared() {
+		t.Error("not prepared after all files read")
+	}
+	if !strings.Contains(um.View(), "Saving entry...") {
+		t.Error("expected saving title once attachments are prepared")
+	}
+
+	wantErr := errors.New("boom")
+	updated, cmd := um.Update(uploadDoneMsg{err: wantErr})
+	if cmd == nil || updated.(uploadModel).err != wantErr {
+		t.Error("done message should record the error and quit")
+	}
+}